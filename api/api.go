@@ -1,44 +1,245 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"time"
 	"vocablo/api/auth"
+	"vocablo/api/deltasync"
+	"vocablo/api/export"
+	"vocablo/api/graphql"
+	"vocablo/api/health"
+	"vocablo/api/language"
+	"vocablo/api/mail"
+	"vocablo/api/notification"
+	"vocablo/api/openapi"
 	"vocablo/api/quiz"
+	"vocablo/api/stats"
+	"vocablo/api/user"
 	"vocablo/api/userword"
 	"vocablo/api/word"
 	"vocablo/conf"
+	"vocablo/metrics"
 	"vocablo/middleware"
+	"vocablo/rpc"
+	"vocablo/svc"
 
-	ginzerolog "github.com/dn365/gin-zerolog"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func Start() error {
+const defaultShutdownTimeout = 30 * time.Second
+
+// Start serves the API, and the gRPC services when enabled, until the context is done, then drains the in-flight
+// requests
+func Start(ctx context.Context) error {
 	conf := conf.Get()
-	r := GetRouter()
-	log.Info().Msg(fmt.Sprintf(conf.Port))
-	err := r.Run(fmt.Sprintf("%s:%s", conf.IP, conf.Port))
+	server, err := NewServer()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("address", server.Addr).Bool("tls", server.TLSConfig != nil).Msg("Server listening")
+	if conf.Grpc.Enabled {
+		//The gRPC server shuts down with the API, and is waited for before returning
+		grpcServer := rpc.NewServer(server.TLSConfig)
+		grpcListener, err := net.Listen("tcp", net.JoinHostPort(conf.IP, conf.Grpc.Port))
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		log.Info().Str("address", grpcListener.Addr().String()).Msg("gRPC server listening")
+		grpcCtx, stopGrpc := context.WithCancel(ctx)
+		grpcErr := make(chan error, 1)
+		go func() {
+			grpcErr <- rpc.Serve(grpcCtx, grpcServer, grpcListener, conf.Server.ShutdownTimeout)
+		}()
+		defer func() {
+			stopGrpc()
+			if grpcServeErr := <-grpcErr; grpcServeErr != nil {
+				log.Error().Err(grpcServeErr).Msg("Error serving gRPC")
+			}
+		}()
+	}
+	if !conf.Metrics.Enabled || conf.Metrics.Port == "" {
+		return Serve(ctx, server, listener, conf.Server.DrainDelay, conf.Server.ShutdownTimeout)
+	}
+
+	//The metrics are served until the API has drained, so its last requests are scraped
+	metricsServer := NewMetricsServer()
+	metricsListener, err := net.Listen("tcp", metricsServer.Addr)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	log.Info().Str("address", metricsServer.Addr).Msg("Metrics server listening")
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	metricsErr := make(chan error, 1)
+	go func() {
+		metricsErr <- Serve(metricsCtx, metricsServer, metricsListener, 0, conf.Server.ShutdownTimeout)
+	}()
+	err = Serve(ctx, server, listener, conf.Server.DrainDelay, conf.Server.ShutdownTimeout)
+	stopMetrics()
+	if metricsServeErr := <-metricsErr; metricsServeErr != nil {
+		log.Error().Err(metricsServeErr).Msg("Error serving the metrics")
+	}
 	return err
 }
 
+// NewMetricsServer creates the server of the separate metrics listener
+func NewMetricsServer() *http.Server {
+	serverConf := conf.Get().Server
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	return &http.Server{
+		Addr:              net.JoinHostPort(conf.Get().IP, conf.Get().Metrics.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: serverConf.ReadHeaderTimeout,
+		ReadTimeout:       serverConf.ReadTimeout,
+		WriteTimeout:      serverConf.WriteTimeout,
+		IdleTimeout:       serverConf.IdleTimeout,
+	}
+}
+
+// NewServer creates the HTTP server of the API with the configured timeouts and TLS
+func NewServer() (*http.Server, error) {
+	serverConf := conf.Get().Server
+	err := gin.New().SetTrustedProxies(serverConf.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	server := &http.Server{
+		Addr:              net.JoinHostPort(conf.Get().IP, conf.Get().Port),
+		Handler:           GetRouter(),
+		ReadHeaderTimeout: serverConf.ReadHeaderTimeout,
+		ReadTimeout:       serverConf.ReadTimeout,
+		WriteTimeout:      serverConf.WriteTimeout,
+		IdleTimeout:       serverConf.IdleTimeout,
+	}
+	tlsConf := serverConf.TLS
+	if tlsConf.CertFile == "" {
+		return server, nil
+	}
+	cert, err := tls.LoadX509KeyPair(conf.ResolvePath(tlsConf.CertFile), conf.ResolvePath(tlsConf.KeyFile))
+	if err != nil {
+		return nil, err
+	}
+	server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if tlsConf.MinVersion == "1.3" {
+		server.TLSConfig.MinVersion = tls.VersionTLS13
+	}
+	return server, nil
+}
+
+// Serve accepts the connections of the listener until the context is done. Then it reports the instance as not
+// ready for the drain delay, stops accepting new connections and waits for the in-flight requests up to the
+// shutdown timeout before closing the rest
+func Serve(ctx context.Context, server *http.Server, listener net.Listener, drainDelay time.Duration, shutdownTimeout time.Duration) error {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		if server.TLSConfig != nil {
+			serveErr <- server.ServeTLS(listener, "", "")
+		} else {
+			serveErr <- server.Serve(listener)
+		}
+	}()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	log.Info().Dur("drainDelay", drainDelay).Msg("Shutting down the server")
+	svc.Get().Health.ShutDown()
+	time.Sleep(drainDelay)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		server.Close()
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// tracedRequest leaves out of the traces the probes and scrapes, which would drown the requests of the users
+func tracedRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	default:
+		return true
+	}
+}
+
 func GetRouter() *gin.Engine {
 	api := gin.Default()
-	api.Use(gin.Recovery())
-	api.Use(ginzerolog.Logger("gin"))
+	//The forwarded IP can be forged by any client, so it is only taken from the trusted proxies
+	err := api.SetTrustedProxies(conf.Get().Server.TrustedProxies)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid trusted proxies, none is trusted")
+		api.SetTrustedProxies(nil)
+	}
+	//The request ID is set first, so the errors, panics and logs of the request have it
+	api.Use(middleware.RequestInfo())
+	api.Use(middleware.Recovery())
+	//The span of the request is started before the rest of the middlewares, so they and the log run inside it
+	api.Use(otelgin.Middleware(conf.Get().Tracing.ServiceName, otelgin.WithFilter(tracedRequest)))
+	api.Use(middleware.Logger())
 	api.Use(middleware.Cors())
+	metricsConf := conf.Get().Metrics
+	if metricsConf.Enabled {
+		api.Use(middleware.Metrics())
+		if metricsConf.Port == "" {
+			api.GET("/metrics", gin.WrapH(metrics.Handler()))
+		}
+	}
+	api.GET("/.well-known/jwks.json", auth.JWKS)
+	api.GET("/healthz", health.Liveness)
+	api.GET("/readyz", health.Readiness)
 	pub := api.Group("/api/public")
-	pub.GET("/health", health)
+	pub.GET("/health", health.Readiness)
+	pub.GET("/openapi.json", openapi.JSON)
+	pub.GET("/docs", openapi.Docs)
+	pub.GET("/docs/redoc.standalone.js", openapi.DocsScript)
 	pub.POST("/login", auth.Login)
 	pub.POST("/register", auth.SignUp)
 	pub.POST("/validate/:username/:code", auth.ValidateAccount)
 	pub.POST("/validate/:username/resend", auth.ResendValidationCode)
 	pub.POST("/forgotten-password/:username", auth.SendForgottenPasswordCode)
 	pub.POST("/reset-password/:username/:code", auth.ResetPassword)
+	pub.POST("/magic-link", auth.RequestMagicLink)
+	pub.POST("/magic-link/login", auth.MagicLinkLogin)
+	pub.GET("/oidc/providers", auth.OIDCProviders)
+	pub.POST("/oidc/:provider/start", auth.OIDCStart)
+	pub.POST("/oidc/:provider/callback", auth.OIDCCallback)
+	pub.GET("/export/:token", export.Download)
+	pub.POST("/unsubscribe", notification.Unsubscribe)
+	pub.POST("/unsubscribe/:token", notification.UnsubscribeOneClick)
 	priv := api.Group("/api")
 	priv.Use(middleware.Authentication())
 	priv.GET("/self", auth.Self)
+	priv.PUT("/self/password", auth.ChangePassword)
+	priv.PUT("/self/email", auth.RequestEmailChange)
+	priv.POST("/self/email/confirm/:code", auth.ConfirmEmailChange)
+	priv.POST("/self/export", export.Request)
+	priv.GET("/self/export/:id", export.Get)
+	priv.GET("/self/audit", auth.Audit)
+	priv.GET("/self/notifications", notification.GetPreferences)
+	priv.PUT("/self/notifications", notification.UpdatePreferences)
 	priv.POST("/userword", userword.Create)
 	priv.PUT("/userword", userword.Update)
 	priv.GET("/userword/:id", userword.Get)
@@ -46,12 +247,27 @@ func GetRouter() *gin.Engine {
 	priv.DELETE("/userword/:id", userword.Delete)
 	priv.GET("/userword/progress", userword.UserProgress)
 	priv.POST("/word/search", word.Search)
+	priv.GET("/language", language.List)
 	priv.POST("/quiz", quiz.Create)
 	priv.POST("/quiz/answer", quiz.Answer)
+	priv.GET("/sync", deltasync.Pull)
+	priv.POST("/sync", deltasync.Push)
 	priv.DELETE("/account", auth.DeleteAccount)
+	priv.POST("/graphql", graphql.Query)
+	admin := api.Group("/api/admin")
+	admin.Use(middleware.Authentication(), middleware.Admin())
+	admin.POST("/user/search", user.Search)
+	admin.GET("/user/:id", user.Get)
+	admin.PUT("/user", user.Update)
+	admin.DELETE("/user/:id", user.Delete)
+	admin.PUT("/word", word.Update)
+	admin.DELETE("/word/:id", word.Delete)
+	admin.GET("/language", language.List)
+	admin.POST("/language", language.Create)
+	admin.DELETE("/language/:id", language.Delete)
+	admin.GET("/stats", stats.Get)
+	admin.GET("/mail/templates", mail.Templates)
+	admin.GET("/mail/templates/:name/preview", mail.Preview)
+	admin.GET("/mail/outbox", mail.Outbox)
 	return api
 }
-
-func health(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Everything is FINE2"})
-}
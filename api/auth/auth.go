@@ -5,6 +5,7 @@ import (
 	"vocablo/conf"
 	"vocablo/customerrors"
 	"vocablo/svc"
+	"vocablo/svc/audit"
 	"vocablo/svc/auth"
 	"vocablo/svc/verificationcode"
 	"vocablo/utils"
@@ -13,11 +14,10 @@ import (
 )
 
 func Login(c *gin.Context) {
-	conf := conf.Get()
 	var form auth.LoginForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -25,33 +25,40 @@ func Login(c *gin.Context) {
 	loginResponse, err := svc.Auth.Login(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.EmptyFormFieldsError:
-			res = utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("No username or password present"), nil)
-		case customerrors.InvalidCredentialsError:
-			res = utils.ErrorResponse(http.StatusUnauthorized, utils.GetStringPointer("Invalid credentials"), utils.GetStringPointer(customerrors.INVALID_CREDENTIALS))
-		case customerrors.NotValidatedAccountError:
-			res = utils.ErrorResponse(http.StatusForbidden, utils.GetStringPointer("Not validated account"), utils.GetStringPointer(customerrors.NOT_VALIDATED_ACCOUNT))
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
-		c.SetSameSite(http.SameSiteLaxMode)
-		if conf.Env == "prod" {
-			c.SetCookie("JWT_TOKEN", loginResponse.JWTToken, 30*24*60*60*1000, "/", conf.Prod.CookieHost, conf.Prod.CookieSecure, conf.Prod.CookieHttpOnly)
-		} else {
-			c.SetCookie("JWT_TOKEN", loginResponse.JWTToken, 30*24*60*60*1000, "/", conf.Dev.CookieHost, conf.Dev.CookieSecure, conf.Dev.CookieHttpOnly)
-		}
+		setJWTCookie(c, loginResponse.JWTToken)
 		res = utils.SuccessResponse(loginResponse.HashedCsrfToken)
 	}
 	c.JSON(res.Status, res.Body)
 }
 
+func setJWTCookie(c *gin.Context, token string) {
+	conf := conf.Get()
+	c.SetSameSite(http.SameSiteLaxMode)
+	if conf.Env == "prod" {
+		c.SetCookie("JWT_TOKEN", token, 30*24*60*60*1000, "/", conf.Prod.CookieHost, conf.Prod.CookieSecure, conf.Prod.CookieHttpOnly)
+	} else {
+		c.SetCookie("JWT_TOKEN", token, 30*24*60*60*1000, "/", conf.Dev.CookieHost, conf.Dev.CookieSecure, conf.Dev.CookieHttpOnly)
+	}
+}
+
+// setTemporaryCookie sets an HttpOnly cookie used to bind a flow, like an OIDC login, to the browser that started it
+func setTemporaryCookie(c *gin.Context, name string, value string, maxAge int) {
+	conf := conf.Get()
+	c.SetSameSite(http.SameSiteLaxMode)
+	if conf.Env == "prod" {
+		c.SetCookie(name, value, maxAge, "/", conf.Prod.CookieHost, conf.Prod.CookieSecure, true)
+	} else {
+		c.SetCookie(name, value, maxAge, "/", conf.Dev.CookieHost, conf.Dev.CookieSecure, true)
+	}
+}
+
 func SignUp(c *gin.Context) {
 	var form auth.SignUpForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -59,16 +66,7 @@ func SignUp(c *gin.Context) {
 	user, err := svc.Auth.SignUp(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.EmptyFormFieldsError:
-			res = utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("No username, email or password present"), nil)
-		case customerrors.UsernameAlreadyInUseError:
-			res = utils.ErrorResponse(http.StatusConflict, utils.GetStringPointer("Username already exist"), utils.GetStringPointer(customerrors.USERNAME_ALREADY_IN_USE))
-		case customerrors.EmailAlreadyInUseError:
-			res = utils.ErrorResponse(http.StatusConflict, utils.GetStringPointer("Email already exist"), utils.GetStringPointer(customerrors.EMAIL_ALREADY_IN_USE))
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(user)
 	}
@@ -82,7 +80,7 @@ func ResendValidationCode(c *gin.Context) {
 
 	var res utils.HttpResponse
 	if err != nil {
-		res = utils.InternalError(err)
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(nil)
 	}
@@ -95,7 +93,7 @@ func SendForgottenPasswordCode(c *gin.Context) {
 	err := svc.VerificationCode.Create(c.Request.Context(), verificationcode.CreateForm{Username: username, Type: utils.RESET_TYPE}, nil)
 	var res utils.HttpResponse
 	if err != nil {
-		res = utils.InternalError(err)
+		res = utils.Error(c.Request.Context(), err)
 
 	} else {
 		res = utils.SuccessResponse(nil)
@@ -111,16 +109,7 @@ func ValidateAccount(c *gin.Context) {
 	err := svc.VerificationCode.UseCode(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.AlreadyUsedValidationCodeError:
-			res = utils.ErrorResponse(http.StatusConflict, utils.GetStringPointer("Validation code already used"), utils.GetStringPointer(customerrors.ALREADY_USED_VALIDATION_CODE))
-		case customerrors.ExpiredValidationCodeError:
-			res = utils.ErrorResponse(http.StatusGone, utils.GetStringPointer("Validation code expired"), utils.GetStringPointer(customerrors.EXPIRED_VALIDATION_CODE))
-		case customerrors.IncorrectValidationCodeError:
-			res = utils.ErrorResponse(http.StatusUnauthorized, utils.GetStringPointer("Invalid validation code"), utils.GetStringPointer(customerrors.INCORRECT_VALIDATION_CODE))
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(nil)
 	}
@@ -132,14 +121,14 @@ func ResetPassword(c *gin.Context) {
 	code, _ := c.Params.Get("code")
 	body, err := c.GetRawData()
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
 	newPassStr := string(body)
 
 	if newPassStr == "" {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("No new password present"), nil)
+		res := utils.Error(c.Request.Context(), customerrors.EmptyFormFieldsError{})
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -149,16 +138,7 @@ func ResetPassword(c *gin.Context) {
 	err = svc.VerificationCode.UseCode(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.AlreadyUsedValidationCodeError:
-			res = utils.ErrorResponse(http.StatusConflict, utils.GetStringPointer("Validation code already used"), utils.GetStringPointer(customerrors.ALREADY_USED_VALIDATION_CODE))
-		case customerrors.ExpiredValidationCodeError:
-			res = utils.ErrorResponse(http.StatusGone, utils.GetStringPointer("Validation code expired"), utils.GetStringPointer(customerrors.EXPIRED_VALIDATION_CODE))
-		case customerrors.IncorrectValidationCodeError:
-			res = utils.ErrorResponse(http.StatusUnauthorized, utils.GetStringPointer("Invalid validation code"), utils.GetStringPointer(customerrors.INCORRECT_VALIDATION_CODE))
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(nil)
 	}
@@ -168,20 +148,20 @@ func ResetPassword(c *gin.Context) {
 func Self(c *gin.Context) {
 	jwtCookie, err := c.Cookie("JWT_TOKEN")
 	if err != nil {
-		res := utils.InternalError(err)
+		res := utils.Error(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
 	claims, err := utils.ValidateToken(jwtCookie)
 	if err != nil {
-		res := utils.InternalError(err)
+		res := utils.Error(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
 	svc := svc.Get()
 	user, err := svc.User.Get(c.Request.Context(), claims.Id)
 	if err != nil {
-		res := utils.NotFound("user", claims.Id.String())
+		res := utils.Error(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -192,13 +172,13 @@ func Self(c *gin.Context) {
 func DeleteAccount(c *gin.Context) {
 	jwtCookie, err := c.Cookie("JWT_TOKEN")
 	if err != nil {
-		res := utils.InternalError(err)
+		res := utils.Error(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
 	claims, err := utils.ValidateToken(jwtCookie)
 	if err != nil {
-		res := utils.InternalError(err)
+		res := utils.Error(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -206,9 +186,83 @@ func DeleteAccount(c *gin.Context) {
 	err = svc.User.Delete(c.Request.Context(), claims.Id)
 	var res utils.HttpResponse
 	if err != nil {
-		res = utils.InternalError(err)
+		res = utils.Error(c.Request.Context(), err)
 	} else {
+		//The user doesn't exist anymore, so the event is only linked by the username
+		svc.Audit.Record(c.Request.Context(), audit.Event{Type: utils.ACCOUNT_DELETION_EVENT, Username: claims.Username})
 		res = utils.SuccessResponse(nil)
 	}
 	c.JSON(res.Status, res.Body)
 }
+
+func Audit(c *gin.Context) {
+	var form audit.SearchForm
+	err := c.ShouldBindQuery(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	events, err := svc.Audit.Search(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(events)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func ChangePassword(c *gin.Context) {
+	var form auth.ChangePasswordForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	loginResponse, err := svc.Auth.ChangePassword(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		setJWTCookie(c, loginResponse.JWTToken)
+		res = utils.SuccessResponse(loginResponse.HashedCsrfToken)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func RequestEmailChange(c *gin.Context) {
+	var form auth.ChangeEmailForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	err = svc.Auth.RequestEmailChange(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(nil)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func ConfirmEmailChange(c *gin.Context) {
+	code, _ := c.Params.Get("code")
+	svc := svc.Get()
+	loginResponse, err := svc.Auth.ConfirmEmailChange(c.Request.Context(), code)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		setJWTCookie(c, loginResponse.JWTToken)
+		res = utils.SuccessResponse(loginResponse.HashedCsrfToken)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"net/http"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS publishes the public keys in the standard format, so other services can verify our tokens
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.GetJWKS())
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"vocablo/svc"
+	"vocablo/svc/verificationcode"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const magicLinkNonceCookie = "MAGIC_LINK_NONCE"
+
+func RequestMagicLink(c *gin.Context) {
+	var form verificationcode.MagicLinkForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	nonce, err := svc.Auth.RequestMagicLink(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		//The cookie outlives the link, which expires sooner
+		setTemporaryCookie(c, magicLinkNonceCookie, nonce, 60*60)
+		res = utils.SuccessResponse(nil)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func MagicLinkLogin(c *gin.Context) {
+	var form verificationcode.UseMagicLinkForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	form.Nonce, _ = c.Cookie(magicLinkNonceCookie)
+	svc := svc.Get()
+	loginResponse, err := svc.Auth.MagicLinkLogin(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		setTemporaryCookie(c, magicLinkNonceCookie, "", -1)
+		setJWTCookie(c, loginResponse.JWTToken)
+		res = utils.SuccessResponse(loginResponse.HashedCsrfToken)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"vocablo/svc"
+	"vocablo/svc/oidc"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const oidcStateCookie = "OIDC_STATE"
+
+func OIDCProviders(c *gin.Context) {
+	svc := svc.Get()
+	res := utils.SuccessResponse(svc.OIDC.Providers())
+	c.JSON(res.Status, res.Body)
+}
+
+func OIDCStart(c *gin.Context) {
+	provider, _ := c.Params.Get("provider")
+	svc := svc.Get()
+	result, err := svc.OIDC.Start(c.Request.Context(), provider)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		setTemporaryCookie(c, oidcStateCookie, result.StateToken, 10*60)
+		res = utils.SuccessResponse(result.AuthUrl)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func OIDCCallback(c *gin.Context) {
+	var form oidc.CallbackForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	form.Provider, _ = c.Params.Get("provider")
+	form.StateToken, _ = c.Cookie(oidcStateCookie)
+
+	svc := svc.Get()
+	loginResponse, err := svc.OIDC.Callback(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		//The state can only be used once
+		setTemporaryCookie(c, oidcStateCookie, "", -1)
+		setJWTCookie(c, loginResponse.JWTToken)
+		res = utils.SuccessResponse(loginResponse.HashedCsrfToken)
+	}
+	c.JSON(res.Status, res.Body)
+}
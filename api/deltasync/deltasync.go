@@ -0,0 +1,49 @@
+package deltasync
+
+import (
+	"vocablo/svc"
+	"vocablo/svc/deltasync"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Pull(c *gin.Context) {
+	var form deltasync.PullForm
+	err := c.ShouldBindQuery(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+
+	svc := svc.Get()
+	changes, err := svc.Sync.Pull(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(changes)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Push(c *gin.Context) {
+	var form deltasync.PushForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+
+	svc := svc.Get()
+	result, err := svc.Sync.Push(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(result)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"net/http"
+	"vocablo/svc"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Request(c *gin.Context) {
+	svc := svc.Get()
+	dataExport, err := svc.Export.Request(c.Request.Context())
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(dataExport)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Get(c *gin.Context) {
+	id, _ := c.Params.Get("id")
+	svc := svc.Get()
+	dataExport, err := svc.Export.Get(c.Request.Context(), id)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(dataExport)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Download(c *gin.Context) {
+	token, _ := c.Params.Get("token")
+	svc := svc.Get()
+	dataExport, err := svc.Export.Download(c.Request.Context(), token)
+	if err != nil {
+		var res utils.HttpResponse
+		res = utils.Error(c.Request.Context(), err)
+		c.JSON(res.Status, res.Body)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="vocablo-export.zip"`)
+	c.Data(http.StatusOK, "application/zip", dataExport.Archive)
+}
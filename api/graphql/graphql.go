@@ -0,0 +1,14 @@
+package graphql
+
+import (
+	"vocablo/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+var server = graph.NewServer()
+
+// Query answers a GraphQL query of the user of the session
+func Query(c *gin.Context) {
+	server.ServeHTTP(c.Writer, c.Request)
+}
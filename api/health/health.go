@@ -0,0 +1,30 @@
+package health
+
+import (
+	"net/http"
+	"vocablo/customerrors"
+	"vocablo/svc"
+	"vocablo/svc/health"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Liveness only checks that the process answers, so it isn't restarted because of a dependency
+func Liveness(c *gin.Context) {
+	res := utils.SuccessResponse(health.Component{Status: health.UP_STATUS})
+	c.JSON(res.Status, res.Body)
+}
+
+func Readiness(c *gin.Context) {
+	svc := svc.Get()
+	report := svc.Health.Readiness(c.Request.Context())
+	var res utils.HttpResponse
+	if report.Ready() {
+		res = utils.SuccessResponse(report)
+	} else {
+		res = utils.ErrorResponseWithData(http.StatusServiceUnavailable, utils.GetStringPointer("Not ready: "+report.Status),
+			utils.GetStringPointer(customerrors.NOT_READY), report)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,53 @@
+package language
+
+import (
+	"vocablo/svc"
+	"vocablo/svc/language"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func List(c *gin.Context) {
+	svc := svc.Get()
+	languages, err := svc.Language.List(c.Request.Context())
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(languages)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Create(c *gin.Context) {
+	var form language.CreateForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	createdLanguage, err := svc.Language.Create(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(createdLanguage)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Delete(c *gin.Context) {
+	id, _ := c.Params.Get("id")
+	svc := svc.Get()
+	err := svc.Language.Delete(c.Request.Context(), id)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(nil)
+	}
+	c.JSON(res.Status, res.Body)
+}
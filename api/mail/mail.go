@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"net/http"
+	"vocablo/svc"
+	"vocablo/svc/mail"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Templates(c *gin.Context) {
+	svc := svc.Get()
+	res := utils.SuccessResponse(svc.Mail.Templates())
+	c.JSON(res.Status, res.Body)
+}
+
+// Preview renders a template with sample data. With format=html or format=text, the body is returned as is
+// to be opened in the browser
+func Preview(c *gin.Context) {
+	name, _ := c.Params.Get("name")
+	svc := svc.Get()
+	message, err := svc.Mail.Preview(name, c.Query("locale"))
+	if err != nil {
+		var res utils.HttpResponse
+		res = utils.Error(c.Request.Context(), err)
+		c.JSON(res.Status, res.Body)
+		return
+	}
+	switch c.Query("format") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(message.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(message.Text))
+	default:
+		res := utils.SuccessResponse(message)
+		c.JSON(res.Status, res.Body)
+	}
+}
+
+func Outbox(c *gin.Context) {
+	var form mail.OutboxSearchForm
+	err := c.ShouldBindQuery(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	messages, err := svc.Mail.SearchOutbox(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(messages)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"vocablo/svc"
+	"vocablo/svc/notification"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetPreferences(c *gin.Context) {
+	svc := svc.Get()
+	preferences, err := svc.Notification.GetPreferences(c.Request.Context())
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(preferences)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func UpdatePreferences(c *gin.Context) {
+	var form notification.PreferencesForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	preferences, err := svc.Notification.UpdatePreferences(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(preferences)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+// UnsubscribeOneClick is the one-click unsubscribe (RFC 8058) of the List-Unsubscribe header. The email clients
+// POST to it with the token in the link, and a List-Unsubscribe=One-Click form that is not needed
+func UnsubscribeOneClick(c *gin.Context) {
+	svc := svc.Get()
+	err := svc.Notification.Unsubscribe(c.Request.Context(), notification.UnsubscribeForm{Token: c.Param("token")})
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(nil)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Unsubscribe(c *gin.Context) {
+	var form notification.UnsubscribeForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	err = svc.Notification.Unsubscribe(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(nil)
+	}
+	c.JSON(res.Status, res.Body)
+}
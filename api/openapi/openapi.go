@@ -0,0 +1,183 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"vocablo/conf"
+	"vocablo/customerrors"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sessionScheme = "session"
+	csrfScheme    = "csrf"
+	errorResponse = "Error"
+)
+
+var (
+	spec     *openapi3.T
+	specOnce sync.Once
+)
+
+// docsPage is the interactive documentation, rendered by Redoc from the specification. Redoc is served by us,
+// so the page doesn't run whatever a CDN serves
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Vocablo API</title>
+	<meta charset="utf-8"/>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+	<redoc spec-url="/api/public/openapi.json"></redoc>
+	<script src="/api/public/docs/redoc.standalone.js"></script>
+</body>
+</html>`
+
+// Spec returns the OpenAPI specification of the routes. It is built from the types once
+func Spec() *openapi3.T {
+	specOnce.Do(func() {
+		spec = Build(Operations)
+	})
+	return spec
+}
+
+// Build creates the specification of the operations
+func Build(operations []Operation) *openapi3.T {
+	generator := newSchemaGenerator()
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{Title: "Vocablo API", Version: "1.0.0",
+			Description: "The responses are wrapped in an envelope with the data, or the message and code of the error"},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas:   generator.components,
+			Responses: openapi3.ResponseBodies{},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				sessionScheme: &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{Type: "apiKey", In: "cookie",
+					Name: "JWT_TOKEN", Description: "Session cookie set by the logins"}},
+				csrfScheme: &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{Type: "apiKey", In: "header",
+					Name: "X-API-CSRF", Description: "CSRF token returned by the logins"}},
+			},
+		},
+	}
+	errorCode := openapi3.NewStringSchema()
+	for _, code := range customerrors.Codes {
+		errorCode.Enum = append(errorCode.Enum, code)
+	}
+	doc.Components.Schemas["ErrorCode"] = openapi3.NewSchemaRef("", errorCode)
+	errorBody := openapi3.NewObjectSchema().
+		WithPropertyRef("data", openapi3.NewSchemaRef("", openapi3.NewSchema().WithNullable())).
+		WithProperty("errorMessage", openapi3.NewStringSchema()).
+		WithPropertyRef("errorCode", openapi3.NewSchemaRef("#/components/schemas/ErrorCode", errorCode)).
+		WithProperty("requestId", openapi3.NewStringSchema())
+	doc.Components.Schemas["ErrorResponse"] = openapi3.NewSchemaRef("", errorBody)
+	errorRes := openapi3.NewResponse().
+		WithDescription("Error, with its code. The unexpected ones only have the request ID to find their logs").
+		WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/ErrorResponse", errorBody))
+	doc.Components.Responses[errorResponse] = &openapi3.ResponseRef{Value: errorRes}
+
+	for _, operation := range operations {
+		path, parameters := pathParameters(operation.Path)
+		item := doc.Paths.Value(path)
+		if item == nil {
+			item = &openapi3.PathItem{}
+			doc.Paths.Set(path, item)
+		}
+		op := openapi3.NewOperation()
+		op.OperationID = operation.Id
+		op.Summary = operation.Summary
+		op.Tags = []string{operation.Tag}
+		op.Parameters = append(parameters, generator.queryParameters(operation.Query)...)
+		op.Parameters = append(op.Parameters, generator.headerParameters(operation.Header)...)
+		if operation.Body != nil {
+			requestType := operation.RequestType
+			if requestType == "" {
+				requestType = "application/json"
+			}
+			op.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).
+				WithContent(openapi3.NewContentWithSchemaRef(generator.schemaRef(operation.Body), []string{requestType}))}
+		}
+		op.AddResponse(http.StatusOK, response(generator, operation))
+		op.Responses.Set("default", &openapi3.ResponseRef{Ref: "#/components/responses/" + errorResponse, Value: errorRes})
+		if operation.Access != PUBLIC_ACCESS {
+			op.Security = openapi3.NewSecurityRequirements().
+				With(openapi3.NewSecurityRequirement().Authenticate(sessionScheme).Authenticate(csrfScheme))
+		}
+		if operation.Access == ADMIN_ACCESS {
+			op.Description = "Requires the admin role"
+		}
+		item.SetOperation(operation.Method, op)
+	}
+	return doc
+}
+
+// pathParameters converts the parameters of a gin path to the OpenAPI syntax, and returns their definitions
+func pathParameters(ginPath string) (string, openapi3.Parameters) {
+	parameters := openapi3.Parameters{}
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		name, found := strings.CutPrefix(segment, ":")
+		if !found {
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		schema := openapi3.NewStringSchema()
+		//The entities are identified by UUIDs
+		if name == "id" {
+			schema = openapi3.NewUUIDSchema()
+		}
+		parameters = append(parameters, &openapi3.ParameterRef{Value: openapi3.NewPathParameter(name).WithSchema(schema)})
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+// response is the successful response of the operation, the envelope with the data unless it has its own type
+func response(generator *schemaGenerator, operation Operation) *openapi3.Response {
+	res := openapi3.NewResponse().WithDescription("OK")
+	data := generator.schemaRef(operation.Data)
+	if operation.ResponseType != "" {
+		if data == nil {
+			switch operation.ResponseType {
+			case "application/json":
+				data = openapi3.NewSchemaRef("", openapi3.NewObjectSchema())
+			case "application/zip":
+				data = openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithFormat("binary"))
+			default:
+				data = openapi3.NewSchemaRef("", openapi3.NewStringSchema())
+			}
+		}
+		return res.WithContent(openapi3.NewContentWithSchemaRef(data, []string{operation.ResponseType}))
+	}
+	if data == nil {
+		data = openapi3.NewSchemaRef("", openapi3.NewSchema().WithNullable())
+	}
+	envelope := openapi3.NewObjectSchema().WithPropertyRef("data", data).
+		WithProperty("errorMessage", openapi3.NewStringSchema().WithNullable()).
+		WithProperty("errorCode", openapi3.NewStringSchema().WithNullable())
+	return res.WithJSONSchema(envelope)
+}
+
+// JSON serves the specification
+func JSON(c *gin.Context) {
+	c.JSON(http.StatusOK, Spec())
+}
+
+// Docs serves the interactive documentation
+func Docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+// DocsScript serves the configured Redoc bundle used by the documentation
+func DocsScript(c *gin.Context) {
+	path := conf.ResolvePath(conf.Get().Docs.RedocFile)
+	if path == "" {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	c.Header("Content-Type", "text/javascript; charset=utf-8")
+	c.File(path)
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"vocablo/ent"
+	"vocablo/schema"
+	"vocablo/svc/audit"
+	"vocablo/svc/auth"
+	"vocablo/svc/deltasync"
+	"vocablo/svc/health"
+	"vocablo/svc/language"
+	"vocablo/svc/mail"
+	"vocablo/svc/notification"
+	"vocablo/svc/oidc"
+	"vocablo/svc/quiz"
+	"vocablo/svc/stats"
+	"vocablo/svc/user"
+	"vocablo/svc/userword"
+	"vocablo/svc/verificationcode"
+	"vocablo/svc/word"
+	"vocablo/utils"
+)
+
+const (
+	PUBLIC_ACCESS = "public"
+	USER_ACCESS   = "user"
+	ADMIN_ACCESS  = "admin"
+)
+
+// Operation describes a route of the router. The types of the bodies are given by a value of them
+type Operation struct {
+	Method string
+	// Path with the parameters in the syntax of gin, as it is registered
+	Path    string
+	Id      string
+	Tag     string
+	Summary string
+	Access  string
+	// Body sent as JSON, or with RequestType if it's set
+	Body        interface{}
+	RequestType string
+	// Query is the form bound from the query parameters
+	Query interface{}
+	// Header is the form bound from the request headers
+	Header interface{}
+	// Data of the response envelope. When ResponseType is set the response is sent as is with that type
+	Data         interface{}
+	ResponseType string
+}
+
+// graphqlRequest is the body of a GraphQL query
+type graphqlRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// previewQuery are the parameters the mail preview reads from the query
+type previewQuery struct {
+	Locale string `form:"locale"`
+	// html or text to get the body as is instead of the message
+	Format string `form:"format"`
+}
+
+// Operations are the routes of the API. The test of the specification checks that every route of the router
+// is here, so a route added without documenting fails it
+var Operations = []Operation{
+	{Method: "GET", Path: "/.well-known/jwks.json", Id: "getJwks", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Public keys to verify the tokens", Data: utils.JWKS{}, ResponseType: "application/json"},
+	{Method: "GET", Path: "/healthz", Id: "liveness", Tag: "health", Access: PUBLIC_ACCESS,
+		Summary: "Liveness of the instance", Data: health.Component{}},
+	{Method: "GET", Path: "/readyz", Id: "readiness", Tag: "health", Access: PUBLIC_ACCESS,
+		Summary: "Readiness of the instance and its dependencies", Data: health.Report{}},
+	{Method: "GET", Path: "/metrics", Id: "metrics", Tag: "health", Access: PUBLIC_ACCESS,
+		Summary: "Prometheus metrics, when they aren't served in their own port", ResponseType: "text/plain"},
+
+	{Method: "GET", Path: "/api/public/health", Id: "health", Tag: "health", Access: PUBLIC_ACCESS,
+		Summary: "Readiness of the instance and its dependencies", Data: health.Report{}},
+	{Method: "GET", Path: "/api/public/openapi.json", Id: "getOpenApi", Tag: "docs", Access: PUBLIC_ACCESS,
+		Summary: "This specification", ResponseType: "application/json"},
+	{Method: "GET", Path: "/api/public/docs", Id: "getDocs", Tag: "docs", Access: PUBLIC_ACCESS,
+		Summary: "Interactive documentation of this specification", ResponseType: "text/html"},
+	{Method: "GET", Path: "/api/public/docs/redoc.standalone.js", Id: "getDocsScript", Tag: "docs", Access: PUBLIC_ACCESS,
+		Summary: "Redoc bundle used by the documentation", ResponseType: "text/javascript"},
+	{Method: "POST", Path: "/api/public/login", Id: "login", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Log in, setting the session cookie. Returns the CSRF token", Body: auth.LoginForm{}, Data: ""},
+	{Method: "POST", Path: "/api/public/register", Id: "signUp", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Create an account, sending the validation code", Body: auth.SignUpForm{}, Data: &ent.User{}},
+	{Method: "POST", Path: "/api/public/validate/:username/:code", Id: "validateAccount", Tag: "auth",
+		Access: PUBLIC_ACCESS, Summary: "Validate the account with the code sent by email"},
+	{Method: "POST", Path: "/api/public/validate/:username/resend", Id: "resendValidationCode", Tag: "auth",
+		Access: PUBLIC_ACCESS, Summary: "Send a new validation code"},
+	{Method: "POST", Path: "/api/public/forgotten-password/:username", Id: "sendForgottenPasswordCode", Tag: "auth",
+		Access: PUBLIC_ACCESS, Summary: "Send the code to reset the password"},
+	{Method: "POST", Path: "/api/public/reset-password/:username/:code", Id: "resetPassword", Tag: "auth",
+		Access: PUBLIC_ACCESS, Summary: "Set a new password, sent as the body, with the reset code", Body: "",
+		RequestType: "text/plain"},
+	{Method: "POST", Path: "/api/public/magic-link", Id: "requestMagicLink", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Send a login link by email", Body: verificationcode.MagicLinkForm{}},
+	{Method: "POST", Path: "/api/public/magic-link/login", Id: "magicLinkLogin", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Log in with a magic link. Returns the CSRF token", Body: verificationcode.UseMagicLinkForm{}, Data: ""},
+	{Method: "GET", Path: "/api/public/oidc/providers", Id: "oidcProviders", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Configured identity providers", Data: []string{}},
+	{Method: "POST", Path: "/api/public/oidc/:provider/start", Id: "oidcStart", Tag: "auth", Access: PUBLIC_ACCESS,
+		Summary: "Start the login with a provider, setting the state cookie. Returns the URL to redirect to", Data: ""},
+	{Method: "POST", Path: "/api/public/oidc/:provider/callback", Id: "oidcCallback", Tag: "auth",
+		Access: PUBLIC_ACCESS, Summary: "Finish the login with a provider. Returns the CSRF token",
+		Body: oidc.CallbackForm{}, Data: ""},
+	{Method: "GET", Path: "/api/public/export/:token", Id: "downloadExport", Tag: "account", Access: PUBLIC_ACCESS,
+		Summary: "Download the archive of a data export", ResponseType: "application/zip"},
+	{Method: "POST", Path: "/api/public/unsubscribe", Id: "unsubscribe", Tag: "notification", Access: PUBLIC_ACCESS,
+		Summary: "Stop the emails of the link of an email", Body: notification.UnsubscribeForm{}},
+	{Method: "POST", Path: "/api/public/unsubscribe/:token", Id: "unsubscribeOneClick", Tag: "notification",
+		Access: PUBLIC_ACCESS, Summary: "One-click unsubscribe of the List-Unsubscribe header of an email"},
+
+	{Method: "GET", Path: "/api/self", Id: "self", Tag: "account", Access: USER_ACCESS,
+		Summary: "User of the session", Data: &ent.User{}},
+	{Method: "PUT", Path: "/api/self/password", Id: "changePassword", Tag: "account", Access: USER_ACCESS,
+		Summary: "Change the password, revoking the other sessions. Returns the new CSRF token",
+		Body:    auth.ChangePasswordForm{}, Data: ""},
+	{Method: "PUT", Path: "/api/self/email", Id: "requestEmailChange", Tag: "account", Access: USER_ACCESS,
+		Summary: "Send the code to confirm a new email", Body: auth.ChangeEmailForm{}},
+	{Method: "POST", Path: "/api/self/email/confirm/:code", Id: "confirmEmailChange", Tag: "account",
+		Access: USER_ACCESS, Summary: "Confirm the new email. Returns the new CSRF token", Data: ""},
+	{Method: "POST", Path: "/api/self/export", Id: "requestExport", Tag: "account", Access: USER_ACCESS,
+		Summary: "Request an export of the data of the user", Data: &ent.DataExport{}},
+	{Method: "GET", Path: "/api/self/export/:id", Id: "getExport", Tag: "account", Access: USER_ACCESS,
+		Summary: "State of a data export", Data: &ent.DataExport{}},
+	{Method: "GET", Path: "/api/self/audit", Id: "audit", Tag: "account", Access: USER_ACCESS,
+		Summary: "Security events of the user", Query: audit.SearchForm{}, Data: utils.Page[*ent.AuditEvent]{}},
+	{Method: "GET", Path: "/api/self/notifications", Id: "getNotificationPreferences", Tag: "notification",
+		Access: USER_ACCESS, Summary: "Notification preferences", Data: &notification.Preferences{}},
+	{Method: "PUT", Path: "/api/self/notifications", Id: "updateNotificationPreferences", Tag: "notification",
+		Access: USER_ACCESS, Summary: "Update the notification preferences", Body: notification.PreferencesForm{},
+		Data: &notification.Preferences{}},
+	{Method: "POST", Path: "/api/userword", Id: "createUserWord", Tag: "userword", Access: USER_ACCESS,
+		Summary: "Add a word to the user. The data is the created word as base64 encoded JSON",
+		Body:    userword.CreateForm{}, Data: []byte{}},
+	{Method: "PUT", Path: "/api/userword", Id: "updateUserWord", Tag: "userword", Access: USER_ACCESS,
+		Summary: "Update a word of the user if it is in the version of If-Match", Header: userword.UpdateHeader{},
+		Body: userword.UpdateForm{}, Data: &ent.UserWord{}},
+	{Method: "GET", Path: "/api/userword/:id", Id: "getUserWord", Tag: "userword", Access: USER_ACCESS,
+		Summary: "Word of the user, with the ETag of its version", Header: userword.GetHeader{}, Data: &ent.UserWord{}},
+	{Method: "POST", Path: "/api/userword/search", Id: "searchUserWords", Tag: "userword", Access: USER_ACCESS,
+		Summary: "Search the words of the user", Body: userword.SearchForm{}, Data: utils.Page[*ent.UserWord]{}},
+	{Method: "DELETE", Path: "/api/userword/:id", Id: "deleteUserWord", Tag: "userword", Access: USER_ACCESS,
+		Summary: "Delete a word of the user"},
+	{Method: "GET", Path: "/api/userword/progress", Id: "userWordProgress", Tag: "userword", Access: USER_ACCESS,
+		Summary: "Learned and pending words of the user", Data: schema.UserWordProgress{}},
+	{Method: "POST", Path: "/api/word/search", Id: "searchWord", Tag: "word", Access: USER_ACCESS,
+		Summary: "Search a word in the dictionary", Body: word.SearchForm{}, Data: &utils.Page[*ent.Word]{}},
+	{Method: "GET", Path: "/api/language", Id: "listLanguages", Tag: "language", Access: USER_ACCESS,
+		Summary: "Supported languages", Data: []*ent.Language{}},
+	{Method: "POST", Path: "/api/quiz", Id: "createQuiz", Tag: "quiz", Access: USER_ACCESS,
+		Summary: "Create a quiz with the words of the user", Body: quiz.CreateForm{}, Data: &quiz.Quiz{}},
+	{Method: "POST", Path: "/api/quiz/answer", Id: "answerQuiz", Tag: "quiz", Access: USER_ACCESS,
+		Summary: "Answer a quiz. Returns the score", Body: quiz.Quiz{}, Data: 0},
+	{Method: "GET", Path: "/api/sync", Id: "pullChanges", Tag: "sync", Access: USER_ACCESS,
+		Summary: "Changes of the words of the user since the cursor of the last pull", Query: deltasync.PullForm{},
+		Data: &deltasync.Changes{}},
+	{Method: "POST", Path: "/api/sync", Id: "pushChanges", Tag: "sync", Access: USER_ACCESS,
+		Summary: "Apply the changes and quiz answers made offline", Body: deltasync.PushForm{},
+		Data: &deltasync.PushResult{}},
+	{Method: "DELETE", Path: "/api/account", Id: "deleteAccount", Tag: "account", Access: USER_ACCESS,
+		Summary: "Delete the account and its data"},
+	{Method: "POST", Path: "/api/graphql", Id: "graphql", Tag: "graphql", Access: USER_ACCESS,
+		Summary: "GraphQL query on the data of the user, answered as a GraphQL response", Body: graphqlRequest{},
+		ResponseType: "application/json"},
+
+	{Method: "POST", Path: "/api/admin/user/search", Id: "adminSearchUsers", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Search the users", Body: user.SearchForm{}, Data: &utils.Page[*ent.User]{}},
+	{Method: "GET", Path: "/api/admin/user/:id", Id: "adminGetUser", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "User", Data: &ent.User{}},
+	{Method: "PUT", Path: "/api/admin/user", Id: "adminUpdateUser", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Update the role or state of a user", Body: user.UpdateForm{}, Data: &ent.User{}},
+	{Method: "DELETE", Path: "/api/admin/user/:id", Id: "adminDeleteUser", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Delete a user"},
+	{Method: "PUT", Path: "/api/admin/word", Id: "adminUpdateWord", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Update a word of the dictionary", Body: word.UpdateForm{}, Data: &ent.Word{}},
+	{Method: "DELETE", Path: "/api/admin/word/:id", Id: "adminDeleteWord", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Delete a word of the dictionary"},
+	{Method: "GET", Path: "/api/admin/language", Id: "adminListLanguages", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Supported languages", Data: []*ent.Language{}},
+	{Method: "POST", Path: "/api/admin/language", Id: "adminCreateLanguage", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Add a supported language", Body: language.CreateForm{}, Data: &ent.Language{}},
+	{Method: "DELETE", Path: "/api/admin/language/:id", Id: "adminDeleteLanguage", Tag: "admin",
+		Access: ADMIN_ACCESS, Summary: "Remove a supported language"},
+	{Method: "GET", Path: "/api/admin/stats", Id: "adminStats", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Usage statistics", Data: &stats.Stats{}},
+	{Method: "GET", Path: "/api/admin/mail/templates", Id: "adminMailTemplates", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Mail templates and their locales", Data: []mail.TemplateInfo{}},
+	{Method: "GET", Path: "/api/admin/mail/templates/:name/preview", Id: "adminPreviewMail", Tag: "admin",
+		Access: ADMIN_ACCESS, Summary: "Render a template with sample data. With format the body is returned as is",
+		Query: previewQuery{}, Data: &mail.Message{}},
+	{Method: "GET", Path: "/api/admin/mail/outbox", Id: "adminOutbox", Tag: "admin", Access: ADMIN_ACCESS,
+		Summary: "Search the queued emails, without their bodies", Query: mail.OutboxSearchForm{}, Data: &utils.Page[*ent.Outbox]{}},
+}
@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaGenerator builds the schemas of the Go types as encoding/json marshals them. The named structs are
+// added once to the components and referenced, which also resolves the cycles of the ent edges
+type schemaGenerator struct {
+	components openapi3.Schemas
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{components: openapi3.Schemas{}}
+}
+
+// componentName is the name of the component of a struct, qualified by its package as the forms of the
+// services share names. The instances of the generic types have no name, and are inlined
+func componentName(t reflect.Type) string {
+	if t.Name() == "" || strings.Contains(t.Name(), "[") {
+		return ""
+	}
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// schemaRef returns the schema of the type of the value. A nil value has no schema
+func (g *schemaGenerator) schemaRef(value interface{}) *openapi3.SchemaRef {
+	if value == nil {
+		return nil
+	}
+	return g.typeRef(reflect.TypeOf(value))
+}
+
+func (g *schemaGenerator) typeRef(t reflect.Type) *openapi3.SchemaRef {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	var schema *openapi3.Schema
+	switch {
+	case t == timeType:
+		schema = openapi3.NewDateTimeSchema()
+	case t == uuidType:
+		schema = openapi3.NewUUIDSchema()
+	case t == rawMessageType:
+		schema = openapi3.NewSchema()
+	case t.Kind() == reflect.Struct:
+		return g.structRef(t)
+	default:
+		schema = g.kindSchema(t)
+	}
+	if nullable {
+		schema.Nullable = true
+	}
+	return openapi3.NewSchemaRef("", schema)
+}
+
+// kindSchema returns the schema of the types that aren't structs
+func (g *schemaGenerator) kindSchema(t reflect.Type) *openapi3.Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return openapi3.NewBoolSchema()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return openapi3.NewIntegerSchema()
+	case reflect.Int32, reflect.Uint32:
+		return openapi3.NewInt32Schema()
+	case reflect.Int64, reflect.Uint64:
+		return openapi3.NewInt64Schema()
+	case reflect.Float32, reflect.Float64:
+		return openapi3.NewFloat64Schema()
+	case reflect.String:
+		return openapi3.NewStringSchema()
+	case reflect.Slice, reflect.Array:
+		//encoding/json sends the bytes in base64
+		if t.Elem().Kind() == reflect.Uint8 {
+			return openapi3.NewBytesSchema()
+		}
+		schema := openapi3.NewArraySchema()
+		schema.Items = g.typeRef(t.Elem())
+		return schema
+	case reflect.Map:
+		schema := openapi3.NewObjectSchema()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: g.typeRef(t.Elem())}
+		return schema
+	default:
+		return openapi3.NewSchema()
+	}
+}
+
+// structRef returns the reference to the component of the struct, creating it the first time. The component is
+// registered before its fields are generated, so the fields that lead back to it are references too
+func (g *schemaGenerator) structRef(t reflect.Type) *openapi3.SchemaRef {
+	name := componentName(t)
+	if name == "" {
+		return openapi3.NewSchemaRef("", g.structSchema(t))
+	}
+	ref := "#/components/schemas/" + name
+	if component, ok := g.components[name]; ok {
+		return openapi3.NewSchemaRef(ref, component.Value)
+	}
+	schema := openapi3.NewObjectSchema()
+	g.components[name] = openapi3.NewSchemaRef("", schema)
+	*schema = *g.structSchema(t)
+	return openapi3.NewSchemaRef(ref, schema)
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	g.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the properties of the exported fields with the name of their json tag. The embedded structs
+// without a name are flattened, as encoding/json does
+func (g *schemaGenerator) addFields(schema *openapi3.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				g.addFields(schema, fieldType)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.WithPropertyRef(name, g.typeRef(field.Type))
+		if strings.Contains(field.Tag.Get("binding"), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// queryParameters returns the parameters of a form bound from the query, one per field with a form tag
+func (g *schemaGenerator) queryParameters(form interface{}) openapi3.Parameters {
+	return g.formParameters(form, "form", openapi3.NewQueryParameter)
+}
+
+// headerParameters returns the parameters of a form bound from the headers, one per field with a header tag
+func (g *schemaGenerator) headerParameters(form interface{}) openapi3.Parameters {
+	return g.formParameters(form, "header", openapi3.NewHeaderParameter)
+}
+
+func (g *schemaGenerator) formParameters(form interface{}, tag string,
+	newParameter func(name string) *openapi3.Parameter) openapi3.Parameters {
+	parameters := openapi3.Parameters{}
+	if form == nil {
+		return parameters
+	}
+	t := reflect.TypeOf(form)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		parameter := newParameter(name).WithSchema(g.typeRef(field.Type).Value).
+			WithRequired(strings.Contains(field.Tag.Get("binding"), "required"))
+		parameters = append(parameters, &openapi3.ParameterRef{Value: parameter})
+	}
+	return parameters
+}
@@ -1,8 +1,6 @@
 package quiz
 
 import (
-	"net/http"
-	"vocablo/customerrors"
 	"vocablo/svc"
 	"vocablo/svc/quiz"
 	"vocablo/utils"
@@ -14,7 +12,7 @@ func Create(c *gin.Context) {
 	var form quiz.CreateForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -23,13 +21,7 @@ func Create(c *gin.Context) {
 	createdQuiz, err := svc.Quiz.Create(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.NotEnoughWordsForQuizError:
-			res = utils.ErrorResponse(http.StatusConflict, utils.GetStringPointer(err.Error()),
-				utils.GetStringPointer(customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ))
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(createdQuiz)
 	}
@@ -40,7 +32,7 @@ func Answer(c *gin.Context) {
 	var filledQuiz quiz.Quiz
 	err := c.ShouldBind(&filledQuiz)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -49,10 +41,7 @@ func Answer(c *gin.Context) {
 	score, err := svc.Quiz.Answer(c.Request.Context(), filledQuiz)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(score)
 	}
@@ -0,0 +1,20 @@
+package stats
+
+import (
+	"vocablo/svc"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Get(c *gin.Context) {
+	svc := svc.Get()
+	stats, err := svc.Stats.Get(c.Request.Context())
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(stats)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"vocablo/cli"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	entuser "vocablo/ent/user"
+	"vocablo/svc/auth"
+	"vocablo/svc/stats"
+	"vocablo/svc/user"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SetupAdminTest promotes the main user to admin and creates a second, regular, user
+func SetupAdminTest(client *ent.Client, t *testing.T, ctx context.Context) {
+	_, err := cli.CreateAdmin(ctx, client, testUserForm1.Username, "", "")
+	if err != nil {
+		t.Fatalf("Error creating admin: %s", err)
+	}
+	bytesPass, err := bcrypt.GenerateFromPassword([]byte(testUserForm2.Password), 14)
+	if err != nil {
+		t.Fatalf("Error hashing password: %s", err)
+	}
+	client.User.Create().SetUsername(testUserForm2.Username).SetEmail(testUserForm2.Email).
+		SetPassword(string(bytesPass[:])).SetValidated(true).SaveX(ctx)
+}
+
+func TestAdminRequired(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("GET", "/api/admin/stats", nil, ctx)
+	assert.Equal(t, 403, resp.Code, "Response status should be 403")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.ADMIN_REQUIRED, *respBody.ErrorCode)
+}
+
+func TestAdminSearchUsers(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, SetupAdminTest)
+	defer teardown(t)
+
+	body, _ := json.Marshal(user.SearchForm{Name: utils.GetStringPointer(testUserForm2.Username)})
+	resp := testEnv.MakeAuthRequest("POST", "/api/admin/user/search", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var page utils.Page[ent.User]
+	pageStr, _ := json.Marshal(respBody.Data)
+	err = json.Unmarshal(pageStr, &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, page.NElements)
+	assert.Equal(t, testUserForm2.Username, page.Content[0].Username)
+	assert.Empty(t, page.Content[0].Password, "The password should never be returned")
+}
+
+func TestAdminDisableUser(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupAdminTest)
+	defer teardown(t)
+
+	disabledUser := client.User.Query().Where(entuser.UsernameEQ(testUserForm2.Username)).OnlyX(ctx)
+	loginResult, err := auth.GenerateLoginResult(disabledUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabledCtx := context.WithValue(context.WithValue(ctx, utils.CsrfKey, loginResult.HashedCsrfToken),
+		utils.JwtKey, loginResult.JWTToken)
+
+	disabled := true
+	body, _ := json.Marshal(user.UpdateForm{Id: disabledUser.ID, Disabled: &disabled})
+	resp := testEnv.MakeAuthRequest("PUT", "/api/admin/user", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	//The open sessions are revoked and the user can't log in again
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, disabledCtx)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	body, _ = json.Marshal(auth.LoginForm{Username: testUserForm2.Username, Password: testUserForm2.Password})
+	resp = testEnv.MakeRequest("POST", "/api/public/login", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 403, resp.Code, "Response status should be 403")
+	var respBody utils.ResponseBody
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.DISABLED_ACCOUNT, *respBody.ErrorCode)
+
+	//An admin can't disable himself
+	body, _ = json.Marshal(user.UpdateForm{Id: ctx.Value(utils.UserIdKey).(uuid.UUID), Disabled: &disabled})
+	resp = testEnv.MakeAuthRequest("PUT", "/api/admin/user", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 403, resp.Code, "Response status should be 403")
+}
+
+func TestAdminStats(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, SetupAdminTest)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("GET", "/api/admin/stats", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result stats.Stats
+	statsStr, _ := json.Marshal(respBody.Data)
+	err = json.Unmarshal(statsStr, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, result.Users)
+	assert.Equal(t, 1, result.Admins)
+	assert.Equal(t, 2, result.Languages)
+}
+
+func TestCreateAdminOnlyOnce(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, false, SetupAdminTest)
+	defer teardown(t)
+
+	_, err := cli.CreateAdmin(ctx, client, testUserForm2.Username, "", "")
+	assert.IsType(t, customerrors.AdminAlreadyExistsError{}, err)
+	//The promoted user is validated too
+	admin := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	assert.True(t, admin.Validated)
+}
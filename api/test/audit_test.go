@@ -0,0 +1,95 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vocablo/api"
+	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/ent/auditevent"
+	"vocablo/svc/auth"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogin(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	body, _ := json.Marshal(auth.LoginForm{Username: testUserForm1.Username, Password: INCORRECT_PASSWORD})
+	req := httptest.NewRequest("POST", "/api/public/login", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "audit-test")
+	req.Header.Set("X-Request-ID", "audit-request-id")
+	recorder := httptest.NewRecorder()
+	testEnv.Router.ServeHTTP(recorder, req)
+	assert.Equal(t, 401, recorder.Code)
+	assert.Equal(t, "audit-request-id", recorder.Header().Get("X-Request-ID"))
+
+	resp := testEnv.MakeAuthRequest("GET", "/api/self/audit?pageSize=1", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var page utils.Page[ent.AuditEvent]
+	pageStr, _ := json.Marshal(respBody.Data)
+	err = json.Unmarshal(pageStr, &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The login made in the setup is recorded too
+	assert.Equal(t, 2, page.NElements)
+	assert.True(t, page.HasNext)
+	lastEvent := page.Content[0]
+	assert.Equal(t, utils.LOGIN_FAILURE_EVENT, lastEvent.Type)
+	assert.Equal(t, "audit-test", lastEvent.UserAgent)
+	assert.Equal(t, "audit-request-id", lastEvent.RequestId)
+	assert.NotEmpty(t, lastEvent.IP)
+}
+
+func TestAuditAccountDeletion(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("DELETE", "/api/account", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	//The events outlive the account
+	deletionEvent, err := client.AuditEvent.Query().Where(auditevent.TypeEQ(utils.ACCOUNT_DELETION_EVENT)).Only(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testUserForm1.Username, deletionEvent.Username)
+	assert.Equal(t, 1, client.AuditEvent.Query().Where(auditevent.TypeEQ(utils.LOGIN_SUCCESS_EVENT)).CountX(ctx))
+}
+
+func TestAuditClientIPTrustedProxies(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	defer func() { conf.Get().Server.TrustedProxies = nil }()
+
+	login := func(requestId string) string {
+		body, _ := json.Marshal(auth.LoginForm{Username: testUserForm1.Username, Password: INCORRECT_PASSWORD})
+		req := httptest.NewRequest("POST", "/api/public/login", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.Header.Set("X-Request-ID", requestId)
+		req.RemoteAddr = "192.0.2.1:1234"
+		recorder := httptest.NewRecorder()
+		testEnv.Router.ServeHTTP(recorder, req)
+		assert.Equal(t, 401, recorder.Code)
+		return client.AuditEvent.Query().Where(auditevent.RequestIdEQ(requestId)).OnlyX(ctx).IP
+	}
+
+	//By default no proxy is trusted, so the forwarded IP is ignored
+	assert.Equal(t, "192.0.2.1", login("untrusted-proxy"))
+
+	conf.Get().Server.TrustedProxies = []string{"192.0.2.0/24"}
+	testEnv.Router = api.GetRouter()
+	assert.Equal(t, "203.0.113.7", login("trusted-proxy"))
+}
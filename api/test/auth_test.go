@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +13,8 @@ import (
 	"vocablo/ent"
 	entuser "vocablo/ent/user"
 	"vocablo/ent/verificationcode"
+	"vocablo/svc"
+	"vocablo/svc/auth"
 	"vocablo/svc/user"
 	"vocablo/utils"
 
@@ -113,13 +117,13 @@ func TestValidateAccountOk(t *testing.T) {
 	client, teardown, ctx := SetupTest(t, false, nil)
 	defer teardown(t)
 	expirationDate := time.Now().Add(time.Minute * 15)
-	verificationCode, err := client.VerificationCode.Create().SetCode(verificationCodeForm.Code).SetUserID(client.User.Query().Where(
+	_, err := client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetUserID(client.User.Query().Where(
 		entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).ID).
 		SetType(utils.VALIDATION_TYPE).SetExpireDate(expirationDate).Save(ctx)
 	if err != nil {
 		t.Errorf("Error creating verification code: %s", err)
 	}
-	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCode.Code, nil)
+	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCodeForm.Code, nil)
 
 	assert.Equal(t, 200, resp.Code, "Response status should be 200")
 	//We check that the user is validated
@@ -134,7 +138,7 @@ func TestValidateAccountWrongCode(t *testing.T) {
 	client, teardown, ctx := SetupTest(t, false, nil)
 	defer teardown(t)
 	expirationDate := time.Now().Add(time.Minute * 15)
-	_, err := client.VerificationCode.Create().SetCode(verificationCodeForm.Code).SetUserID(client.User.Query().Where(
+	_, err := client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetUserID(client.User.Query().Where(
 		entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).ID).
 		SetType(utils.VALIDATION_TYPE).SetExpireDate(expirationDate).Save(ctx)
 	if err != nil {
@@ -156,14 +160,14 @@ func TestValidateAccountAlreadyUsedCode(t *testing.T) {
 	client, teardown, ctx := SetupTest(t, false, nil)
 	defer teardown(t)
 	expirationDate := time.Now().Add(time.Minute * 15)
-	verificationCode, err := client.VerificationCode.Create().SetCode(verificationCodeForm.Code).SetUserID(client.User.Query().Where(
+	_, err := client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetUserID(client.User.Query().Where(
 		entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).ID).
 		SetType(utils.VALIDATION_TYPE).SetExpireDate(expirationDate).Save(ctx)
 	if err != nil {
 		t.Errorf("Error creating verification code: %s", err)
 	}
-	testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCode.Code, nil)
-	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCode.Code, nil)
+	testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCodeForm.Code, nil)
+	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCodeForm.Code, nil)
 
 	assert.Equal(t, 409, resp.Code, "Response status should be 409")
 	bodyRes := resp.Body.Bytes()
@@ -179,13 +183,13 @@ func TestValidateAccountExpiredCode(t *testing.T) {
 	client, teardown, ctx := SetupTest(t, false, nil)
 	defer teardown(t)
 	expirationDate := time.Now().Add(time.Minute * -5)
-	verificationCode, err := client.VerificationCode.Create().SetCode(verificationCodeForm.Code).SetUserID(client.User.Query().Where(
+	_, err := client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetUserID(client.User.Query().Where(
 		entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).ID).
 		SetType(utils.VALIDATION_TYPE).SetExpireDate(expirationDate).Save(ctx)
 	if err != nil {
 		t.Errorf("Error creating verification code: %s", err)
 	}
-	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCode.Code, nil)
+	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCodeForm.Code, nil)
 
 	assert.Equal(t, 410, resp.Code, "Response status should be 410")
 	bodyRes := resp.Body.Bytes()
@@ -202,7 +206,7 @@ func TestValidateAccountResendCode(t *testing.T) {
 	defer teardown(t)
 	expirationDate := time.Now().Add(time.Minute * 15)
 	//We create a verification code with a creation date in the past to ensure that the just created code is the one used
-	verificationCode, err := client.VerificationCode.Create().SetCode(verificationCodeForm.Code).SetCreationDate(time.Now().Add(time.Minute * -5)).SetUserID(client.User.Query().Where(
+	_, err := client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetCreationDate(time.Now().Add(time.Minute * -5)).SetUserID(client.User.Query().Where(
 		entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).ID).
 		SetType(utils.VALIDATION_TYPE).SetExpireDate(expirationDate).Save(ctx)
 	if err != nil {
@@ -212,16 +216,17 @@ func TestValidateAccountResendCode(t *testing.T) {
 	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/resend", nil)
 	assert.Equal(t, 200, resp.Code, "Response status should be 200")
 
-	//We check that the verification code was created
-	validationCode, err := client.VerificationCode.Query().Where(verificationcode.And(verificationcode.HasUserWith(entuser.UsernameEQ(testUserForm1.Username)),
-		verificationcode.TypeEQ(utils.VALIDATION_TYPE))).Order(verificationcode.ByCreationDate(sql.OrderDesc())).First(ctx)
+	//We check that the verification code was created, replacing the previous one
+	nCodes, err := client.VerificationCode.Query().Where(verificationcode.And(verificationcode.HasUserWith(entuser.UsernameEQ(testUserForm1.Username)),
+		verificationcode.TypeEQ(utils.VALIDATION_TYPE))).Count(ctx)
 	if err != nil {
 		t.Errorf("Error getting verification code: %s", err)
 	}
+	assert.Equal(t, 1, nCodes, "The previous verification code should be invalidated")
 	//We check that the previous verification code is not working
 	verif, _ := client.VerificationCode.Query().All(ctx)
 	fmt.Println(verif)
-	resp = testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCode.Code, nil)
+	resp = testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+verificationCodeForm.Code, nil)
 	assert.Equal(t, 401, resp.Code, "Response status should be 401")
 	bodyRes := resp.Body.Bytes()
 	var bodyResObj utils.ResponseBody
@@ -231,7 +236,7 @@ func TestValidateAccountResendCode(t *testing.T) {
 	}
 	assert.Equal(t, customerrors.INCORRECT_VALIDATION_CODE, *bodyResObj.ErrorCode, "Error code should be INCORRECT_VALIDATION_CODE")
 	//We check that the new verification code is working
-	resp = testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+validationCode.Code, nil)
+	resp = testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/"+codeFromMail(t), nil)
 	assert.Equal(t, 200, resp.Code, "Response status should be 200")
 	//We check that the user is validated
 	user, err := client.User.Query().Where(entuser.Username(testUserForm1.Username)).Only(ctx)
@@ -247,14 +252,14 @@ func TestResetPasswordOk(t *testing.T) {
 	resp := testEnv.MakeRequest("POST", "/api/public/forgotten-password/"+testUserForm1.Username, nil)
 	assert.Equal(t, 200, resp.Code, "Response status should be 200")
 
-	//We check that the verification code was created and we get the code number
-	validationCode, err := client.VerificationCode.Query().Where(verificationcode.And(verificationcode.HasUserWith(entuser.UsernameEQ(testUserForm1.Username)),
+	//We check that the verification code was created, the code number is taken from the email
+	_, err := client.VerificationCode.Query().Where(verificationcode.And(verificationcode.HasUserWith(entuser.UsernameEQ(testUserForm1.Username)),
 		verificationcode.TypeEQ(utils.RESET_TYPE))).Order(verificationcode.ByCreationDate(sql.OrderDesc())).First(ctx)
 	if err != nil {
 		t.Errorf("Error getting verification code: %s", err)
 	}
 	//We reset the password using the code
-	resp = testEnv.MakeRequest("POST", "/api/public/reset-password/"+testUserForm1.Username+"/"+validationCode.Code, utils.GetStringPointer(NEW_PASSWORD))
+	resp = testEnv.MakeRequest("POST", "/api/public/reset-password/"+testUserForm1.Username+"/"+codeFromMail(t), utils.GetStringPointer(NEW_PASSWORD))
 	assert.Equal(t, 200, resp.Code, "Response status should be 200")
 
 	//We check that the password was updated
@@ -307,3 +312,125 @@ func TestSelf(t *testing.T) {
 	assert.Equal(t, testUserForm1.Username, resultUser.Username, "Username should be the same")
 
 }
+
+// codeFromMail returns the verification code sent in the last email, as only its hash is stored
+func codeFromMail(t *testing.T) string {
+	mail := testEnv.LastMail(t)
+	if mail == nil {
+		t.Fatal("No email sent")
+	}
+	code := regexp.MustCompile(`\b\d{4,}\b`).FindString(mail.Message)
+	if code == "" {
+		t.Fatal("No code in the email")
+	}
+	return code
+}
+
+// sessionFromResponse returns a context with the session tokens returned by a login-like response
+func sessionFromResponse(t *testing.T, ctx context.Context, resp *httptest.ResponseRecorder) context.Context {
+	var bodyResObj utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &bodyResObj)
+	if err != nil {
+		t.Fatalf("Error unmarshalling response body: %s", err)
+	}
+	ctx = context.WithValue(ctx, utils.CsrfKey, bodyResObj.Data.(string))
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == "JWT_TOKEN" {
+			ctx = context.WithValue(ctx, utils.JwtKey, cookie.Value)
+		}
+	}
+	return ctx
+}
+
+func TestChangePasswordOk(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	body, _ := json.Marshal(&auth.ChangePasswordForm{CurrentPassword: testUserForm1.Password, NewPassword: NEW_PASSWORD})
+	resp := testEnv.MakeAuthRequest("PUT", "/api/self/password", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	newSessionCtx := sessionFromResponse(t, ctx, resp)
+
+	//The previous session is revoked, but the one returned keeps working
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, ctx)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, newSessionCtx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	newPassTestUserForm := testUserForm1
+	newPassTestUserForm.Password = NEW_PASSWORD
+	body, _ = json.Marshal(&newPassTestUserForm)
+	resp = testEnv.MakeRequest("POST", "/api/public/login", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestChangePasswordIncorrectCurrent(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	body, _ := json.Marshal(&auth.ChangePasswordForm{CurrentPassword: INCORRECT_PASSWORD, NewPassword: NEW_PASSWORD})
+	resp := testEnv.MakeAuthRequest("PUT", "/api/self/password", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 403, resp.Code, "Response status should be 403")
+	//The session is still valid
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestChangeEmailOk(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	body, _ := json.Marshal(&auth.ChangeEmailForm{NewEmail: NEW_EMAIL})
+	resp := testEnv.MakeAuthRequest("PUT", "/api/self/email", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	//The email is not changed until the code is confirmed
+	user := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	assert.Equal(t, testUserForm1.Email, user.Email)
+	emailCode, err := client.VerificationCode.Query().Where(verificationcode.And(verificationcode.HasUserWith(entuser.UsernameEQ(testUserForm1.Username)),
+		verificationcode.TypeEQ(utils.EMAIL_TYPE))).Only(ctx)
+	if err != nil {
+		t.Fatalf("Error getting verification code: %s", err)
+	}
+	assert.Equal(t, NEW_EMAIL, emailCode.NewEmail)
+
+	resp = testEnv.MakeAuthRequest("POST", "/api/self/email/confirm/"+codeFromMail(t), nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	newSessionCtx := sessionFromResponse(t, ctx, resp)
+	user = client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	assert.Equal(t, NEW_EMAIL, user.Email)
+
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, ctx)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, newSessionCtx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestChangeEmailAlreadyInUse(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	client.User.Create().SetUsername(testUserForm2.Username).SetEmail(testUserForm2.Email).SetPassword(testUserForm2.Password).SaveX(ctx)
+	body, _ := json.Marshal(&auth.ChangeEmailForm{NewEmail: testUserForm2.Email})
+	resp := testEnv.MakeAuthRequest("PUT", "/api/self/email", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 409, resp.Code, "Response status should be 409")
+}
+
+func TestPurgeVerificationCodes(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, false, nil)
+	defer teardown(t)
+	userId := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).ID
+	client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetUserID(userId).
+		SetType(utils.RESET_TYPE).SetExpireDate(time.Now().Add(time.Minute * -5)).SaveX(ctx)
+	client.VerificationCode.Create().SetCode(utils.HashCode(verificationCodeForm.Code)).SetUserID(userId).
+		SetType(utils.EMAIL_TYPE).SetExpireDate(time.Now().Add(time.Minute * 15)).SetUsed(true).SaveX(ctx)
+
+	resp := testEnv.MakeRequest("POST", "/api/public/validate/"+testUserForm1.Username+"/resend", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	//The codes are stored hashed
+	validationCode := client.VerificationCode.Query().Where(verificationcode.TypeEQ(utils.VALIDATION_TYPE)).OnlyX(ctx)
+	assert.NotEqual(t, codeFromMail(t), validationCode.Code)
+
+	purged, err := svc.Get().VerificationCode.Purge(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, purged)
+	assert.Equal(t, 1, client.VerificationCode.Query().CountX(ctx))
+}
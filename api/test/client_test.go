@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	apiclient "vocablo/client"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/schema"
+	"vocablo/svc/auth"
+	"vocablo/svc/userword"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSession(t *testing.T) {
+	_, teardown, _ := SetupTest(t, true, nil)
+	defer teardown(t)
+	ctx := context.Background()
+	apiClient := testEnv.Client(t, ctx)
+
+	//Without a session the error has only the code
+	_, err := apiClient.Self(ctx)
+	var apiErr *apiclient.Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, 401, apiErr.Status)
+		assert.Equal(t, customerrors.NOT_CSRF_TOKEN, apiErr.Code)
+		assert.Nil(t, apiErr.Unwrap())
+	}
+
+	err = apiClient.Login(ctx, auth.LoginForm{Username: testUserForm1.Username, Password: "Wrong-password1"})
+	assert.ErrorAs(t, err, &customerrors.InvalidCredentialsError{})
+
+	err = apiClient.Login(ctx, auth.LoginForm{Username: testUserForm1.Username, Password: testUserForm1.Password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	self, err := apiClient.Self(ctx)
+	if assert.Nil(t, err) {
+		assert.Equal(t, testUserForm1.Username, self.Username)
+	}
+
+	//The password change renews the session of the client
+	err = apiClient.ChangePassword(ctx, auth.ChangePasswordForm{CurrentPassword: "Wrong-password1",
+		NewPassword: "New-password1"})
+	assert.ErrorAs(t, err, &customerrors.IncorrectCurrentPasswordError{})
+	err = apiClient.ChangePassword(ctx, auth.ChangePasswordForm{CurrentPassword: testUserForm1.Password,
+		NewPassword: "weak"})
+	var weakPasswordErr customerrors.WeakPasswordError
+	if assert.ErrorAs(t, err, &weakPasswordErr) {
+		assert.Contains(t, weakPasswordErr.Rules, customerrors.PASSWORD_TOO_SHORT)
+	}
+	previousJwt, _ := apiClient.Session()
+	err = apiClient.ChangePassword(ctx, auth.ChangePasswordForm{CurrentPassword: testUserForm1.Password,
+		NewPassword: "New-password1"})
+	if assert.Nil(t, err) {
+		jwt, _ := apiClient.Session()
+		assert.NotEqual(t, previousJwt, jwt)
+		_, err = apiClient.Self(ctx)
+		assert.Nil(t, err)
+	}
+}
+
+func TestClientUserWords(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	apiClient := testEnv.Client(t, ctx)
+
+	createdWord, err := apiClient.CreateUserWord(ctx, testWordForm1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testWordForm1.Term, createdWord.Term)
+	_, err = apiClient.CreateUserWord(ctx, userword.CreateForm{Term: "palabra", Lang: "xx",
+		Definitions: testWordForm1.Definitions})
+	var languageErr customerrors.LanguageNotFoundError
+	if assert.ErrorAs(t, err, &languageErr) {
+		assert.Equal(t, "xx", languageErr.Code)
+	}
+
+	updatedWord, err := apiClient.UpdateUserWord(ctx, userword.UpdateForm{ID: createdWord.ID.String(),
+		Term: utils.GetStringPointer("worse")}, createdWord.Version)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "worse", updatedWord.Term)
+	}
+	//An update of the version already changed returns the current word
+	_, err = apiClient.UpdateUserWord(ctx, userword.UpdateForm{ID: createdWord.ID.String(),
+		Term: utils.GetStringPointer("stale")}, createdWord.Version)
+	var staleErr customerrors.StaleVersionError
+	if assert.ErrorAs(t, err, &staleErr) && assert.NotNil(t, updatedWord) {
+		assert.Equal(t, updatedWord.Version, staleErr.Version)
+		var currentWord ent.UserWord
+		assert.Nil(t, json.Unmarshal(staleErr.Current.(json.RawMessage), &currentWord))
+		assert.Equal(t, "worse", currentWord.Term)
+	}
+	gotWord, err := apiClient.GetUserWord(ctx, createdWord.ID)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "worse", gotWord.Term)
+	}
+	page, err := apiClient.SearchUserWords(ctx, userword.SearchForm{Term: utils.GetStringPointer("worse")})
+	if assert.Nil(t, err) && assert.Len(t, page.Content, 1) {
+		assert.Equal(t, createdWord.ID, page.Content[0].ID)
+	}
+	progress, err := apiClient.UserWordProgress(ctx)
+	if assert.Nil(t, err) {
+		assert.Equal(t, schema.UserWordProgress{TotalWords: 1, UnlearnedWords: 1}, *progress)
+	}
+	languages, err := apiClient.Languages(ctx)
+	if assert.Nil(t, err) {
+		assert.Len(t, languages, 2)
+	}
+
+	err = apiClient.DeleteUserWord(ctx, createdWord.ID)
+	assert.Nil(t, err)
+	_, err = apiClient.GetUserWord(ctx, createdWord.ID)
+	var notFoundErr customerrors.NotFoundError
+	if assert.ErrorAs(t, err, &notFoundErr) {
+		assert.Equal(t, "Word", notFoundErr.Resource)
+	}
+}
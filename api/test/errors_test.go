@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vocablo/customerrors"
+	"vocablo/svc/auth"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// requestWithId makes a request with the request ID header and returns the decoded body
+func requestWithId(t *testing.T, method string, path string, body string, requestId string) (*httptest.ResponseRecorder, utils.ResponseBody) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", requestId)
+	testEnv.Router.ServeHTTP(recorder, req)
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(recorder.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recorder, respBody
+}
+
+func TestErrorMapping(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	body, _ := json.Marshal(auth.LoginForm{Username: "nobody", Password: "Nobody-password1"})
+	resp, respBody := requestWithId(t, "POST", "/api/public/login", string(body), "client-request-1")
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	assert.Equal(t, "client-request-1", resp.Header().Get("X-Request-ID"))
+	assert.Equal(t, "client-request-1", respBody.RequestId)
+	if assert.NotNil(t, respBody.ErrorCode) {
+		assert.Equal(t, customerrors.INVALID_CREDENTIALS, *respBody.ErrorCode)
+	}
+
+	//The bodies that don't pass the validation have a generic code
+	body, _ = json.Marshal(auth.LoginForm{Username: "nobody"})
+	resp, respBody = requestWithId(t, "POST", "/api/public/login", string(body), "client-request-2")
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	if assert.NotNil(t, respBody.ErrorCode) {
+		assert.Equal(t, customerrors.INVALID_REQUEST, *respBody.ErrorCode)
+	}
+}
+
+func TestInternalErrorHidesDetails(t *testing.T) {
+	client, teardown := StartTest(t)
+	defer teardown(t)
+	var logs bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	defer func() { log.Logger = previousLogger }()
+	//Without the database every query fails with an unexpected error
+	client.Close()
+
+	resp, respBody := requestWithId(t, "GET", "/api/public/export/sometoken", "", "client-request-3")
+	assert.Equal(t, 500, resp.Code, "Response status should be 500")
+	assert.Equal(t, "client-request-3", respBody.RequestId)
+	if assert.NotNil(t, respBody.ErrorCode) && assert.NotNil(t, respBody.ErrorMessage) {
+		assert.Equal(t, customerrors.INTERNAL_ERROR, *respBody.ErrorCode)
+		assert.Equal(t, "Internal server error, request ID: client-request-3", *respBody.ErrorMessage)
+	}
+	//The details are only logged
+	assert.NotContains(t, resp.Body.String(), "database is closed")
+	assert.Contains(t, logs.String(), "database is closed")
+	assert.Contains(t, logs.String(), `"request_id":"client-request-3"`)
+}
+
+func TestPanicRecovery(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	testEnv.Router.GET("/panic", func(c *gin.Context) {
+		panic("unexpected state")
+	})
+
+	resp, respBody := requestWithId(t, "GET", "/panic", "", "client-request-4")
+	assert.Equal(t, 500, resp.Code, "Response status should be 500")
+	assert.Equal(t, "client-request-4", respBody.RequestId)
+	if assert.NotNil(t, respBody.ErrorCode) {
+		assert.Equal(t, customerrors.INTERNAL_ERROR, *respBody.ErrorCode)
+	}
+	assert.NotContains(t, resp.Body.String(), "unexpected state")
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/svc"
+	"vocablo/svc/export"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExport(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("POST", "/api/self/export", nil, ctx)
+	assert.Equal(t, 200, resp.Code)
+	var respBody utils.ResponseBody
+	var dataExport ent.DataExport
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataExportStr, _ := json.Marshal(respBody.Data)
+	err = json.Unmarshal(dataExportStr, &dataExport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, export.PENDING_STATUS, dataExport.Status)
+
+	svc.Get().Export.Wait()
+	resp = testEnv.MakeAuthRequest("GET", "/api/self/export/"+dataExport.ID.String(), nil, ctx)
+	assert.Equal(t, 200, resp.Code)
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, export.READY_STATUS, respBody.Data.(map[string]interface{})["status"])
+
+	//The download link is sent by email
+	mail := testEnv.LastMail(t)
+	if mail == nil {
+		t.Fatal("No email sent")
+	}
+	assert.Equal(t, testUserForm1.Email, mail.To)
+	token := mail.Message[strings.LastIndex(mail.Message, "/export/")+len("/export/"):]
+
+	resp = testEnv.MakeRequest("GET", "/api/public/export/"+token, nil)
+	assert.Equal(t, 200, resp.Code)
+	assert.Equal(t, "application/zip", resp.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	assert.Contains(t, files, "profile.json")
+	assert.Contains(t, files, "quizzes.json")
+	assert.Contains(t, files, "verification_codes.json")
+	userWordsFile, err := files["userwords.json"].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userWordsContent, _ := io.ReadAll(userWordsFile)
+	var userWords []export.UserWord
+	err = json.Unmarshal(userWordsContent, &userWords)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, len(userWords))
+
+	//Once expired, the link doesn't work anymore
+	client.DataExport.UpdateOneID(dataExport.ID).SetExpireDate(time.Now().Add(-time.Minute)).ExecX(ctx)
+	resp = testEnv.MakeRequest("GET", "/api/public/export/"+token, nil)
+	assert.Equal(t, 410, resp.Code)
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.EXPIRED_DOWNLOAD_TOKEN, *respBody.ErrorCode)
+}
+
+func TestDataExportInvalidToken(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+
+	resp := testEnv.MakeRequest("GET", "/api/public/export/invalidtoken", nil)
+	assert.Equal(t, 404, resp.Code)
+}
+
+func TestDataExportStalePending(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	//An export whose generation was interrupted by a crash stays pending
+	staleExport := client.DataExport.Create().SetStatus(export.PENDING_STATUS).SetUserID(userId).
+		SetCreationDate(time.Now().Add(-time.Hour)).SaveX(ctx)
+
+	resp := testEnv.MakeAuthRequest("POST", "/api/self/export", nil, ctx)
+	assert.Equal(t, 200, resp.Code)
+	var respBody struct {
+		Data ent.DataExport `json:"data"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, staleExport.ID, respBody.Data.ID)
+	assert.Equal(t, export.FAILED_STATUS, client.DataExport.GetX(ctx, staleExport.ID).Status)
+
+	svc.Get().Export.Wait()
+	assert.Equal(t, export.READY_STATUS, client.DataExport.GetX(ctx, respBody.Data.ID).Status)
+}
+
+func TestDataExportPurge(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	expiredExport := client.DataExport.Create().SetStatus(export.READY_STATUS).SetUserID(userId).
+		SetArchive([]byte("archive")).SetExpireDate(time.Now().Add(-time.Minute)).SaveX(ctx)
+	readyExport := client.DataExport.Create().SetStatus(export.READY_STATUS).SetUserID(userId).
+		SetArchive([]byte("archive")).SetExpireDate(time.Now().Add(time.Hour)).SaveX(ctx)
+
+	purged, err := svc.Get().Export.Purge(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, purged)
+	_, err = client.DataExport.Get(ctx, expiredExport.ID)
+	assert.True(t, ent.IsNotFound(err))
+	assert.Equal(t, readyExport.ID, client.DataExport.GetX(ctx, readyExport.ID).ID)
+}
@@ -0,0 +1,267 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"vocablo/customerrors"
+	"vocablo/ent/language"
+	"vocablo/ent/user"
+	entuserword "vocablo/ent/userword"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type graphqlError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
+}
+
+// graphqlQuery sends a GraphQL query with the session of the context, decoding its data in data when it has no errors
+func graphqlQuery(t *testing.T, ctx context.Context, query string, variables map[string]interface{},
+	data interface{}) []graphqlError {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := testEnv.MakeAuthRequest("POST", "/api/graphql", utils.GetStringPointer(string(body)), ctx)
+	if resp.Code != 200 {
+		t.Fatalf("GraphQL query answered with %d: %s", resp.Code, resp.Body.String())
+	}
+	var gqlResp graphqlResponse
+	err = json.Unmarshal(resp.Body.Bytes(), &gqlResp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gqlResp.Errors) == 0 && data != nil {
+		err = json.Unmarshal(gqlResp.Data, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return gqlResp.Errors
+}
+
+func TestGraphQLRequiresSession(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+
+	resp := testEnv.MakeRequest("POST", "/api/graphql", utils.GetStringPointer(`{"query": "{ progress { totalWords } }"}`))
+	assert.Equal(t, 401, resp.Code)
+}
+
+func TestGraphQLUserWords(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+	otherUser := client.User.Create().SetUsername(testUserForm2.Username).SetEmail(testUserForm2.Email).
+		SetPassword(testUserForm2.Password).SaveX(ctx)
+	client.UserWord.Create().SetTerm("ajeno").SetLang(
+		client.Language.Query().Where(language.CodeEqualFold(testWordForm1.Lang)).OnlyX(ctx)).
+		SetDefinitions(testWordForm1.Definitions).SetUserID(otherUser.ID).SaveX(ctx)
+
+	query := `query ($after: Cursor) {
+		userWords(first: 2, after: $after, orderBy: {field: TERM, direction: ASC}) {
+			totalCount
+			edges { node { id term definitions { definition } lang { code } } }
+			pageInfo { hasNextPage endCursor }
+		}
+		progress { totalWords learnedWords unlearnedWords }
+	}`
+	var data struct {
+		UserWords struct {
+			TotalCount int `json:"totalCount"`
+			Edges      []struct {
+				Node struct {
+					ID          string `json:"id"`
+					Term        string `json:"term"`
+					Definitions []struct {
+						Definition string `json:"definition"`
+					} `json:"definitions"`
+					Lang struct {
+						Code string `json:"code"`
+					} `json:"lang"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool    `json:"hasNextPage"`
+				EndCursor   *string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"userWords"`
+		Progress struct {
+			TotalWords     int `json:"totalWords"`
+			LearnedWords   int `json:"learnedWords"`
+			UnlearnedWords int `json:"unlearnedWords"`
+		} `json:"progress"`
+	}
+	errs := graphqlQuery(t, ctx, query, nil, &data)
+	if !assert.Empty(t, errs) {
+		return
+	}
+	//Only the words of the user are in the connection
+	assert.Equal(t, 3, data.UserWords.TotalCount)
+	assert.Equal(t, 3, data.Progress.TotalWords)
+	assert.Equal(t, 3, data.Progress.UnlearnedWords)
+	terms := make([]string, 0, 3)
+	if assert.Len(t, data.UserWords.Edges, 2) {
+		assert.NotEmpty(t, data.UserWords.Edges[0].Node.Definitions)
+		assert.NotEmpty(t, data.UserWords.Edges[0].Node.Lang.Code)
+		for _, edge := range data.UserWords.Edges {
+			terms = append(terms, edge.Node.Term)
+		}
+	}
+	assert.True(t, data.UserWords.PageInfo.HasNextPage)
+
+	errs = graphqlQuery(t, ctx, query, map[string]interface{}{"after": data.UserWords.PageInfo.EndCursor}, &data)
+	if assert.Empty(t, errs) && assert.Len(t, data.UserWords.Edges, 1) {
+		terms = append(terms, data.UserWords.Edges[0].Node.Term)
+		assert.False(t, data.UserWords.PageInfo.HasNextPage)
+	}
+	assert.IsNonDecreasing(t, terms)
+	assert.NotContains(t, terms, "ajeno")
+
+	//The filters are the ones of the search
+	errs = graphqlQuery(t, ctx, `query ($term: String) { userWords(term: $term) { totalCount } }`,
+		map[string]interface{}{"term": testWordForm1.Term}, &data)
+	if assert.Empty(t, errs) {
+		assert.Equal(t, 1, data.UserWords.TotalCount)
+	}
+}
+
+func TestGraphQLUserWordMutations(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	var created struct {
+		CreateUserWord struct {
+			ID      string `json:"id"`
+			Term    string `json:"term"`
+			Version int    `json:"version"`
+		} `json:"createUserWord"`
+	}
+	errs := graphqlQuery(t, ctx, `mutation ($input: CreateUserWordInput!) { createUserWord(input: $input) { id term version } }`,
+		map[string]interface{}{"input": testWordForm1}, &created)
+	if !assert.Empty(t, errs) {
+		return
+	}
+	assert.Equal(t, testWordForm1.Term, created.CreateUserWord.Term)
+	userWord := client.UserWord.Query().Where(entuserword.TermEQ(testWordForm1.Term)).WithUser().OnlyX(ctx)
+	assert.Equal(t, ctx.Value(utils.UserIdKey), userWord.Edges.User.ID)
+
+	var updated struct {
+		UpdateUserWord struct {
+			Term        string `json:"term"`
+			Definitions []struct {
+				Definition string `json:"definition"`
+			} `json:"definitions"`
+		} `json:"updateUserWord"`
+	}
+	errs = graphqlQuery(t, ctx, `mutation ($input: UpdateUserWordInput!) {
+		updateUserWord(input: $input) { term definitions { definition } }
+	}`, map[string]interface{}{"input": map[string]interface{}{"id": created.CreateUserWord.ID,
+		"version": created.CreateUserWord.Version, "term": "worse"}}, &updated)
+	if assert.Empty(t, errs) {
+		assert.Equal(t, "worse", updated.UpdateUserWord.Term)
+		assert.Len(t, updated.UpdateUserWord.Definitions, len(testWordForm1.Definitions))
+	}
+
+	//A change on the version before the update is stale, and the error has the current word
+	errs = graphqlQuery(t, ctx, `mutation ($input: UpdateUserWordInput!) { updateUserWord(input: $input) { id } }`,
+		map[string]interface{}{"input": map[string]interface{}{"id": created.CreateUserWord.ID,
+			"version": created.CreateUserWord.Version, "term": "lost"}}, nil)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, customerrors.STALE_VERSION, errs[0].Extensions["code"])
+		assert.Equal(t, "worse", errs[0].Extensions["data"].(map[string]interface{})["term"])
+	}
+
+	//The errors of the services keep their code
+	errs = graphqlQuery(t, ctx, `mutation ($input: CreateUserWordInput!) { createUserWord(input: $input) { id } }`,
+		map[string]interface{}{"input": map[string]interface{}{"term": "palabra", "lang": "xx",
+			"definitions": testWordForm1.Definitions}}, nil)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, customerrors.LANGUAGE_NOT_FOUND, errs[0].Extensions["code"])
+	}
+
+	var deleted struct {
+		DeleteUserWord string `json:"deleteUserWord"`
+	}
+	errs = graphqlQuery(t, ctx, `mutation ($id: ID!) { deleteUserWord(id: $id) }`,
+		map[string]interface{}{"id": created.CreateUserWord.ID}, &deleted)
+	if assert.Empty(t, errs) {
+		assert.Equal(t, created.CreateUserWord.ID, deleted.DeleteUserWord)
+	}
+	assert.Zero(t, client.UserWord.Query().Where(entuserword.DeletedAtIsNil()).CountX(ctx))
+}
+
+func TestGraphQLUserWordOwnership(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	otherWord := client.UserWord.Query().Where(entuserword.HasUserWith(user.UsernameEQ(testUserForm2.Username))).
+		OnlyX(ctx)
+
+	errs := graphqlQuery(t, ctx, `mutation ($input: UpdateUserWordInput!) { updateUserWord(input: $input) { id } }`,
+		map[string]interface{}{"input": map[string]interface{}{"id": otherWord.ID, "version": otherWord.Version,
+			"term": "stolen"}}, nil)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, customerrors.NOT_ALLOWED_RESOURCE, errs[0].Extensions["code"])
+	}
+	errs = graphqlQuery(t, ctx, `mutation ($id: ID!) { deleteUserWord(id: $id) }`,
+		map[string]interface{}{"id": otherWord.ID}, nil)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, customerrors.NOT_ALLOWED_RESOURCE, errs[0].Extensions["code"])
+	}
+	//A quiz can't be answered with the words of another user
+	errs = graphqlQuery(t, ctx, `mutation ($input: QuizInput!) { answerQuiz(input: $input) }`,
+		map[string]interface{}{"input": map[string]interface{}{"questions": []map[string]interface{}{{
+			"userWordID": otherWord.ID, "question": otherWord.Term, "options": []string{"a", "b", "c", "d"},
+			"correctOptionPos": 0, "answerPos": 0}}}}, nil)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, customerrors.NOT_ALLOWED_RESOURCE, errs[0].Extensions["code"])
+	}
+
+	unchanged := client.UserWord.GetX(ctx, otherWord.ID)
+	assert.Equal(t, otherWord.Term, unchanged.Term)
+	assert.Equal(t, otherWord.LearningProgress, unchanged.LearningProgress)
+}
+
+func TestGraphQLQuiz(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+
+	errs := graphqlQuery(t, ctx, `mutation { createQuiz(nQuestions: 4) { score } }`, nil, nil)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ, errs[0].Extensions["code"])
+	}
+
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLang(
+		client.Language.Query().Where(language.CodeEqualFold(testWordForm3.Lang)).OnlyX(ctx)).
+		SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	var created struct {
+		CreateQuiz struct {
+			Questions []map[string]interface{} `json:"questions"`
+		} `json:"createQuiz"`
+	}
+	errs = graphqlQuery(t, ctx, `mutation {
+		createQuiz(nQuestions: 4) { questions { userWordID question options correctOptionPos answerPos } }
+	}`, nil, &created)
+	if !assert.Empty(t, errs) || !assert.Len(t, created.CreateQuiz.Questions, 4) {
+		return
+	}
+	for _, question := range created.CreateQuiz.Questions {
+		question["answerPos"] = question["correctOptionPos"]
+	}
+	var answered struct {
+		AnswerQuiz int `json:"answerQuiz"`
+	}
+	errs = graphqlQuery(t, ctx, `mutation ($input: QuizInput!) { answerQuiz(input: $input) }`,
+		map[string]interface{}{"input": created.CreateQuiz}, &answered)
+	if assert.Empty(t, errs) {
+		assert.Equal(t, 100, answered.AnswerQuiz)
+	}
+}
@@ -0,0 +1,218 @@
+package test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"vocablo/customerrors"
+	"vocablo/ent/user"
+	entuserword "vocablo/ent/userword"
+	vocablov1 "vocablo/proto/vocablo/v1"
+	"vocablo/rpc"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcConn serves the gRPC services in memory and returns a connection to them
+func grpcConn(t *testing.T) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := rpc.NewServer(nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// withToken sends the JWT of the session of the context as the bearer token
+func withToken(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+ctx.Value(utils.JwtKey).(string))
+}
+
+// assertStatus checks the gRPC code of the error and the error code of its details
+func assertStatus(t *testing.T, err error, code codes.Code, errorCode string) {
+	st, ok := status.FromError(err)
+	if !assert.True(t, ok) || !assert.Equal(t, code, st.Code()) {
+		return
+	}
+	for _, detail := range st.Details() {
+		if info, isInfo := detail.(*errdetails.ErrorInfo); isInfo {
+			assert.Equal(t, errorCode, info.Reason)
+			return
+		}
+	}
+	t.Errorf("The status has no ErrorInfo: %v", st)
+}
+
+func TestGrpcAuthentication(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	conn := grpcConn(t)
+	userWordClient := vocablov1.NewUserWordServiceClient(conn)
+
+	_, err := userWordClient.GetProgress(context.Background(), &vocablov1.GetProgressRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	invalidCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	_, err = userWordClient.GetProgress(invalidCtx, &vocablov1.GetProgressRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	progress, err := userWordClient.GetProgress(withToken(ctx), &vocablov1.GetProgressRequest{})
+	if assert.Nil(t, err) {
+		assert.Zero(t, progress.TotalWords)
+	}
+
+	//The health and reflection services are public
+	health, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(),
+		&grpc_health_v1.HealthCheckRequest{Service: vocablov1.UserWordService_ServiceDesc.ServiceName})
+	if assert.Nil(t, err) {
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.Status)
+	}
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reflectionRes, err := stream.Recv()
+	if assert.Nil(t, err) {
+		var services []string
+		for _, service := range reflectionRes.GetListServicesResponse().GetService() {
+			services = append(services, service.Name)
+		}
+		assert.Contains(t, services, vocablov1.QuizService_ServiceDesc.ServiceName)
+		assert.Contains(t, services, vocablov1.WordService_ServiceDesc.ServiceName)
+	}
+}
+
+func TestGrpcUserWords(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	conn := grpcConn(t)
+	userWordClient := vocablov1.NewUserWordServiceClient(conn)
+	tokenCtx := withToken(ctx)
+
+	definitions := []*vocablov1.Definition{{Definition: testWordForm1.Definitions[0].Definition}}
+	created, err := userWordClient.CreateUserWord(tokenCtx, &vocablov1.CreateUserWordRequest{
+		Term: testWordForm1.Term, Lang: testWordForm1.Lang, Definitions: definitions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testWordForm1.Term, created.UserWord.Term)
+	_, err = userWordClient.CreateUserWord(tokenCtx, &vocablov1.CreateUserWordRequest{Term: "palabra", Lang: "xx",
+		Definitions: definitions})
+	assertStatus(t, err, codes.InvalidArgument, customerrors.LANGUAGE_NOT_FOUND)
+
+	_, err = userWordClient.UpdateUserWord(tokenCtx, &vocablov1.UpdateUserWordRequest{
+		Id: created.UserWord.Id, Term: utils.GetStringPointer("worse")})
+	assertStatus(t, err, codes.FailedPrecondition, customerrors.PRECONDITION_REQUIRED)
+	updated, err := userWordClient.UpdateUserWord(tokenCtx, &vocablov1.UpdateUserWordRequest{
+		Id: created.UserWord.Id, Term: utils.GetStringPointer("worse"), ExpectedVersion: &created.UserWord.Version})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "worse", updated.UserWord.Term)
+		assert.Len(t, updated.UserWord.Definitions, 1)
+		assert.Greater(t, updated.UserWord.Version, created.UserWord.Version)
+	}
+	//A change on the version before the update is stale, and the error has the current version
+	_, err = userWordClient.UpdateUserWord(tokenCtx, &vocablov1.UpdateUserWordRequest{
+		Id: created.UserWord.Id, Term: utils.GetStringPointer("lost"), ExpectedVersion: &created.UserWord.Version})
+	assertStatus(t, err, codes.FailedPrecondition, customerrors.STALE_VERSION)
+	for _, detail := range status.Convert(err).Details() {
+		if info, isInfo := detail.(*errdetails.ErrorInfo); isInfo {
+			assert.Equal(t, strconv.Itoa(int(updated.GetUserWord().GetVersion())), info.Metadata["version"])
+		}
+	}
+	got, err := userWordClient.GetUserWord(tokenCtx, &vocablov1.GetUserWordRequest{Id: created.UserWord.Id})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "worse", got.UserWord.Term)
+	}
+	page, err := userWordClient.SearchUserWords(tokenCtx, &vocablov1.SearchUserWordsRequest{
+		Term: utils.GetStringPointer("wor")})
+	if assert.Nil(t, err) && assert.Len(t, page.UserWords, 1) {
+		assert.Equal(t, created.UserWord.Id, page.UserWords[0].Id)
+		assert.Equal(t, int32(1), page.NElements)
+	}
+	languages, err := vocablov1.NewWordServiceClient(conn).ListLanguages(tokenCtx, &vocablov1.ListLanguagesRequest{})
+	if assert.Nil(t, err) {
+		assert.Len(t, languages.Languages, 2)
+	}
+
+	_, err = userWordClient.DeleteUserWord(tokenCtx, &vocablov1.DeleteUserWordRequest{Id: created.UserWord.Id})
+	assert.Nil(t, err)
+	_, err = userWordClient.GetUserWord(tokenCtx, &vocablov1.GetUserWordRequest{Id: created.UserWord.Id})
+	assertStatus(t, err, codes.NotFound, customerrors.NOT_FOUND)
+}
+
+func TestGrpcUserWordOwnership(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	userWordClient := vocablov1.NewUserWordServiceClient(grpcConn(t))
+	tokenCtx := withToken(ctx)
+	otherWord := client.UserWord.Query().Where(entuserword.HasUserWith(user.UsernameEQ(testUserForm2.Username))).
+		OnlyX(ctx)
+
+	_, err := userWordClient.GetUserWord(tokenCtx, &vocablov1.GetUserWordRequest{Id: otherWord.ID.String()})
+	assertStatus(t, err, codes.PermissionDenied, customerrors.NOT_ALLOWED_RESOURCE)
+	otherVersion := int32(otherWord.Version)
+	_, err = userWordClient.UpdateUserWord(tokenCtx, &vocablov1.UpdateUserWordRequest{Id: otherWord.ID.String(),
+		Term: utils.GetStringPointer("stolen"), ExpectedVersion: &otherVersion})
+	assertStatus(t, err, codes.PermissionDenied, customerrors.NOT_ALLOWED_RESOURCE)
+	_, err = userWordClient.DeleteUserWord(tokenCtx, &vocablov1.DeleteUserWordRequest{Id: otherWord.ID.String()})
+	assertStatus(t, err, codes.PermissionDenied, customerrors.NOT_ALLOWED_RESOURCE)
+	page, err := userWordClient.SearchUserWords(tokenCtx, &vocablov1.SearchUserWordsRequest{})
+	if assert.Nil(t, err) && assert.Len(t, page.UserWords, 1) {
+		assert.Equal(t, testWordForm1.Term, page.UserWords[0].Term)
+	}
+	assert.Equal(t, otherWord.Term, client.UserWord.GetX(ctx, otherWord.ID).Term)
+}
+
+func TestGrpcQuiz(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+	quizClient := vocablov1.NewQuizServiceClient(grpcConn(t))
+	tokenCtx := withToken(ctx)
+
+	_, err := quizClient.CreateQuiz(tokenCtx, &vocablov1.CreateQuizRequest{NQuestions: 4})
+	assertStatus(t, err, codes.FailedPrecondition, customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ)
+
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLangID(
+		client.Language.Query().FirstIDX(ctx)).SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	created, err := quizClient.CreateQuiz(tokenCtx, &vocablov1.CreateQuizRequest{NQuestions: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, created.Quiz.Questions, 4)
+	for _, question := range created.Quiz.Questions {
+		answerPos := question.CorrectOptionPos
+		question.AnswerPos = &answerPos
+	}
+	answered, err := quizClient.AnswerQuiz(tokenCtx, &vocablov1.AnswerQuizRequest{Quiz: created.Quiz})
+	if assert.Nil(t, err) {
+		assert.Equal(t, int32(100), answered.Score)
+	}
+
+	//The positions are checked before answering
+	outOfRange := int32(len(created.Quiz.Questions[0].Options))
+	created.Quiz.Questions[0].AnswerPos = &outOfRange
+	_, err = quizClient.AnswerQuiz(tokenCtx, &vocablov1.AnswerQuizRequest{Quiz: created.Quiz})
+	assertStatus(t, err, codes.InvalidArgument, customerrors.INVALID_REQUEST)
+}
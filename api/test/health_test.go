@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"vocablo/conf"
+	"vocablo/customerrors"
+	"vocablo/svc"
+	"vocablo/svc/health"
+	"vocablo/svc/mail"
+	"vocablo/svc/word"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readinessReport returns the report of a readiness response
+func readinessReport(t *testing.T, body []byte) health.Report {
+	var respBody struct {
+		Data health.Report `json:"data"`
+	}
+	err := json.Unmarshal(body, &respBody)
+	if err != nil {
+		t.Fatalf("Error unmarshalling response body: %s", err)
+	}
+	return respBody.Data
+}
+
+func TestLiveness(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	resp := testEnv.MakeRequest("GET", "/healthz", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestReadiness(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	resp := testEnv.MakeRequest("GET", "/readyz", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	report := readinessReport(t, resp.Body.Bytes())
+	assert.Equal(t, health.UP_STATUS, report.Status)
+	for _, name := range []string{health.DB_COMPONENT, health.MIGRATIONS_COMPONENT, health.OUTBOX_COMPONENT, health.DICTIONARY_COMPONENT} {
+		assert.Equal(t, health.UP_STATUS, report.Components[name].Status, name)
+	}
+}
+
+func TestReadinessDBDown(t *testing.T) {
+	client, teardown := StartTest(t)
+	defer teardown(t)
+	client.Close()
+	resp := testEnv.MakeRequest("GET", "/readyz", nil)
+	assert.Equal(t, 503, resp.Code, "Response status should be 503")
+	assert.Contains(t, resp.Body.String(), customerrors.NOT_READY)
+	report := readinessReport(t, resp.Body.Bytes())
+	assert.Equal(t, health.DOWN_STATUS, report.Status)
+	//The error of the driver isn't shown to the callers
+	assert.Equal(t, health.Component{Status: health.DOWN_STATUS, Detail: health.UNREACHABLE_DETAIL},
+		report.Components[health.DB_COMPONENT])
+	assert.Equal(t, health.Component{Status: health.DOWN_STATUS, Detail: health.UNREACHABLE_DETAIL},
+		report.Components[health.OUTBOX_COMPONENT])
+}
+
+func TestReadinessOutboxBacklog(t *testing.T) {
+	client, teardown := StartTest(t)
+	defer teardown(t)
+	conf.Get().Health.MaxOutboxBacklog = 1
+	for i := 0; i < 2; i++ {
+		client.Outbox.Create().SetTo("jane@example.com").SetSubject("Hi").SetText("Hi").SetStatus(mail.PENDING_STATUS).SaveX(context.Background())
+	}
+	//A backlog is reported, but the instance can still serve traffic
+	resp := testEnv.MakeRequest("GET", "/readyz", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	report := readinessReport(t, resp.Body.Bytes())
+	assert.Equal(t, health.DEGRADED_STATUS, report.Status)
+	assert.Equal(t, health.Component{Status: health.DEGRADED_STATUS, Detail: "2 emails pending"}, report.Components[health.OUTBOX_COMPONENT])
+}
+
+func TestReadinessDictionaryCircuit(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	var calls atomic.Int32
+	dictionary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer dictionary.Close()
+	conf.Get().Dictionary.Url = dictionary.URL
+	threshold := conf.Get().Dictionary.FailureThreshold
+
+	body, _ := json.Marshal(word.SearchForm{Term: WORD_TO_SEARCH, Lang: "en"})
+	for i := 0; i < threshold; i++ {
+		resp := testEnv.MakeAuthRequest("POST", "/api/word/search", utils.GetStringPointer(string(body)), ctx)
+		assert.Equal(t, 503, resp.Code, "Response status should be 503")
+		assert.Contains(t, resp.Body.String(), customerrors.DICTIONARY_UNAVAILABLE)
+	}
+	assert.Equal(t, word.CIRCUIT_OPEN, svc.Get().Word.DictionaryState())
+	//Once open, the dictionary is not called
+	resp := testEnv.MakeAuthRequest("POST", "/api/word/search", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 503, resp.Code, "Response status should be 503")
+	assert.Equal(t, int32(threshold), calls.Load())
+
+	resp = testEnv.MakeRequest("GET", "/readyz", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	report := readinessReport(t, resp.Body.Bytes())
+	assert.Equal(t, health.DEGRADED_STATUS, report.Components[health.DICTIONARY_COMPONENT].Status)
+}
+
+func TestReadinessShuttingDown(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	svc.Get().Health.ShutDown()
+	resp := testEnv.MakeRequest("GET", "/readyz", nil)
+	assert.Equal(t, 503, resp.Code, "Response status should be 503")
+	assert.Equal(t, health.SHUTTING_DOWN_STATUS, readinessReport(t, resp.Body.Bytes()).Status)
+	//The process is still alive while it drains
+	resp = testEnv.MakeRequest("GET", "/healthz", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"vocablo/conf"
+	"vocablo/ent"
+	entuser "vocablo/ent/user"
+	"vocablo/svc/auth"
+	"vocablo/utils"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeKeyFiles writes the private and public PEM files of the key, returning their paths
+func writeKeyFiles(t *testing.T, name string, privateKey interface{}, publicKey interface{}) (string, string) {
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath := filepath.Join(t.TempDir(), name+".pem")
+	publicPath := filepath.Join(t.TempDir(), name+".pub.pem")
+	err = os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func setJwtKeys(t *testing.T, jwtConf conf.JwtConf) {
+	conf.Get().Jwt = jwtConf
+	err := utils.SetupJwtKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newSession(t *testing.T, ctx context.Context, client *ent.Client) context.Context {
+	sessionUser := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	loginResult, err := auth.GenerateLoginResult(sessionUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return context.WithValue(context.WithValue(ctx, utils.CsrfKey, loginResult.HashedCsrfToken),
+		utils.JwtKey, loginResult.JWTToken)
+}
+
+func tokenHeader(t *testing.T, ctx context.Context) map[string]interface{} {
+	token, _, err := new(jwt.Parser).ParseUnverified(ctx.Value(utils.JwtKey).(string), &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token.Header
+}
+
+func TestJwtKeyRotation(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	defer setJwtKeys(t, conf.JwtConf{})
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivatePath, rsaPublicPath := writeKeyFiles(t, "rsa", rsaKey, &rsaKey.PublicKey)
+	edPrivatePath, _ := writeKeyFiles(t, "ed", edPrivateKey, edPublicKey)
+
+	setJwtKeys(t, conf.JwtConf{ActiveKey: "rsa", Keys: []conf.JwtKeyConf{{Id: "rsa", PrivateKeyFile: rsaPrivatePath}}})
+	rsaSessionCtx := newSession(t, ctx, client)
+	assert.Equal(t, "rsa", tokenHeader(t, rsaSessionCtx)["kid"])
+	assert.Equal(t, "RS256", tokenHeader(t, rsaSessionCtx)["alg"])
+	resp := testEnv.MakeAuthRequest("GET", "/api/self", nil, rsaSessionCtx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	//After rotating, the tokens signed with the previous key keep working while it is kept
+	setJwtKeys(t, conf.JwtConf{ActiveKey: "ed", Keys: []conf.JwtKeyConf{{Id: "ed", PrivateKeyFile: edPrivatePath},
+		{Id: "rsa", PublicKeyFile: rsaPublicPath}}})
+	edSessionCtx := newSession(t, ctx, client)
+	assert.Equal(t, "EdDSA", tokenHeader(t, edSessionCtx)["alg"])
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, edSessionCtx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, rsaSessionCtx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	resp = testEnv.MakeRequest("GET", "/.well-known/jwks.json", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var jwks utils.JWKS
+	err = json.Unmarshal(resp.Body.Bytes(), &jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(jwks.Keys))
+
+	//Once the previous key is removed, its tokens are rejected
+	setJwtKeys(t, conf.JwtConf{ActiveKey: "ed", Keys: []conf.JwtKeyConf{{Id: "ed", PrivateKeyFile: edPrivatePath}}})
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, rsaSessionCtx)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, edSessionCtx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestJwtKeyForgedAlgorithm(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	//A token with a kid can't be verified with the HS256 secret
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.JWTClaim{Id: ctx.Value(utils.UserIdKey).(uuid.UUID)})
+	token.Header["kid"] = "rsa"
+	signedToken, err := token.SignedString([]byte(conf.Get().JwtKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = utils.ValidateToken(signedToken)
+	assert.Error(t, err)
+}
+
+func TestConfRefusesDefaultSecretInProd(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+
+	conf.Get().Env = "prod"
+	defer func() { conf.Get().Env = "dev" }()
+	err := conf.Validate()
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "JWT_SECRET"))
+	conf.Get().JwtKey = "a-real-secret"
+	assert.NoError(t, conf.Validate())
+}
+
+func TestJwtKeySharedSecretDisabled(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	defer setJwtKeys(t, conf.JwtConf{})
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivatePath, _ := writeKeyFiles(t, "rsa", rsaKey, &rsaKey.PublicKey)
+
+	//The session of the setup is signed with the shared secret, which stops working with the keys
+	assert.Nil(t, tokenHeader(t, ctx)["kid"])
+	setJwtKeys(t, conf.JwtConf{ActiveKey: "rsa", Keys: []conf.JwtKeyConf{{Id: "rsa", PrivateKeyFile: rsaPrivatePath}}})
+	resp := testEnv.MakeAuthRequest("GET", "/api/self", nil, ctx)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+
+	//Unless it is still accepted while migrating to them
+	setJwtKeys(t, conf.JwtConf{ActiveKey: "rsa", Keys: []conf.JwtKeyConf{{Id: "rsa", PrivateKeyFile: rsaPrivatePath}},
+		AcceptSharedSecret: true})
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
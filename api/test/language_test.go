@@ -0,0 +1,91 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/svc"
+	"vocablo/svc/language"
+	"vocablo/svc/userword"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListLanguages(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("GET", "/api/language", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var languages []ent.Language
+	languagesStr, _ := json.Marshal(respBody.Data)
+	err = json.Unmarshal(languagesStr, &languages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(languages))
+	assert.Equal(t, "en", languages[0].Code)
+	assert.Equal(t, "English", languages[0].Name)
+	assert.Equal(t, "español", languages[1].NativeName)
+	assert.Equal(t, language.LTR_DIRECTION, languages[1].Direction)
+}
+
+func TestSeedLanguagesIdempotent(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, false, nil)
+	defer teardown(t)
+
+	err := svc.Get().Language.Seed(ctx, []string{"en", "es", "ar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, client.Language.Query().CountX(ctx))
+	err = svc.Get().Language.Seed(ctx, []string{"xx"})
+	assert.IsType(t, customerrors.InvalidLanguageCodeError{}, err)
+}
+
+func TestCreateUserWordMissingLanguage(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	form := userword.CreateForm{Term: testWordForm1.Term, Lang: "fr", Definitions: testWordForm1.Definitions}
+	body, _ := json.Marshal(form)
+	resp := testEnv.MakeAuthRequest("POST", "/api/userword", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.LANGUAGE_NOT_FOUND, *respBody.ErrorCode)
+}
+
+func TestAdminCreateLanguageInvalidCode(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, SetupAdminTest)
+	defer teardown(t)
+
+	body, _ := json.Marshal(language.CreateForm{Code: "xx"})
+	resp := testEnv.MakeAuthRequest("POST", "/api/admin/language", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.INVALID_LANGUAGE_CODE, *respBody.ErrorCode)
+
+	body, _ = json.Marshal(language.CreateForm{Code: "he"})
+	resp = testEnv.MakeAuthRequest("POST", "/api/admin/language", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, language.RTL_DIRECTION, respBody.Data.(map[string]interface{})["direction"])
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"vocablo/customerrors"
+	entuser "vocablo/ent/user"
+	"vocablo/svc/auth"
+	"vocablo/svc/verificationcode"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// requestMagicLink asks for a login link and returns the token sent by email and the browser cookies
+func requestMagicLink(t *testing.T, email string) (string, []*http.Cookie) {
+	body, _ := json.Marshal(verificationcode.MagicLinkForm{Email: email})
+	resp := testEnv.MakeRequest("POST", "/api/public/magic-link", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	mail := testEnv.LastMail(t)
+	if mail == nil {
+		return "", resp.Result().Cookies()
+	}
+	return mail.Message[strings.LastIndex(mail.Message, "/magic-link/")+len("/magic-link/"):], resp.Result().Cookies()
+}
+
+func TestMagicLinkLoginOk(t *testing.T) {
+	_, teardown, _ := SetupTest(t, false, nil)
+	defer teardown(t)
+
+	token, cookies := requestMagicLink(t, testUserForm1.Email)
+	body, _ := json.Marshal(verificationcode.UseMagicLinkForm{Token: token})
+	resp := testEnv.MakeRequestWithCookies("POST", "/api/public/magic-link/login", utils.GetStringPointer(string(body)), cookies)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.True(t, strings.Contains(strings.Join(resp.Header().Values("Set-Cookie"), ";"), "JWT_TOKEN"))
+
+	//The link can only be used once
+	resp = testEnv.MakeRequestWithCookies("POST", "/api/public/magic-link/login", utils.GetStringPointer(string(body)), cookies)
+	assert.Equal(t, 409, resp.Code, "Response status should be 409")
+}
+
+func TestMagicLinkOtherBrowser(t *testing.T) {
+	_, teardown, _ := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	token, _ := requestMagicLink(t, testUserForm1.Email)
+	_, otherCookies := requestMagicLink(t, testUserForm2.Email)
+	body, _ := json.Marshal(verificationcode.UseMagicLinkForm{Token: token})
+	resp := testEnv.MakeRequestWithCookies("POST", "/api/public/magic-link/login", utils.GetStringPointer(string(body)), otherCookies)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.INVALID_MAGIC_LINK, *respBody.ErrorCode)
+}
+
+func TestMagicLinkUnknownEmail(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+
+	_, cookies := requestMagicLink(t, testUserForm2.Email)
+	//The response doesn't reveal that the email doesn't exist
+	assert.Equal(t, 1, len(cookies))
+	assert.Nil(t, testEnv.LastMail(t))
+}
+
+func TestMagicLinkTakesOverNotValidatedUser(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, false, nil)
+	defer teardown(t)
+	//A session of whoever registered the account with the email
+	registeredUser := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	loginResult, err := auth.GenerateLoginResult(registeredUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionCtx := context.WithValue(ctx, utils.JwtKey, loginResult.JWTToken)
+	sessionCtx = context.WithValue(sessionCtx, utils.CsrfKey, loginResult.HashedCsrfToken)
+	sessionCtx = context.WithValue(sessionCtx, utils.UserIdKey, registeredUser.ID)
+
+	token, cookies := requestMagicLink(t, testUserForm1.Email)
+	body, _ := json.Marshal(verificationcode.UseMagicLinkForm{Token: token})
+	resp := testEnv.MakeRequestWithCookies("POST", "/api/public/magic-link/login", utils.GetStringPointer(string(body)), cookies)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.True(t, client.User.GetX(ctx, registeredUser.ID).Validated)
+
+	//The password and the session of whoever registered it stop working
+	body, _ = json.Marshal(&testUserForm1)
+	resp = testEnv.MakeRequest("POST", "/api/public/login", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, sessionCtx)
+	assert.Equal(t, 401, resp.Code, "Response status should be 401")
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"vocablo/svc/auth"
+	"vocablo/svc/mail"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetPasswordMailTemplate(t *testing.T) {
+	_, teardown, _ := SetupTest(t, true, nil)
+	defer teardown(t)
+	resp := testEnv.MakeRequest("POST", "/api/public/forgotten-password/"+testUserForm1.Username, nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	sent := testEnv.LastMail(t)
+	if sent == nil {
+		t.Fatal("No email sent")
+	}
+	assert.Equal(t, "Reset your Vocablo password", sent.Subject)
+	assert.Contains(t, sent.HTML, codeFromMail(t))
+	assert.Contains(t, sent.HTML, "<title>Reset your Vocablo password</title>")
+}
+
+func TestSignUpLocale(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	body, _ := json.Marshal(&auth.SignUpForm{Username: "localized", Email: "localized@gmail.com", Password: "Localized-pass1", Locale: "es-ES"})
+	resp := testEnv.MakeRequest("POST", "/api/public/register", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	sent := testEnv.LastMail(t)
+	if sent == nil {
+		t.Fatal("No email sent")
+	}
+	assert.Equal(t, "Valida tu cuenta de Vocablo", sent.Subject)
+}
+
+func TestMailTemplatePreview(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, SetupAdminTest)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("GET", "/api/admin/mail/templates", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var templatesBody struct {
+		Data []mail.TemplateInfo `json:"data"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &templatesBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, templatesBody.Data, mail.TemplateInfo{Name: mail.MAGIC_LINK_TEMPLATE, Locales: []string{"en", "es"}})
+
+	resp = testEnv.MakeAuthRequest("GET", "/api/admin/mail/templates/"+mail.MAGIC_LINK_TEMPLATE+"/preview?locale=es", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var previewBody struct {
+		Data mail.Message `json:"data"`
+	}
+	err = json.Unmarshal(resp.Body.Bytes(), &previewBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Tu enlace de inicio de sesión", previewBody.Data.Subject)
+	assert.Contains(t, previewBody.Data.Text, "jane")
+
+	resp = testEnv.MakeAuthRequest("GET", "/api/admin/mail/templates/"+mail.MAGIC_LINK_TEMPLATE+"/preview?format=html", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.True(t, strings.HasPrefix(resp.Header().Get("Content-Type"), "text/html"))
+	assert.Contains(t, resp.Body.String(), "Log in")
+
+	resp = testEnv.MakeAuthRequest("GET", "/api/admin/mail/templates/unknown/preview", nil, ctx)
+	assert.Equal(t, 404, resp.Code, "Response status should be 404")
+}
+
+func TestMailMessageMultipart(t *testing.T) {
+	message := mail.Message{To: "jane@example.com", Subject: "Contraseña cambiada", Text: "Hola, ¿qué tal?", HTML: "<p>Hola</p>"}
+	raw, err := message.Bytes("Vocablo Ñ", "noreply@vocablo.com", "vocablo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, message.Subject, subject)
+	from, err := parsed.Header.AddressList("From")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Vocablo Ñ", from[0].Name)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "multipart/alternative", mediaType)
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part)
+		parts = append(parts, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	assert.Equal(t, []string{"text/plain; charset=utf-8: " + message.Text, "text/html; charset=utf-8: " + message.HTML}, parts)
+}
+
+func TestMailHeaderInjection(t *testing.T) {
+	message := mail.Message{To: "jane@example.com\r\nBcc: victim@example.com", Subject: "Hi", Text: "Hi"}
+	_, err := message.Bytes("Vocablo", "noreply@vocablo.com", "vocablo.com")
+	assert.Error(t, err)
+	message = mail.Message{To: "jane@example.com", Subject: "Hi", Text: "Hi",
+		ListUnsubscribe: "https://vocablo.com/x>\r\nBcc: victim@example.com"}
+	_, err = message.Bytes("Vocablo", "noreply@vocablo.com", "vocablo.com")
+	assert.Error(t, err)
+}
+
+func TestMailListUnsubscribe(t *testing.T) {
+	message := mail.Message{To: "jane@example.com", Subject: "Hi", Text: "Hi",
+		ListUnsubscribe: "https://vocablo.com/api/public/unsubscribe/token"}
+	raw, err := message.Bytes("Vocablo", "noreply@vocablo.com", "vocablo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "<https://vocablo.com/api/public/unsubscribe/token>", parsed.Header.Get("List-Unsubscribe"))
+	assert.Equal(t, "List-Unsubscribe=One-Click", parsed.Header.Get("List-Unsubscribe-Post"))
+
+	//The emails that were requested don't have them
+	message.ListUnsubscribe = ""
+	raw, err = message.Bytes("Vocablo", "noreply@vocablo.com", "vocablo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(raw), "List-Unsubscribe")
+}
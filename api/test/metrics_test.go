@@ -0,0 +1,102 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vocablo/api"
+	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/metrics"
+	"vocablo/svc/auth"
+	"vocablo/utils"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// dbQueries returns how many statements of the kind have been timed
+func dbQueries(t *testing.T, statement string) uint64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "vocablo_db_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if metric.GetLabel()[0].GetValue() == statement {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	//Without a separate port they are served with the API
+	conf.Get().Metrics.Port = ""
+	testEnv.Router = api.GetRouter()
+
+	failures := testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.UNKNOWN_USER_REASON))
+	body, _ := json.Marshal(auth.LoginForm{Username: "nobody", Password: "Nobody-password1"})
+	resp := testEnv.MakeRequest("POST", "/api/public/login", utils.GetStringPointer(string(body)))
+	assert.Equal(t, failures+1, testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.UNKNOWN_USER_REASON)))
+
+	//The requests are labelled with the route template
+	resp = testEnv.MakeRequest("GET", "/metrics", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.Contains(t, resp.Body.String(), `vocablo_http_requests_total{method="POST",route="/api/public/login",status="`)
+	assert.Contains(t, resp.Body.String(), "vocablo_login_failures_total")
+	assert.Contains(t, resp.Body.String(), "go_goroutines")
+}
+
+func TestMetricsSeparatePort(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	conf.Get().Metrics.Port = "9090"
+	//The public router doesn't expose them
+	resp := testEnv.MakeRequest("GET", "/metrics", nil)
+	assert.Equal(t, 404, resp.Code, "Response status should be 404")
+
+	server := api.NewMetricsServer()
+	assert.Equal(t, "9090", server.Addr[len(server.Addr)-4:])
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, req)
+	assert.Equal(t, 200, recorder.Code, "Response status should be 200")
+	assert.Contains(t, recorder.Body.String(), "vocablo_http_requests_total")
+}
+
+func TestMetricsDriver(t *testing.T) {
+	driver, err := entsql.Open("sqlite3", "file:metrics?mode=memory&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ent.NewClient(ent.Driver(metrics.NewDriver(driver)))
+	defer client.Close()
+	ctx := context.Background()
+	err = client.Schema.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selects, inserts := dbQueries(t, "select"), dbQueries(t, "insert")
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Language.Create().SetCode("en").SetName("English").SaveX(ctx)
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Language.Query().AllX(ctx)
+	assert.Equal(t, selects+1, dbQueries(t, "select"))
+	assert.Equal(t, inserts+1, dbQueries(t, "insert"))
+}
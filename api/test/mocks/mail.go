@@ -2,11 +2,44 @@ package mocks
 
 import (
 	"fmt"
+	"sync"
+	"vocablo/svc/mail"
 )
 
-type MailSvcMock struct{}
+type SentMail struct {
+	To      string
+	Subject string
+	Message string
+	HTML    string
+	// One-click unsubscribe link of the headers
+	ListUnsubscribe string
+}
+
+// MailSvcMock doesn't send anything, but keeps the mails so the tests can check them. While Err is set,
+// it fails as an unavailable server would
+type MailSvcMock struct {
+	mu   sync.Mutex
+	Sent []SentMail
+	Err  error
+}
 
-func (s *MailSvcMock) SendMail(to string, subject string, message string) error {
-	fmt.Println("Sending mail to: ", to, " with subject: ", subject, " and message: ", message)
+func (s *MailSvcMock) Send(message *mail.Message) error {
+	fmt.Println("Sending mail to: ", message.To, " with subject: ", message.Subject, " and message: ", message.Text)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+	s.Sent = append(s.Sent, SentMail{To: message.To, Subject: message.Subject, Message: message.Text, HTML: message.HTML,
+		ListUnsubscribe: message.ListUnsubscribe})
 	return nil
 }
+
+func (s *MailSvcMock) Last() *SentMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.Sent) == 0 {
+		return nil
+	}
+	return &s.Sent[len(s.Sent)-1]
+}
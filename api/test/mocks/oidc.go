@@ -0,0 +1,154 @@
+package mocks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+const oidcMockKeyId = "mock-key"
+
+type oidcMockAuthorization struct {
+	subject       string
+	email         string
+	emailVerified bool
+	nonce         string
+	challenge     string
+}
+
+// OIDCServerMock is a minimal OpenID Connect provider that implements discovery, JWKS and the token endpoint
+// with PKCE verification
+type OIDCServerMock struct {
+	Server   *httptest.Server
+	ClientId string
+	key      *rsa.PrivateKey
+	mu       sync.Mutex
+	codes    map[string]oidcMockAuthorization
+}
+
+func NewOIDCServerMock(clientId string) *OIDCServerMock {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	mock := &OIDCServerMock{ClientId: clientId, key: key, codes: map[string]oidcMockAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", mock.discovery)
+	mux.HandleFunc("/jwks", mock.jwks)
+	mux.HandleFunc("/token", mock.token)
+	mock.Server = httptest.NewServer(mux)
+	return mock
+}
+
+func (m *OIDCServerMock) Issuer() string {
+	return m.Server.URL
+}
+
+func (m *OIDCServerMock) Close() {
+	m.Server.Close()
+}
+
+// Authorize simulates the user logging in at the provider from the given authorization url. It returns the code
+// and state the provider would send back to the redirect url
+func (m *OIDCServerMock) Authorize(authUrl string, subject string, email string, emailVerified bool) (code string, state string, err error) {
+	parsedUrl, err := url.Parse(authUrl)
+	if err != nil {
+		return "", "", err
+	}
+	query := parsedUrl.Query()
+	if query.Get("client_id") != m.ClientId || query.Get("code_challenge_method") != "S256" {
+		return "", "", errors.New("invalid authorization request")
+	}
+	codeBytes := make([]byte, 16)
+	rand.Read(codeBytes)
+	code = hex.EncodeToString(codeBytes)
+	m.mu.Lock()
+	m.codes[code] = oidcMockAuthorization{subject: subject, email: email, emailVerified: emailVerified,
+		nonce: query.Get("nonce"), challenge: query.Get("code_challenge")}
+	m.mu.Unlock()
+	return code, query.Get("state"), nil
+}
+
+func (m *OIDCServerMock) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                m.Issuer(),
+		"authorization_endpoint":                m.Issuer() + "/authorize",
+		"token_endpoint":                        m.Issuer() + "/token",
+		"jwks_uri":                              m.Issuer() + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *OIDCServerMock) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": oidcMockKeyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *OIDCServerMock) token(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	m.mu.Lock()
+	authorization, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.challenge {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            m.Issuer(),
+		"sub":            authorization.subject,
+		"aud":            m.ClientId,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          authorization.email,
+		"email_verified": authorization.emailVerified,
+	})
+	idToken.Header["kid"] = oidcMockKeyId
+	signedIdToken, err := idToken.SignedString(m.key)
+	if err != nil {
+		writeJson(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]interface{}{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signedIdToken,
+	})
+}
+
+func writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
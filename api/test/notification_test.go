@@ -0,0 +1,278 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"vocablo/cli"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/language"
+	"vocablo/ent/user"
+	"vocablo/schema"
+	"vocablo/svc"
+	"vocablo/svc/notification"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// reminderNow is after the default reminder time in UTC
+var reminderNow = time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC)
+
+func createQuizResult(t *testing.T, client *ent.Client, ctx context.Context, date time.Time) {
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	client.QuizResult.Create().SetUser(mainUser).SetScore(100).SetNQuestions(4).SetNCorrect(4).
+		SetCreationDate(date).SaveX(ctx)
+}
+
+// digestNow is a Monday, the default digest weekday
+var digestNow = time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+
+// SetupDigestTest gives the main user a week of activity before digestNow
+func SetupDigestTest(client *ent.Client, t *testing.T, ctx context.Context) {
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	lang := client.Language.Query().Where(language.CodeEqualFold("en")).OnlyX(ctx)
+	definitions := []schema.Definition{{Definition: "a fruit"}}
+	apple := client.UserWord.Create().SetTerm("apple").SetLang(lang).SetDefinitions(definitions).SetUser(mainUser).
+		SetCreationDate(digestNow.AddDate(0, 0, -3)).SetLearningProgress(100).SetLearnedDate(digestNow.AddDate(0, 0, -2)).SaveX(ctx)
+	banana := client.UserWord.Create().SetTerm("banana").SetLang(lang).SetDefinitions(definitions).SetUser(mainUser).
+		SetCreationDate(digestNow.AddDate(0, 0, -2)).SetLearningProgress(20).SaveX(ctx)
+	cherry := client.UserWord.Create().SetTerm("cherry").SetLang(lang).SetDefinitions(definitions).SetUser(mainUser).
+		SetCreationDate(digestNow.AddDate(0, 0, -20)).SaveX(ctx)
+	client.UserWord.Create().SetTerm("damson").SetLang(lang).SetDefinitions(definitions).SetUser(mainUser).
+		SetCreationDate(digestNow.AddDate(0, 0, -30)).SetLearningProgress(100).SaveX(ctx)
+	client.QuizResult.Create().SetUser(mainUser).SetScore(33).SetNQuestions(3).SetNCorrect(1).SetCreationDate(digestNow.AddDate(0, 0, -1)).
+		SetAnswers([]schema.QuizAnswer{{UserWordID: banana.ID, Term: banana.Term}, {UserWordID: apple.ID, Term: apple.Term, Correct: true},
+			{UserWordID: cherry.ID, Term: cherry.Term}}).SaveX(ctx)
+	client.QuizResult.Create().SetUser(mainUser).SetScore(50).SetNQuestions(2).SetNCorrect(1).SetCreationDate(digestNow.AddDate(0, 0, -2)).
+		SetAnswers([]schema.QuizAnswer{{UserWordID: banana.ID, Term: banana.Term}, {UserWordID: cherry.ID, Term: cherry.Term, Correct: true}}).SaveX(ctx)
+	//The quizzes of previous weeks are not summarised
+	client.QuizResult.Create().SetUser(mainUser).SetScore(0).SetNQuestions(1).SetNCorrect(0).SetCreationDate(digestNow.AddDate(0, 0, -10)).
+		SetAnswers([]schema.QuizAnswer{{UserWordID: apple.ID, Term: apple.Term}}).SaveX(ctx)
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+
+	resp := testEnv.MakeAuthRequest("GET", "/api/self/notifications", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var respBody struct {
+		Data notification.Preferences `json:"data"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, notification.Preferences{RemindersEnabled: true, ReminderTime: "18:00", Timezone: "UTC", DigestEnabled: false}, respBody.Data)
+
+	body, _ := json.Marshal(notification.PreferencesForm{ReminderTime: utils.GetStringPointer("08:30"), Timezone: utils.GetStringPointer("Europe/Madrid"),
+		DigestEnabled: utils.GetBoolPointer(true)})
+	resp = testEnv.MakeAuthRequest("PUT", "/api/self/notifications", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, notification.Preferences{RemindersEnabled: true, ReminderTime: "08:30", Timezone: "Europe/Madrid", DigestEnabled: true}, respBody.Data)
+
+	body, _ = json.Marshal(notification.PreferencesForm{Timezone: utils.GetStringPointer("Mars/Olympus_Mons")})
+	resp = testEnv.MakeAuthRequest("PUT", "/api/self/notifications", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Contains(t, resp.Body.String(), customerrors.INVALID_TIMEZONE)
+
+	body, _ = json.Marshal(notification.PreferencesForm{ReminderTime: utils.GetStringPointer("25:00")})
+	resp = testEnv.MakeAuthRequest("PUT", "/api/self/notifications", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Contains(t, resp.Body.String(), customerrors.INVALID_REMINDER_TIME)
+}
+
+func TestSendReminders(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+
+	//Before the reminder time nothing is sent
+	sent, err := svc.Get().Notification.SendReminders(ctx, reminderNow.Add(-2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	//Only the validated user is reminded, and once a day
+	sent, err = svc.Get().Notification.SendReminders(ctx, reminderNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	reminder := testEnv.LastMail(t)
+	if assert.NotNil(t, reminder) {
+		assert.Equal(t, testUserForm1.Email, reminder.To)
+		assert.Equal(t, "Your words are waiting for you", reminder.Subject)
+		assert.Contains(t, reminder.Message, "1 word to learn")
+	}
+	sent, err = svc.Get().Notification.SendReminders(ctx, reminderNow.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	//The next day the streak of the previous days is at risk
+	createQuizResult(t, client, ctx, reminderNow)
+	createQuizResult(t, client, ctx, reminderNow.AddDate(0, 0, -1))
+	sent, err = svc.Get().Notification.SendReminders(ctx, reminderNow.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	reminder = testEnv.LastMail(t)
+	if assert.NotNil(t, reminder) {
+		assert.Equal(t, "Keep your 2-day streak going", reminder.Subject)
+	}
+
+	//There is nothing to remind to whoever already practised today
+	createQuizResult(t, client, ctx, reminderNow.AddDate(0, 0, 2).Add(-time.Hour))
+	sent, err = svc.Get().Notification.SendReminders(ctx, reminderNow.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestSendRemindersTimezone(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	//At 19:00 UTC it is still 14:00 in New York
+	client.User.Update().Where(user.UsernameEQ(testUserForm1.Username)).SetTimezone("America/New_York").ExecX(ctx)
+	sent, err := svc.Get().Notification.SendReminders(ctx, reminderNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	sent, err = svc.Get().Notification.SendReminders(ctx, reminderNow.Add(5*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+func TestUnsubscribeReminders(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	_, err := svc.Get().Notification.SendReminders(ctx, reminderNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reminder := testEnv.LastMail(t)
+	if reminder == nil {
+		t.Fatal("No email sent")
+	}
+	token := regexp.MustCompile(`/unsubscribe/(\S+)`).FindStringSubmatch(reminder.Message)[1]
+
+	body, _ := json.Marshal(notification.UnsubscribeForm{Token: token})
+	resp := testEnv.MakeRequest("POST", "/api/public/unsubscribe", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.False(t, client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).RemindersEnabled)
+
+	//A session token can't be used to unsubscribe
+	body, _ = json.Marshal(notification.UnsubscribeForm{Token: ctx.Value(utils.JwtKey).(string)})
+	resp = testEnv.MakeRequest("POST", "/api/public/unsubscribe", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Contains(t, resp.Body.String(), customerrors.INVALID_UNSUBSCRIBE_TOKEN)
+}
+
+func TestUnsubscribeOneClick(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	_, err := svc.Get().Notification.SendReminders(ctx, reminderNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reminder := testEnv.LastMail(t)
+	if reminder == nil {
+		t.Fatal("No email sent")
+	}
+	//The link of the header is the endpoint of the API, with the token of the link of the body
+	token := regexp.MustCompile(`/unsubscribe/(\S+)`).FindStringSubmatch(reminder.Message)[1]
+	assert.Equal(t, utils.ApiUrl()+"/api/public/unsubscribe/"+token, reminder.ListUnsubscribe)
+
+	//The email clients POST the One-Click form to the link
+	resp := testEnv.MakeRequest("POST", strings.TrimPrefix(reminder.ListUnsubscribe, utils.ApiUrl()),
+		utils.GetStringPointer("List-Unsubscribe=One-Click"))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.False(t, client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx).RemindersEnabled)
+
+	resp = testEnv.MakeRequest("POST", "/api/public/unsubscribe/"+ctx.Value(utils.JwtKey).(string), nil)
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+}
+
+func TestSendDigests(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupDigestTest)
+	defer teardown(t)
+
+	//The digest is opt-in
+	sent, err := svc.Get().Notification.SendDigests(ctx, digestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	client.User.Update().Where(user.UsernameEQ(testUserForm1.Username)).SetDigestEnabled(true).ExecX(ctx)
+	sent, err = svc.Get().Notification.SendDigests(ctx, digestNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	digest := testEnv.LastMail(t)
+	if assert.NotNil(t, digest) {
+		assert.Equal(t, testUserForm1.Email, digest.To)
+		assert.Equal(t, "Your week in Vocablo", digest.Subject)
+		assert.Contains(t, digest.Message, "Words added: 2")
+		assert.Contains(t, digest.Message, "Words learned: 1 (apple)")
+		assert.Contains(t, digest.Message, "Quizzes: 2, with 40% of correct answers")
+		assert.Contains(t, digest.Message, "Hardest words: banana, cherry")
+		assert.Contains(t, digest.Message, "You have learned 2 of your 4 words")
+		assert.Contains(t, digest.ListUnsubscribe, "/api/public/unsubscribe/")
+		assert.Contains(t, digest.Message, "focus on: banana, cherry.")
+	}
+
+	//Only one digest is sent a week
+	sent, err = svc.Get().Notification.SendDigests(ctx, digestNow.AddDate(0, 0, 6))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	//There is nothing to summarise of a week without activity
+	sent, err = svc.Get().Notification.SendDigests(ctx, digestNow.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestUnsubscribeDigest(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupDigestTest)
+	defer teardown(t)
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	client.User.UpdateOne(mainUser).SetDigestEnabled(true).ExecX(ctx)
+	err := svc.Get().Notification.SendDigest(ctx, mainUser.ID, digestNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := testEnv.LastMail(t)
+	if digest == nil {
+		t.Fatal("No email sent")
+	}
+	token := regexp.MustCompile(`/unsubscribe/(\S+)`).FindStringSubmatch(digest.Message)[1]
+
+	body, _ := json.Marshal(notification.UnsubscribeForm{Token: token})
+	resp := testEnv.MakeRequest("POST", "/api/public/unsubscribe", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	unsubscribed := client.User.GetX(ctx, mainUser.ID)
+	assert.False(t, unsubscribed.DigestEnabled)
+	//The reminders are a different list
+	assert.True(t, unsubscribed.RemindersEnabled)
+}
+
+func TestSendDigestsCommand(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupDigestTest)
+	defer teardown(t)
+
+	//A user can be sent the digest without opting in, to try it out
+	sent, err := cli.RunSendDigests(ctx, client, []string{"-username", testUserForm1.Username, "-now", digestNow.Format(time.RFC3339)})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	digest := testEnv.LastMail(t)
+	if assert.NotNil(t, digest) {
+		assert.Equal(t, "Your week in Vocablo", digest.Subject)
+	}
+
+	sent, err = cli.RunSendDigests(ctx, client, []string{"-now", digestNow.Format(time.RFC3339)})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	_, err = cli.RunSendDigests(ctx, client, []string{"-now", "yesterday"})
+	assert.Error(t, err)
+}
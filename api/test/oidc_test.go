@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"vocablo/api/test/mocks"
+	"vocablo/conf"
+	"vocablo/customerrors"
+	"vocablo/ent/identity"
+	entuser "vocablo/ent/user"
+	"vocablo/svc/oidc"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const OIDC_PROVIDER = "mock"
+const OIDC_SUBJECT = "mock-subject"
+const OIDC_EMAIL = "oidcuser@gmail.com"
+
+func setupOIDCProvider() (*mocks.OIDCServerMock, func()) {
+	mockServer := mocks.NewOIDCServerMock("vocablo")
+	conf.Get().OIDC.Providers = []conf.OIDCProviderConf{{Name: OIDC_PROVIDER, Type: "oidc", Issuer: mockServer.Issuer(),
+		ClientId: mockServer.ClientId, ClientSecret: "secret", RedirectUrl: "http://localhost/oidc/callback"}}
+	return mockServer, func() {
+		conf.Get().OIDC.Providers = nil
+		mockServer.Close()
+	}
+}
+
+// oidcLogin runs the whole authorization code flow against the mock provider and returns the callback response
+func oidcLogin(t *testing.T, mockServer *mocks.OIDCServerMock, email string, emailVerified bool) *http.Response {
+	resp := testEnv.MakeRequest("POST", "/api/public/oidc/"+OIDC_PROVIDER+"/start", nil)
+	assert.Equal(t, 200, resp.Code)
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := mockServer.Authorize(respBody.Data.(string), OIDC_SUBJECT, email, emailVerified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(oidc.CallbackForm{Code: code, State: state})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testEnv.MakeRequestWithCookies("POST", "/api/public/oidc/"+OIDC_PROVIDER+"/callback",
+		utils.GetStringPointer(string(body)), resp.Result().Cookies()).Result()
+}
+
+func TestOIDCLoginNewUser(t *testing.T) {
+	client, teardown := StartTest(t)
+	defer teardown(t)
+	mockServer, teardownProvider := setupOIDCProvider()
+	defer teardownProvider()
+
+	resp := oidcLogin(t, mockServer, OIDC_EMAIL, true)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.True(t, strings.Contains(strings.Join(resp.Header.Values("Set-Cookie"), ";"), "JWT_TOKEN"))
+
+	//We check that the user was created already validated and linked to the identity
+	createdUser, err := client.User.Query().Where(entuser.EmailEQ(OIDC_EMAIL)).Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, createdUser.Validated)
+	linkedIdentity, err := client.Identity.Query().Where(identity.SubjectEQ(OIDC_SUBJECT)).WithUser().Only(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, createdUser.ID, linkedIdentity.Edges.User.ID)
+
+	//A second login reuses the same user
+	resp = oidcLogin(t, mockServer, OIDC_EMAIL, true)
+	assert.Equal(t, 200, resp.StatusCode)
+	nUsers, err := client.User.Query().Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, nUsers)
+}
+
+func TestOIDCLoginLinksExistingUser(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	mockServer, teardownProvider := setupOIDCProvider()
+	defer teardownProvider()
+
+	resp := oidcLogin(t, mockServer, testUserForm1.Email, true)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	existingUser, err := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).WithIdentities().Only(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(existingUser.Edges.Identities))
+	assert.Equal(t, 1, client.User.Query().CountX(ctx))
+
+	//The password of the validated user still works
+	body, _ := json.Marshal(&testUserForm1)
+	loginResp := testEnv.MakeRequest("POST", "/api/public/login", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 200, loginResp.Code)
+}
+
+func TestOIDCLoginTakesOverNotValidatedUser(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, false, nil)
+	defer teardown(t)
+	mockServer, teardownProvider := setupOIDCProvider()
+	defer teardownProvider()
+	registeredUser, err := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).Only(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := oidcLogin(t, mockServer, testUserForm1.Email, true)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	existingUser, err := client.User.Query().Where(entuser.UsernameEQ(testUserForm1.Username)).WithIdentities().Only(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, existingUser.Validated)
+	assert.Equal(t, 1, len(existingUser.Edges.Identities))
+	assert.Equal(t, 1, client.User.Query().CountX(ctx))
+	assert.NotEqual(t, registeredUser.Password, existingUser.Password)
+	assert.Equal(t, registeredUser.SessionVersion+1, existingUser.SessionVersion)
+
+	//Whoever registered the account with the email can't log in with its password anymore
+	body, _ := json.Marshal(&testUserForm1)
+	loginResp := testEnv.MakeRequest("POST", "/api/public/login", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 401, loginResp.Code)
+}
+
+func TestOIDCLoginNotVerifiedEmail(t *testing.T) {
+	_, teardown, _ := SetupTest(t, true, nil)
+	defer teardown(t)
+	mockServer, teardownProvider := setupOIDCProvider()
+	defer teardownProvider()
+
+	resp := oidcLogin(t, mockServer, testUserForm1.Email, false)
+	assert.Equal(t, 403, resp.StatusCode)
+	var respBody utils.ResponseBody
+	err := json.NewDecoder(resp.Body).Decode(&respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.NOT_VERIFIED_OIDC_EMAIL, *respBody.ErrorCode)
+}
+
+func TestOIDCCallbackInvalidState(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	mockServer, teardownProvider := setupOIDCProvider()
+	defer teardownProvider()
+
+	resp := testEnv.MakeRequest("POST", "/api/public/oidc/"+OIDC_PROVIDER+"/start", nil)
+	var respBody utils.ResponseBody
+	err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := mockServer.Authorize(respBody.Data.(string), OIDC_SUBJECT, OIDC_EMAIL, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(oidc.CallbackForm{Code: code, State: "forged-state"})
+	callbackResp := testEnv.MakeRequestWithCookies("POST", "/api/public/oidc/"+OIDC_PROVIDER+"/callback",
+		utils.GetStringPointer(string(body)), resp.Result().Cookies())
+	assert.Equal(t, 401, callbackResp.Code)
+	err = json.Unmarshal(callbackResp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customerrors.INVALID_OIDC_STATE, *respBody.ErrorCode)
+}
+
+func TestOIDCUnknownProvider(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+
+	resp := testEnv.MakeRequest("POST", "/api/public/oidc/unknown/start", nil)
+	assert.Equal(t, 404, resp.Code)
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"vocablo/api"
+	"vocablo/api/openapi"
+	"vocablo/conf"
+	"vocablo/customerrors"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+var ginParameter = regexp.MustCompile(`:(\w+)`)
+
+func TestOpenAPIRoutes(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	//With the metrics in the port of the API every route is registered
+	metricsConf := conf.Get().Metrics
+	defer func() { conf.Get().Metrics = metricsConf }()
+	conf.Get().Metrics.Enabled = true
+	conf.Get().Metrics.Port = ""
+
+	spec := openapi.Spec()
+	assert.Nil(t, spec.Validate(context.Background()))
+	for _, route := range api.GetRouter().Routes() {
+		path := ginParameter.ReplaceAllString(route.Path, "{$1}")
+		item := spec.Paths.Value(path)
+		if assert.NotNil(t, item, "Route %s %s is not in the specification", route.Method, route.Path) {
+			assert.NotNil(t, item.GetOperation(route.Method), "Route %s %s is not in the specification",
+				route.Method, route.Path)
+		}
+	}
+}
+
+func TestOpenAPIErrorCodes(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../customerrors/customerrors.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//Every code declared is published
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				assert.Contains(t, customerrors.Codes, name.Name)
+			}
+		}
+	}
+	errorCode := openapi.Spec().Components.Schemas["ErrorCode"].Value
+	assert.Len(t, errorCode.Enum, len(customerrors.Codes))
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	resp := testEnv.MakeRequest("GET", "/api/public/openapi.json", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	spec, err := openapi3.NewLoader().LoadFromData(resp.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, spec.Validate(context.Background()))
+
+	//The forms are components with their mandatory fields
+	operation := spec.Paths.Value("/api/userword").Post
+	assert.Equal(t, "#/components/schemas/userword.CreateForm",
+		operation.RequestBody.Value.Content.Get("application/json").Schema.Ref)
+	createForm := spec.Components.Schemas["userword.CreateForm"].Value
+	assert.ElementsMatch(t, []string{"term", "definitions", "lang"}, createForm.Required)
+	assert.NotEmpty(t, operation.Security)
+	//The parameters use the OpenAPI syntax
+	operation = spec.Paths.Value("/api/userword/{id}").Get
+	if assert.Len(t, operation.Parameters, 2) {
+		assert.Equal(t, "id", operation.Parameters[0].Value.Name)
+		assert.Equal(t, "uuid", operation.Parameters[0].Value.Schema.Value.Format)
+		assert.Equal(t, "If-None-Match", operation.Parameters[1].Value.Name)
+		assert.Equal(t, "header", operation.Parameters[1].Value.In)
+	}
+	ifMatch := spec.Paths.Value("/api/userword").Put.Parameters.GetByInAndName("header", "If-Match")
+	if assert.NotNil(t, ifMatch) {
+		assert.True(t, ifMatch.Required)
+	}
+	assert.Empty(t, spec.Paths.Value("/api/public/login").Post.Security)
+
+	resp = testEnv.MakeRequest("GET", "/api/public/docs", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.Contains(t, resp.Body.String(), "/api/public/openapi.json")
+}
+
+func TestOpenAPIDocsScript(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	docsConf := conf.Get().Docs
+	defer func() { conf.Get().Docs = docsConf }()
+	bundle := filepath.Join(t.TempDir(), "redoc.standalone.js")
+	err := os.WriteFile(bundle, []byte("/*redoc*/"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.Get().Docs.RedocFile = bundle
+
+	//The page loads the bundle we serve, not the one of a CDN
+	resp := testEnv.MakeRequest("GET", "/api/public/docs", nil)
+	assert.Contains(t, resp.Body.String(), `src="/api/public/docs/redoc.standalone.js"`)
+	assert.NotContains(t, resp.Body.String(), "https://")
+	resp = testEnv.MakeRequest("GET", "/api/public/docs/redoc.standalone.js", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	assert.Equal(t, "/*redoc*/", resp.Body.String())
+	assert.Contains(t, resp.Header().Get("Content-Type"), "text/javascript")
+
+	conf.Get().Docs.RedocFile = filepath.Join(t.TempDir(), "missing.js")
+	resp = testEnv.MakeRequest("GET", "/api/public/docs/redoc.standalone.js", nil)
+	assert.Equal(t, 404, resp.Code, "Response status should be 404")
+}
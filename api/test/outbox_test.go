@@ -0,0 +1,121 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/ent/outbox"
+	"vocablo/svc"
+	"vocablo/svc/mail"
+	"vocablo/svc/verificationcode"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRollback(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, false, nil)
+	defer teardown(t)
+
+	//The email is queued in the transaction, so it is discarded with it
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = svc.Get().VerificationCode.Create(ctx, verificationcode.CreateForm{Username: testUserForm1.Username, Type: utils.RESET_TYPE}, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, client.Outbox.Query().Where(outbox.TemplateEQ(mail.RESET_PASSWORD_TEMPLATE)).CountX(ctx))
+	sent, err := svc.Get().Mail.Deliver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, sent)
+}
+
+func TestOutboxRetryAndDeadLetter(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupAdminTest)
+	defer teardown(t)
+	conf.Get().Mail.Outbox = conf.OutboxConf{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	//The emails of the setup are delivered before the server goes down
+	testEnv.LastMail(t)
+	testEnv.Mail.Err = errors.New("smtp server unavailable")
+	defer func() { testEnv.Mail.Err = nil }()
+
+	resp := testEnv.MakeRequest("POST", "/api/public/forgotten-password/"+testUserForm2.Username, nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	queued := client.Outbox.Query().Where(outbox.ToEQ(testUserForm2.Email)).OnlyX(ctx)
+	assert.Equal(t, mail.PENDING_STATUS, queued.Status)
+
+	//The first failure is retried after the backoff
+	sent, err := svc.Get().Mail.Deliver(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	queued = client.Outbox.GetX(ctx, queued.ID)
+	assert.Equal(t, mail.PENDING_STATUS, queued.Status)
+	assert.Equal(t, 1, queued.Attempts)
+	assert.Equal(t, "smtp server unavailable", queued.LastError)
+
+	//The last one moves it to the dead letters
+	time.Sleep(5 * time.Millisecond)
+	_, err = svc.Get().Mail.Deliver(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, mail.DEAD_STATUS, client.Outbox.GetX(ctx, queued.ID).Status)
+
+	resp = testEnv.MakeAuthRequest("GET", "/api/admin/mail/outbox?status="+mail.DEAD_STATUS, nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	var respBody struct {
+		Data utils.Page[*ent.Outbox] `json:"data"`
+	}
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, respBody.Data.Content, 1) {
+		assert.Equal(t, queued.ID, respBody.Data.Content[0].ID)
+	}
+
+	//The dead email doesn't keep the body with the code
+	dead := client.Outbox.GetX(ctx, queued.ID)
+	assert.Empty(t, dead.Text)
+	assert.Empty(t, dead.HTML)
+	assert.NotContains(t, resp.Body.String(), "<html")
+
+	//Once the server is back, the sent emails don't keep it either
+	testEnv.Mail.Err = nil
+	resp = testEnv.MakeRequest("POST", "/api/public/forgotten-password/"+testUserForm2.Username, nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	lastMail := testEnv.LastMail(t)
+	if assert.NotNil(t, lastMail) {
+		assert.Equal(t, testUserForm2.Email, lastMail.To)
+		assert.NotEmpty(t, lastMail.Message)
+	}
+	sentMail := client.Outbox.Query().Where(outbox.ToEQ(testUserForm2.Email), outbox.StatusEQ(mail.SENT_STATUS)).OnlyX(ctx)
+	assert.Empty(t, sentMail.Text)
+	assert.Empty(t, sentMail.HTML)
+}
+
+func TestMaildirSender(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	dir := t.TempDir()
+	sender, err := mail.NewMaildirSender(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sender.Send(&mail.Message{To: "jane@example.com", Subject: "Hi", Text: "Hi"})
+	assert.NoError(t, err)
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"vocablo/conf"
+	"vocablo/customerrors"
+	"vocablo/svc"
+	"vocablo/svc/auth"
+	"vocablo/svc/password"
+	"vocablo/svc/user"
+	"vocablo/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// weakPasswordRules returns the failed rules of a WEAK_PASSWORD response
+func weakPasswordRules(t *testing.T, body []byte) []string {
+	var respBody struct {
+		Data      []string `json:"data"`
+		ErrorCode *string  `json:"errorCode"`
+	}
+	err := json.Unmarshal(body, &respBody)
+	if err != nil {
+		t.Fatalf("Error unmarshalling response body: %s", err)
+	}
+	if assert.NotNil(t, respBody.ErrorCode) {
+		assert.Equal(t, customerrors.WEAK_PASSWORD, *respBody.ErrorCode)
+	}
+	return respBody.Data
+}
+
+func TestSignUpWeakPassword(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	body, _ := json.Marshal(&user.CreateForm{Username: "weakuser", Email: "weak@gmail.com", Password: "weak"})
+	resp := testEnv.MakeRequest("POST", "/api/public/register", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	rules := weakPasswordRules(t, resp.Body.Bytes())
+	assert.ElementsMatch(t, []string{customerrors.PASSWORD_TOO_SHORT, customerrors.PASSWORD_NO_UPPERCASE, customerrors.PASSWORD_NO_DIGIT}, rules)
+}
+
+func TestSignUpPasswordEqualsUsername(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	body, _ := json.Marshal(&user.CreateForm{Username: "Username123", Email: "weak@gmail.com", Password: "Username123"})
+	resp := testEnv.MakeRequest("POST", "/api/public/register", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Equal(t, []string{customerrors.PASSWORD_EQUALS_USERNAME}, weakPasswordRules(t, resp.Body.Bytes()))
+}
+
+func TestSignUpBreachedPassword(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	body, _ := json.Marshal(&user.CreateForm{Username: "breached", Email: "breached@gmail.com", Password: "Password1!"})
+	resp := testEnv.MakeRequest("POST", "/api/public/register", utils.GetStringPointer(string(body)))
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Equal(t, []string{customerrors.PASSWORD_BREACHED}, weakPasswordRules(t, resp.Body.Bytes()))
+}
+
+func TestResetPasswordWeak(t *testing.T) {
+	_, teardown, _ := SetupTest(t, true, nil)
+	defer teardown(t)
+	resp := testEnv.MakeRequest("POST", "/api/public/forgotten-password/"+testUserForm1.Username, nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	code := codeFromMail(t)
+	resp = testEnv.MakeRequest("POST", "/api/public/reset-password/"+testUserForm1.Username+"/"+code, utils.GetStringPointer("password"))
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Contains(t, weakPasswordRules(t, resp.Body.Bytes()), customerrors.PASSWORD_BREACHED)
+
+	//The code is not spent, so it can be used with a valid password
+	resp = testEnv.MakeRequest("POST", "/api/public/reset-password/"+testUserForm1.Username+"/"+code, utils.GetStringPointer(NEW_PASSWORD))
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestChangePasswordWeak(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	body, _ := json.Marshal(&auth.ChangePasswordForm{CurrentPassword: testUserForm1.Password, NewPassword: testUserForm1.Email})
+	resp := testEnv.MakeAuthRequest("PUT", "/api/self/password", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 400, resp.Code, "Response status should be 400")
+	assert.Contains(t, weakPasswordRules(t, resp.Body.Bytes()), customerrors.PASSWORD_EQUALS_EMAIL)
+	//The session is not revoked
+	resp = testEnv.MakeAuthRequest("GET", "/api/self", nil, ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+}
+
+func TestBreachedListSearch(t *testing.T) {
+	passwords := []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh"}
+	hashes := []string{}
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		hashes = append(hashes, strings.ToUpper(hex.EncodeToString(sum[:]))+":1")
+	}
+	sort.Strings(hashes)
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	err := os.WriteFile(path, []byte("# Comment\n"+strings.Join(hashes, "\n")+"\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := password.LoadBreachedList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The first and the last lines are found as well as the middle ones
+	for _, breached := range passwords {
+		assert.True(t, list.Contains(breached), breached)
+	}
+	assert.False(t, list.Contains("not breached"))
+	assert.False(t, list.Contains(""))
+}
+
+func TestRequiredBreachedListMissing(t *testing.T) {
+	client, teardown := StartTest(t)
+	defer teardown(t)
+	policy := conf.Get().PasswordPolicy
+	defer func() { conf.Get().PasswordPolicy = policy }()
+
+	//Without the list the password policy is weaker, so it can't be skipped when it is required
+	conf.Get().PasswordPolicy.BreachedListFile = filepath.Join(t.TempDir(), "missing.txt")
+	assert.Error(t, svc.Setup(client, testEnv.Mail))
+}
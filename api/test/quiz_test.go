@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	apiclient "vocablo/client"
 	"vocablo/customerrors"
 	"vocablo/ent"
 	"vocablo/ent/language"
@@ -95,18 +96,13 @@ func TestCreateQuizNotEnoughWords(t *testing.T) {
 	_, teardown, ctx := SetupTest(t, true, SetupQuizTest)
 	defer teardown(t)
 
-	body, err := json.Marshal(testCreateQuizForm)
-	if err != nil {
-		t.Fatal(err)
-	}
-	resp := testEnv.MakeAuthRequest("POST", "/api/quiz", utils.GetStringPointer(string(body)), ctx)
-	var respObj utils.ResponseBody
-	err = json.Unmarshal(resp.Body.Bytes(), &respObj)
-	if err != nil {
-		t.Fatal(err)
+	_, err := testEnv.Client(t, ctx).CreateQuiz(ctx, testCreateQuizForm)
+	assert.ErrorAs(t, err, &customerrors.NotEnoughWordsForQuizError{})
+	var apiErr *apiclient.Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ, apiErr.Code)
+		assert.Equal(t, 409, apiErr.Status)
 	}
-	assert.Equal(t, customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ, *respObj.ErrorCode)
-	assert.Equal(t, 409, resp.Code)
 }
 
 func TestAnswerQuiz(t *testing.T) {
@@ -119,23 +115,9 @@ func TestAnswerQuiz(t *testing.T) {
 	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLang(
 		client.Language.Query().Where(language.CodeEqualFold(testWordForm3.Lang)).OnlyX(ctx)).
 		SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	apiClient := testEnv.Client(t, ctx)
 
-	body, err := json.Marshal(testCreateQuizForm)
-	if err != nil {
-		t.Fatal(err)
-	}
-	resp := testEnv.MakeAuthRequest("POST", "/api/quiz", utils.GetStringPointer(string(body)), ctx)
-	var respObj utils.ResponseBody
-	err = json.Unmarshal(resp.Body.Bytes(), &respObj)
-	if err != nil {
-		t.Fatal(err)
-	}
-	var respQuiz quiz.Quiz
-	respQuizStr, err := json.Marshal(respObj.Data)
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = json.Unmarshal(respQuizStr, &respQuiz)
+	respQuiz, err := apiClient.CreateQuiz(ctx, testCreateQuizForm)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -144,17 +126,9 @@ func TestAnswerQuiz(t *testing.T) {
 	for i, question := range respQuiz.Questions {
 		respQuiz.Questions[i].AnswerPos = utils.GetIntPointer(question.CorrectOptionPos)
 	}
-	body, err = json.Marshal(respQuiz)
-	if err != nil {
-		t.Fatal(err)
-	}
-	resp = testEnv.MakeAuthRequest("POST", "/api/quiz/answer", utils.GetStringPointer(string(body)), ctx)
-	err = json.Unmarshal(resp.Body.Bytes(), &respObj)
-	if err != nil {
-		t.Fatal(err)
-	}
-	assert.Equal(t, 200, resp.Code)
-	assert.Equal(t, 100, int(respObj.Data.(float64)))
+	score, err := apiClient.AnswerQuiz(ctx, *respQuiz)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, score)
 
 	//We answer the quiz with the only half of the correct answers
 	for i, question := range respQuiz.Questions {
@@ -164,16 +138,53 @@ func TestAnswerQuiz(t *testing.T) {
 			respQuiz.Questions[i].AnswerPos = utils.GetIntPointer((question.CorrectOptionPos + 1) % 4)
 		}
 	}
-	body, err = json.Marshal(respQuiz)
+	score, err = apiClient.AnswerQuiz(ctx, *respQuiz)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, score)
+}
+
+func TestAnswerQuizLearnedDate(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+	mainUser, err := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).Only(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
-	resp = testEnv.MakeAuthRequest("POST", "/api/quiz/answer", utils.GetStringPointer(string(body)), ctx)
-	err = json.Unmarshal(resp.Body.Bytes(), &respObj)
+	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLang(
+		client.Language.Query().Where(language.CodeEqualFold(testWordForm3.Lang)).OnlyX(ctx)).
+		SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	client.UserWord.Update().SetLearningProgress(90).ExecX(ctx)
+
+	body, err := json.Marshal(testCreateQuizForm)
 	if err != nil {
 		t.Fatal(err)
 	}
+	resp := testEnv.MakeAuthRequest("POST", "/api/quiz", utils.GetStringPointer(string(body)), ctx)
+	if resp.Code != 200 {
+		t.Fatalf("Creating the quiz returned %d: %s", resp.Code, resp.Body.String())
+	}
+	var respBody struct {
+		Data quiz.Quiz `json:"data"`
+	}
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respQuiz := respBody.Data
+	for i, question := range respQuiz.Questions {
+		respQuiz.Questions[i].AnswerPos = utils.GetIntPointer(question.CorrectOptionPos)
+	}
+	body, err = json.Marshal(respQuiz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = testEnv.MakeAuthRequest("POST", "/api/quiz/answer", utils.GetStringPointer(string(body)), ctx)
 	assert.Equal(t, 200, resp.Code)
-	assert.Equal(t, 50, int(respObj.Data.(float64)))
 
+	//The words that reached 100 keep when they were learned
+	for _, question := range respQuiz.Questions {
+		answered := client.UserWord.GetX(ctx, question.UserWordID)
+		assert.Equal(t, 100.0, answered.LearningProgress)
+		assert.NotNil(t, answered.LearnedDate)
+	}
 }
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"vocablo/api"
+	"vocablo/conf"
+	"vocablo/svc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSelfSignedCert creates a certificate for localhost in the directory and returns its and its key paths
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerGracefulShutdown(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	server, err := api.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, conf.Get().Server.ReadTimeout, server.ReadTimeout)
+	assert.Equal(t, conf.Get().Server.IdleTimeout, server.IdleTimeout)
+
+	started := make(chan struct{})
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- api.Serve(ctx, server, listener, 0, time.Second)
+	}()
+
+	responded := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			responded <- 0
+			return
+		}
+		resp.Body.Close()
+		responded <- resp.StatusCode
+	}()
+	//The request in flight when the shutdown starts is finished
+	<-started
+	cancel()
+	assert.Equal(t, http.StatusOK, <-responded)
+	assert.NoError(t, <-served)
+
+	//And no new connections are accepted
+	_, err = http.Get("http://" + listener.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestServerTLS(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+	previous := conf.Get().Server.TLS
+	conf.Get().Server.TLS = conf.TLSConf{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"}
+	defer func() { conf.Get().Server.TLS = previous }()
+	assert.NoError(t, conf.Validate())
+
+	server, err := api.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go api.Serve(ctx, server, listener, 0, time.Second)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Get("https://" + listener.Addr().String() + "/api/public/health")
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, uint16(tls.VersionTLS13), resp.TLS.Version)
+	}
+
+	//A certificate without its key is a configuration error
+	conf.Get().Server.TLS = conf.TLSConf{CertFile: certFile}
+	assert.Error(t, conf.Validate())
+}
+
+func TestWaitWorkers(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.Get().Mail.ScheduleDelivery(ctx, time.Millisecond)
+	svc.Get().Notification.ScheduleReminders(ctx, time.Millisecond)
+	svc.Get().VerificationCode.SchedulePurge(ctx, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assert.True(t, svc.Wait(5*time.Second))
+}
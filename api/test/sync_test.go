@@ -0,0 +1,333 @@
+package test
+
+import (
+	"testing"
+	"time"
+	"vocablo/customerrors"
+	"vocablo/ent/language"
+	"vocablo/ent/quizresult"
+	"vocablo/ent/user"
+	entuserword "vocablo/ent/userword"
+	"vocablo/schema"
+	"vocablo/svc"
+	"vocablo/svc/deltasync"
+	"vocablo/svc/quiz"
+	"vocablo/svc/userword"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func syncedTerms(changes *deltasync.Changes) (created []string, updated []string, deleted []uuid.UUID) {
+	for _, word := range changes.Created {
+		created = append(created, word.Term)
+	}
+	for _, word := range changes.Updated {
+		updated = append(updated, word.Term)
+	}
+	for _, tombstone := range changes.Deleted {
+		deleted = append(deleted, tombstone.ID)
+	}
+	return created, updated, deleted
+}
+
+func TestSyncPull(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	apiClient := testEnv.Client(t, ctx)
+
+	first, err := apiClient.CreateUserWord(ctx, testWordForm1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := apiClient.CreateUserWord(ctx, testWordForm2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial, err := apiClient.PullChanges(ctx, deltasync.PullForm{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, updated, deleted := syncedTerms(initial)
+	assert.Equal(t, []string{testWordForm1.Term, testWordForm2.Term}, created)
+	assert.Empty(t, updated)
+	assert.Empty(t, deleted)
+	assert.False(t, initial.HasMore)
+
+	_, err = apiClient.UpdateUserWord(ctx, userword.UpdateForm{ID: first.ID.String(), Term: utils.GetStringPointer("worse")},
+		first.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = apiClient.DeleteUserWord(ctx, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = apiClient.CreateUserWord(ctx, testWordForm3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := apiClient.PullChanges(ctx, deltasync.PullForm{Since: initial.Cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, updated, deleted = syncedTerms(changes)
+	assert.Equal(t, []string{testWordForm3.Term}, created)
+	assert.Equal(t, []string{"worse"}, updated)
+	assert.Equal(t, []uuid.UUID{second.ID}, deleted)
+
+	//The changes are paginated with the cursor
+	page, err := apiClient.PullChanges(ctx, deltasync.PullForm{Since: initial.Cursor, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, page.HasMore)
+	assert.Len(t, page.Updated, 1)
+	assert.Len(t, page.Deleted, 1)
+	page, err = apiClient.PullChanges(ctx, deltasync.PullForm{Since: page.Cursor, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, page.HasMore)
+	assert.Len(t, page.Created, 1)
+
+	//Without changes the cursor is kept
+	empty, err := apiClient.PullChanges(ctx, deltasync.PullForm{Since: changes.Cursor})
+	if assert.Nil(t, err) {
+		assert.Empty(t, empty.Created)
+		assert.Empty(t, empty.Updated)
+		assert.Empty(t, empty.Deleted)
+		assert.Equal(t, changes.Cursor, empty.Cursor)
+	}
+	_, err = apiClient.PullChanges(ctx, deltasync.PullForm{Since: "invalid"})
+	assert.ErrorAs(t, err, &customerrors.InvalidSyncCursorError{})
+}
+
+func TestSyncPullOnlyUserWords(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+
+	changes, err := testEnv.Client(t, ctx).PullChanges(ctx, deltasync.PullForm{})
+	if assert.Nil(t, err) {
+		created, _, _ := syncedTerms(changes)
+		assert.Equal(t, []string{testWordForm1.Term}, created)
+	}
+}
+
+func TestSyncPush(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	apiClient := testEnv.Client(t, ctx)
+	userWord := client.UserWord.Query().Where(entuserword.TermEQ(testWordForm1.Term)).OnlyX(ctx)
+	otherWord := client.UserWord.Query().Where(entuserword.TermEQ(otherUserWordForm.Term)).OnlyX(ctx)
+
+	offlineId := uuid.New()
+	definitions := []schema.Definition{{Definition: "An animal"}}
+	result, err := apiClient.PushChanges(ctx, deltasync.PushForm{Changes: []deltasync.Change{
+		{ID: offlineId, UpdatedAt: time.Now(), Term: utils.GetStringPointer("cat"), Lang: utils.GetStringPointer("en"),
+			Definitions: &definitions},
+		{ID: userWord.ID, BaseVersion: userWord.Version, UpdatedAt: time.Now(), Term: utils.GetStringPointer("worse")},
+		{ID: otherWord.ID, BaseVersion: otherWord.Version, UpdatedAt: time.Now(), Term: utils.GetStringPointer("stolen")},
+		{ID: uuid.New(), UpdatedAt: time.Now(), Term: utils.GetStringPointer("palabra"), Lang: utils.GetStringPointer("xx"),
+			Definitions: &definitions},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, result.Changes, 4) {
+		return
+	}
+	assert.Equal(t, deltasync.APPLIED_STATUS, result.Changes[0].Status)
+	assert.Equal(t, "cat", result.Changes[0].Word.Term)
+	assert.Equal(t, offlineId, result.Changes[0].Word.ID)
+	assert.Equal(t, deltasync.APPLIED_STATUS, result.Changes[1].Status)
+	assert.Equal(t, "worse", result.Changes[1].Word.Term)
+	assert.Len(t, result.Changes[1].Word.Definitions, len(testWordForm1.Definitions))
+	assert.Greater(t, result.Changes[1].Word.Version, userWord.Version)
+	assert.Equal(t, deltasync.REJECTED_STATUS, result.Changes[2].Status)
+	assert.Equal(t, customerrors.NOT_ALLOWED_RESOURCE, result.Changes[2].ErrorCode)
+	assert.Nil(t, result.Changes[2].Word)
+	assert.Equal(t, deltasync.REJECTED_STATUS, result.Changes[3].Status)
+	assert.Equal(t, customerrors.LANGUAGE_NOT_FOUND, result.Changes[3].ErrorCode)
+	assert.Equal(t, otherWord.Term, client.UserWord.GetX(ctx, otherWord.ID).Term)
+
+	//A change of an old version made before the last change of the server is a conflict
+	result, err = apiClient.PushChanges(ctx, deltasync.PushForm{Changes: []deltasync.Change{
+		{ID: userWord.ID, BaseVersion: userWord.Version, UpdatedAt: time.Now().Add(-time.Hour),
+			Term: utils.GetStringPointer("older")},
+	}})
+	if assert.Nil(t, err) && assert.Len(t, result.Changes, 1) {
+		assert.Equal(t, deltasync.CONFLICT_STATUS, result.Changes[0].Status)
+		assert.Equal(t, "worse", result.Changes[0].Word.Term)
+	}
+	//The last writer wins
+	result, err = apiClient.PushChanges(ctx, deltasync.PushForm{Changes: []deltasync.Change{
+		{ID: userWord.ID, BaseVersion: userWord.Version, UpdatedAt: time.Now(), Term: utils.GetStringPointer("newer")},
+	}})
+	if assert.Nil(t, err) && assert.Len(t, result.Changes, 1) {
+		assert.Equal(t, deltasync.APPLIED_STATUS, result.Changes[0].Status)
+		assert.Equal(t, "newer", result.Changes[0].Word.Term)
+	}
+
+	latest := client.UserWord.GetX(ctx, userWord.ID)
+	deleteChange := deltasync.Change{ID: userWord.ID, BaseVersion: latest.Version, UpdatedAt: time.Now(), Deleted: true}
+	result, err = apiClient.PushChanges(ctx, deltasync.PushForm{Changes: []deltasync.Change{deleteChange}})
+	if assert.Nil(t, err) && assert.Len(t, result.Changes, 1) {
+		assert.Equal(t, deltasync.APPLIED_STATUS, result.Changes[0].Status)
+		assert.NotNil(t, result.Changes[0].Word.DeletedAt)
+	}
+	_, err = apiClient.GetUserWord(ctx, userWord.ID)
+	assert.ErrorAs(t, err, &customerrors.NotFoundError{})
+	//Pushing the same change again doesn't change the word
+	version := client.UserWord.GetX(ctx, userWord.ID).Version
+	result, err = apiClient.PushChanges(ctx, deltasync.PushForm{Changes: []deltasync.Change{deleteChange}})
+	if assert.Nil(t, err) && assert.Len(t, result.Changes, 1) {
+		assert.Equal(t, deltasync.APPLIED_STATUS, result.Changes[0].Status)
+		assert.Equal(t, version, result.Changes[0].Word.Version)
+	}
+}
+
+func TestSyncPushOfflineQuiz(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLang(
+		client.Language.Query().Where(language.CodeEqualFold(testWordForm3.Lang)).OnlyX(ctx)).
+		SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	apiClient := testEnv.Client(t, ctx)
+
+	initial, err := apiClient.PullChanges(ctx, deltasync.PullForm{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createdQuiz, err := apiClient.CreateQuiz(ctx, testCreateQuizForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, question := range createdQuiz.Questions {
+		createdQuiz.Questions[i].AnswerPos = utils.GetIntPointer(question.CorrectOptionPos)
+	}
+	answeredAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	offlineAnswer := quiz.OfflineAnswer{ID: uuid.New(), AnsweredAt: answeredAt, Questions: createdQuiz.Questions}
+	invalidAnswer := quiz.OfflineAnswer{ID: uuid.New(), AnsweredAt: answeredAt, Questions: []quiz.QuizQuestion{{
+		UserWordID: createdQuiz.Questions[0].UserWordID, Options: createdQuiz.Questions[0].Options,
+		AnswerPos: utils.GetIntPointer(len(createdQuiz.Questions[0].Options))}}}
+	for i := 0; i < 2; i++ {
+		//The answer is applied once even if the client retries the push
+		result, err := apiClient.PushChanges(ctx, deltasync.PushForm{Quizzes: []quiz.OfflineAnswer{offlineAnswer, invalidAnswer}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if assert.Len(t, result.Quizzes, 2) {
+			assert.Equal(t, deltasync.APPLIED_STATUS, result.Quizzes[0].Status)
+			assert.Equal(t, 100, result.Quizzes[0].Score)
+			assert.Equal(t, deltasync.REJECTED_STATUS, result.Quizzes[1].Status)
+			assert.Equal(t, customerrors.INVALID_REQUEST, result.Quizzes[1].ErrorCode)
+		}
+	}
+	answeredWord := client.UserWord.GetX(ctx, createdQuiz.Questions[0].UserWordID)
+	assert.Equal(t, 10.0, answeredWord.LearningProgress)
+	quizResult := client.QuizResult.GetX(ctx, offlineAnswer.ID)
+	assert.True(t, answeredAt.Equal(quizResult.CreationDate))
+	assert.Equal(t, 1, client.QuizResult.Query().Where(quizresult.HasUserWith(user.IDEQ(mainUser.ID))).CountX(ctx))
+
+	//The progress is synced to the other devices
+	changes, err := apiClient.PullChanges(ctx, deltasync.PullForm{Since: initial.Cursor})
+	if assert.Nil(t, err) {
+		assert.Len(t, changes.Updated, 4)
+	}
+}
+
+func TestSyncPushOfflineQuizLimits(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+	mainUser := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).OnlyX(ctx)
+	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLang(
+		client.Language.Query().Where(language.CodeEqualFold(testWordForm3.Lang)).OnlyX(ctx)).
+		SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	apiClient := testEnv.Client(t, ctx)
+
+	createdQuiz, err := apiClient.CreateQuiz(ctx, testCreateQuizForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, question := range createdQuiz.Questions {
+		createdQuiz.Questions[i].AnswerPos = utils.GetIntPointer(question.CorrectOptionPos)
+	}
+	//A clock far behind can't date the answer long before it could be made
+	oldAnswer := quiz.OfflineAnswer{ID: uuid.New(), AnsweredAt: time.Now().AddDate(-1, 0, 0),
+		Questions: createdQuiz.Questions}
+	result, err := apiClient.PushChanges(ctx, deltasync.PushForm{Quizzes: []quiz.OfflineAnswer{oldAnswer}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, result.Quizzes, 1) {
+		assert.Equal(t, deltasync.APPLIED_STATUS, result.Quizzes[0].Status)
+	}
+	quizResult := client.QuizResult.GetX(ctx, oldAnswer.ID)
+	assert.True(t, quizResult.CreationDate.After(time.Now().AddDate(0, 0, -31)))
+
+	//The deleted words can't be answered
+	err = apiClient.DeleteUserWord(ctx, createdQuiz.Questions[0].UserWordID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deletedAnswer := quiz.OfflineAnswer{ID: uuid.New(), AnsweredAt: time.Now(), Questions: createdQuiz.Questions}
+	result, err = apiClient.PushChanges(ctx, deltasync.PushForm{Quizzes: []quiz.OfflineAnswer{deletedAnswer}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, result.Quizzes, 1) {
+		assert.Equal(t, deltasync.REJECTED_STATUS, result.Quizzes[0].Status)
+		assert.Equal(t, customerrors.NOT_ALLOWED_RESOURCE, result.Quizzes[0].ErrorCode)
+	}
+}
+
+func TestSyncPurgeDeletedWords(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	apiClient := testEnv.Client(t, ctx)
+
+	first, err := apiClient.CreateUserWord(ctx, testWordForm1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = apiClient.CreateUserWord(ctx, testWordForm2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial, err := apiClient.PullChanges(ctx, deltasync.PullForm{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = apiClient.DeleteUserWord(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	afterDelete, err := apiClient.PullChanges(ctx, deltasync.PullForm{Since: initial.Cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The deleted words are only purged after the retention
+	purged, err := svc.Get().Sync.Purge(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	client.UserWord.UpdateOneID(first.ID).SetDeletedAt(time.Now().AddDate(-1, 0, 0)).ExecX(ctx)
+	purged, err = svc.Get().Sync.Purge(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Zero(t, client.UserWord.Query().Where(entuserword.ID(first.ID)).CountX(ctx))
+
+	//A client that didn't see the deletion has to sync again from the start
+	_, err = apiClient.PullChanges(ctx, deltasync.PullForm{Since: initial.Cursor})
+	assert.ErrorAs(t, err, &customerrors.InvalidSyncCursorError{})
+	_, err = apiClient.PullChanges(ctx, deltasync.PullForm{Since: afterDelete.Cursor})
+	assert.NoError(t, err)
+	changes, err := apiClient.PullChanges(ctx, deltasync.PullForm{})
+	if assert.NoError(t, err) {
+		created, _, _ := syncedTerms(changes)
+		assert.Equal(t, []string{testWordForm2.Term}, created)
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"vocablo/utils"
 )
 
-var testUserForm1 user.CreateForm = user.CreateForm{Username: "test", Password: "test", Email: "test@gmail.com"}
-var testUserForm2 user.CreateForm = user.CreateForm{Username: "test2", Password: "test2", Email: "test2@gmail.com"}
+var testUserForm1 user.CreateForm = user.CreateForm{Username: "test", Password: "Test-password1", Email: "test@gmail.com"}
+var testUserForm2 user.CreateForm = user.CreateForm{Username: "test2", Password: "Test-password2", Email: "test2@gmail.com"}
 var verificationCodeForm *ent.VerificationCode = &ent.VerificationCode{Code: "123456", Type: utils.VALIDATION_TYPE}
 
 const INCORRECT_VERIFICATION_CODE string = "654321"
-const NEW_PASSWORD string = "newpassword"
+const NEW_PASSWORD string = "New-password1"
+const INCORRECT_PASSWORD string = "incorrectpassword"
+const NEW_EMAIL string = "newtest@gmail.com"
 
 var testWordForm1 = userword.CreateForm{Term: "bad", Lang: "en",
 	Definitions: []schema.Definition{{Definition: "not good", Example: "drug is bad"}}}
@@ -8,12 +8,17 @@ import (
 	"testing"
 	"vocablo/api"
 	"vocablo/api/test/mocks"
+	apiclient "vocablo/client"
+	"vocablo/conf"
+	"vocablo/db"
 	"vocablo/ent"
 	"vocablo/ent/enttest"
 	"vocablo/svc"
 	"vocablo/svc/auth"
+	"vocablo/tracing"
 	"vocablo/utils"
 
+	entsql "entgo.io/ent/dialect/sql"
 	"github.com/gin-gonic/gin"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rs/zerolog/log"
@@ -22,6 +27,7 @@ import (
 
 type TestEnvironment struct {
 	Router *gin.Engine
+	Mail   *mocks.MailSvcMock
 }
 
 var testEnv TestEnvironment
@@ -32,9 +38,29 @@ func Get() *TestEnvironment {
 
 func StartTest(t *testing.T) (*ent.Client, func(t *testing.T)) {
 	log.Info().Msg("Setup tests ")
-	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	err := conf.Setup()
+	if err != nil {
+		t.Fatalf("Error loading configuration: %s", err)
+	}
+	conf.Get().Env = "dev"
+	err = utils.SetupJwtKeys()
+	if err != nil {
+		t.Fatalf("Error loading the JWT keys: %s", err)
+	}
+	driver, err := entsql.Open("sqlite3", "file:ent?mode=memory&_fk=1")
+	if err != nil {
+		t.Fatalf("Error opening the database: %s", err)
+	}
+	//The queries are traced as in the real client
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(tracing.NewDriver(driver))))
+	//enttest runs the migration
+	db.MarkMigrated()
 
-	svc.Setup(client, &(mocks.MailSvcMock{}))
+	testEnv.Mail = &mocks.MailSvcMock{}
+	err = svc.Setup(client, testEnv.Mail)
+	if err != nil {
+		t.Fatalf("Error setting up the services: %s", err)
+	}
 	testEnv.Router = api.GetRouter()
 
 	// Return a function to teardown the test
@@ -68,8 +94,10 @@ func SetupTest(t *testing.T, isValidated bool, customSetup func(client *ent.Clie
 		ctx = context.WithValue(ctx, utils.JwtKey, loginResult.JWTToken)
 		ctx = context.WithValue(ctx, utils.UserIdKey, mainUser.ID)
 	}
-	client.Language.Create().SetCode("es").SaveX(ctx)
-	client.Language.Create().SetCode("en").SaveX(ctx)
+	err = svc.Get().Language.Seed(ctx, []string{"es", "en"})
+	if err != nil {
+		t.Errorf("Error seeding languages: %s", err)
+	}
 
 	if customSetup != nil {
 		customSetup(client, t, ctx)
@@ -92,6 +120,12 @@ func (testEnv TestEnvironment) MakeRequest(method string, path string, body *str
 }
 
 func (testEnv TestEnvironment) MakeAuthRequest(method string, path string, body *string,
+	ctx context.Context) *httptest.ResponseRecorder {
+	return testEnv.MakeAuthRequestWithHeader(method, path, body, http.Header{}, ctx)
+}
+
+// MakeAuthRequestWithHeader is MakeAuthRequest sending the header too, e.g. with the preconditions of the request
+func (testEnv TestEnvironment) MakeAuthRequestWithHeader(method string, path string, body *string, header http.Header,
 	ctx context.Context) *httptest.ResponseRecorder {
 	recorder := httptest.NewRecorder()
 	var bodyReader *strings.Reader
@@ -101,9 +135,52 @@ func (testEnv TestEnvironment) MakeAuthRequest(method string, path string, body
 		bodyReader = strings.NewReader(*body)
 	}
 	req := httptest.NewRequest(method, path, bodyReader)
+	req.Header = header
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-API-CSRF", ctx.Value(utils.CsrfKey).(string))
 	req.AddCookie(&http.Cookie{Name: "JWT_TOKEN", Value: ctx.Value(utils.JwtKey).(string), Path: "/", HttpOnly: true, Secure: false, SameSite: http.SameSiteLaxMode})
 	testEnv.Router.ServeHTTP(recorder, req)
 	return recorder
 }
+
+func (testEnv TestEnvironment) MakeRequestWithCookies(method string, path string, body *string,
+	cookies []*http.Cookie) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	var bodyReader *strings.Reader
+	if body == nil {
+		bodyReader = strings.NewReader("")
+	} else {
+		bodyReader = strings.NewReader(*body)
+	}
+	req := httptest.NewRequest(method, path, bodyReader)
+	req.Header.Set("Content-Type", "application/json")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	testEnv.Router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// Client returns a client of the API served by the router of the test, with the session of the context if
+// it has one
+func (testEnv *TestEnvironment) Client(t *testing.T, ctx context.Context) *apiclient.Client {
+	//The router is read in each request, as some tests rebuild it
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testEnv.Router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	apiClient := apiclient.New(server.URL, server.Client())
+	if jwt, ok := ctx.Value(utils.JwtKey).(string); ok {
+		apiClient.SetSession(jwt, ctx.Value(utils.CsrfKey).(string))
+	}
+	return apiClient
+}
+
+// LastMail delivers the queued emails and returns the last one sent
+func (testEnv TestEnvironment) LastMail(t *testing.T) *mocks.SentMail {
+	_, err := svc.Get().Mail.Deliver(context.Background())
+	if err != nil {
+		t.Fatalf("Error delivering the emails: %s", err)
+	}
+	return testEnv.Mail.Last()
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vocablo/api"
+	"vocablo/conf"
+	"vocablo/ent/language"
+	"vocablo/ent/user"
+	"vocablo/svc/word"
+	"vocablo/tracing"
+	"vocablo/utils"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// recordSpans sets a tracer provider that keeps the ended spans in memory, and rebuilds the router so its
+// middleware uses it. It returns the function that restores the previous provider
+func recordSpans() (*tracetest.SpanRecorder, func()) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	testEnv.Router = api.GetRouter()
+	return recorder, func() {
+		otel.SetTracerProvider(previous)
+	}
+}
+
+// endedSpan returns the first ended span with the name
+func endedSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("Span %s not found", name)
+	return nil
+}
+
+func TestTracingRequest(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupQuizTest)
+	defer teardown(t)
+	mainUser, err := client.User.Query().Where(user.UsernameEQ(testUserForm1.Username)).Only(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.UserWord.Create().SetTerm(testWordForm3.Term).SetLang(
+		client.Language.Query().Where(language.CodeEqualFold(testWordForm3.Lang)).OnlyX(ctx)).
+		SetDefinitions(testWordForm3.Definitions).SetUserID(mainUser.ID).SaveX(ctx)
+	recorder, restore := recordSpans()
+	defer restore()
+	var logs bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = zerolog.New(&logs).Hook(tracing.LogHook{})
+	defer func() { log.Logger = previousLogger }()
+
+	body, err := json.Marshal(testCreateQuizForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := testEnv.MakeAuthRequest("POST", "/api/quiz", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	//The service span is a child of the request one, and the queries of the service one
+	requestSpan := endedSpan(t, recorder, "/api/quiz")
+	serviceSpan := endedSpan(t, recorder, "QuizSvc.Create")
+	assert.Equal(t, requestSpan.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+	assert.Equal(t, requestSpan.SpanContext().TraceID(), serviceSpan.SpanContext().TraceID())
+	var querySpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "db.select" && span.Parent().SpanID() == serviceSpan.SpanContext().SpanID() {
+			querySpan = span
+		}
+	}
+	if assert.NotNil(t, querySpan, "The query of the service should be traced") {
+		assert.Contains(t, querySpan.Attributes(), semconv.DBSystemKey.String("sqlite3"))
+	}
+
+	//The request log has the IDs of the trace
+	var requestLog map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		err = json.Unmarshal([]byte(line), &requestLog)
+		if err == nil && requestLog["message"] == "Request" {
+			break
+		}
+	}
+	assert.Equal(t, "Request", requestLog["message"])
+	assert.Equal(t, requestSpan.SpanContext().TraceID().String(), requestLog["trace_id"])
+	assert.Equal(t, "/api/quiz", requestLog["path"])
+}
+
+func TestTracingIncomingContext(t *testing.T) {
+	_, teardown := StartTest(t)
+	defer teardown(t)
+	recorder, restore := recordSpans()
+	defer restore()
+
+	//The trace started by the caller is continued
+	traceId := "4bf92f3577b34da6a3ce929d0e0e4736"
+	recorderResp := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/public/oidc/providers", nil)
+	req.Header.Set("traceparent", "00-"+traceId+"-00f067aa0ba902b7-01")
+	testEnv.Router.ServeHTTP(recorderResp, req)
+	assert.Equal(t, 200, recorderResp.Code, "Response status should be 200")
+	requestSpan := endedSpan(t, recorder, "/api/public/oidc/providers")
+	assert.Equal(t, traceId, requestSpan.SpanContext().TraceID().String())
+
+	//The probes aren't traced
+	resp := testEnv.MakeRequest("GET", "/healthz", nil)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+	for _, span := range recorder.Ended() {
+		assert.NotEqual(t, "/healthz", span.Name())
+	}
+}
+
+func TestTracingDictionary(t *testing.T) {
+	_, teardown, ctx := SetupTest(t, true, nil)
+	defer teardown(t)
+	recorder, restore := recordSpans()
+	defer restore()
+	var traceparent string
+	dictionary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(404)
+	}))
+	defer dictionary.Close()
+	conf.Get().Dictionary.Url = dictionary.URL
+
+	body, err := json.Marshal(word.SearchForm{Term: "unknownword", Lang: "en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := testEnv.MakeAuthRequest("POST", "/api/word/search", utils.GetStringPointer(string(body)), ctx)
+	assert.Equal(t, 200, resp.Code, "Response status should be 200")
+
+	//The trace context is sent to the dictionary, from the span of the call
+	lookupSpan := endedSpan(t, recorder, "WordSvc.searchInApi")
+	assert.Equal(t, endedSpan(t, recorder, "WordSvc.Search").SpanContext().SpanID(), lookupSpan.Parent().SpanID())
+	clientSpan := endedSpan(t, recorder, "HTTP GET")
+	assert.Equal(t, lookupSpan.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	assert.Equal(t, "00-"+clientSpan.SpanContext().TraceID().String()+"-"+clientSpan.SpanContext().SpanID().String()+"-01",
+		traceparent)
+}
+
+func TestTracingSetup(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), conf.TracingConf{Exporter: tracing.NONE_EXPORTER})
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), conf.TracingConf{Exporter: "jaeger"})
+	assert.NotNil(t, err)
+}
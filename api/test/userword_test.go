@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 	"vocablo/customerrors"
 	"vocablo/ent"
@@ -79,7 +80,8 @@ func TestUpdateWord(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	resp := testEnv.MakeAuthRequest("PUT", "/api/userword", utils.GetStringPointer(string(body)), ctx)
+	resp := testEnv.MakeAuthRequestWithHeader("PUT", "/api/userword", utils.GetStringPointer(string(body)),
+		http.Header{"If-Match": {utils.ETag(userWordCreated.Version)}}, ctx)
 
 	assert.Equal(t, 200, resp.Code)
 
@@ -91,6 +93,103 @@ func TestUpdateWord(t *testing.T) {
 	assert.Equal(t, testWordForm1.Definitions[0].Definition, updatedUserWord.Definitions[0].Definition)
 }
 
+func TestUpdateWordWithoutIfMatch(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	userWordCreated := client.UserWord.Query().Where(entuserword.TermEQ(testWordForm1.Term)).OnlyX(ctx)
+
+	body, err := json.Marshal(userword.UpdateForm{ID: userWordCreated.ID.String(), Term: utils.GetStringPointer(UPDATED_TERM)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := testEnv.MakeAuthRequest("PUT", "/api/userword", utils.GetStringPointer(string(body)), ctx)
+	var respBody utils.ResponseBody
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 428, resp.Code)
+	assert.Equal(t, customerrors.PRECONDITION_REQUIRED, *respBody.ErrorCode)
+	assert.Equal(t, testWordForm1.Term, client.UserWord.GetX(ctx, userWordCreated.ID).Term)
+
+	//The * doesn't name the version the change was made on, so it isn't enough
+	resp = testEnv.MakeAuthRequestWithHeader("PUT", "/api/userword", utils.GetStringPointer(string(body)),
+		http.Header{"If-Match": []string{"*"}}, ctx)
+	assert.Equal(t, 428, resp.Code)
+	assert.Equal(t, testWordForm1.Term, client.UserWord.GetX(ctx, userWordCreated.ID).Term)
+}
+
+func TestUpdateWordStaleVersion(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	userWordCreated := client.UserWord.Query().Where(entuserword.TermEQ(testWordForm1.Term)).OnlyX(ctx)
+	staleTag := utils.ETag(userWordCreated.Version)
+
+	//Two tabs edit the same version of the word
+	body, err := json.Marshal(userword.UpdateForm{ID: userWordCreated.ID.String(), Term: utils.GetStringPointer(UPDATED_TERM)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := testEnv.MakeAuthRequestWithHeader("PUT", "/api/userword", utils.GetStringPointer(string(body)),
+		http.Header{"If-Match": {staleTag}}, ctx)
+	assert.Equal(t, 200, resp.Code)
+	currentTag := resp.Header().Get("ETag")
+	assert.NotEqual(t, staleTag, currentTag)
+
+	body, err = json.Marshal(userword.UpdateForm{ID: userWordCreated.ID.String(), Term: utils.GetStringPointer("clobbered")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = testEnv.MakeAuthRequestWithHeader("PUT", "/api/userword", utils.GetStringPointer(string(body)),
+		http.Header{"If-Match": {staleTag}}, ctx)
+	var respBody struct {
+		Data      ent.UserWord `json:"data"`
+		ErrorCode string       `json:"errorCode"`
+	}
+	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The current state is returned to merge the change
+	assert.Equal(t, 412, resp.Code)
+	assert.Equal(t, customerrors.STALE_VERSION, respBody.ErrorCode)
+	assert.Equal(t, UPDATED_TERM, respBody.Data.Term)
+	assert.Equal(t, currentTag, resp.Header().Get("ETag"))
+	assert.Equal(t, UPDATED_TERM, client.UserWord.GetX(ctx, userWordCreated.ID).Term)
+
+	resp = testEnv.MakeAuthRequestWithHeader("PUT", "/api/userword", utils.GetStringPointer(string(body)),
+		http.Header{"If-Match": {staleTag + ", " + currentTag}}, ctx)
+	assert.Equal(t, 200, resp.Code)
+}
+
+func TestGetWordNotModified(t *testing.T) {
+	client, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
+	defer teardown(t)
+	userWordCreated := client.UserWord.Query().Where(entuserword.TermEQ(testWordForm1.Term)).OnlyX(ctx)
+	path := "/api/userword/" + userWordCreated.ID.String()
+
+	resp := testEnv.MakeAuthRequest("GET", path, nil, ctx)
+	assert.Equal(t, 200, resp.Code)
+	etag := resp.Header().Get("ETag")
+	assert.Equal(t, utils.ETag(userWordCreated.Version), etag)
+
+	resp = testEnv.MakeAuthRequestWithHeader("GET", path, nil, http.Header{"If-None-Match": {etag}}, ctx)
+	assert.Equal(t, 304, resp.Code)
+	assert.Empty(t, resp.Body.String())
+	resp = testEnv.MakeAuthRequestWithHeader("GET", path, nil, http.Header{"If-None-Match": {"W/" + etag}}, ctx)
+	assert.Equal(t, 304, resp.Code)
+
+	//Once changed the word is sent again
+	_, err := testEnv.Client(t, ctx).UpdateUserWord(ctx, userword.UpdateForm{ID: userWordCreated.ID.String(),
+		Term: utils.GetStringPointer(UPDATED_TERM)}, userWordCreated.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = testEnv.MakeAuthRequestWithHeader("GET", path, nil, http.Header{"If-None-Match": {etag}}, ctx)
+	assert.Equal(t, 200, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+}
+
 func TestUpdateWordEmptyId(t *testing.T) {
 	_, teardown, ctx := SetupTest(t, true, SetupUserWordTest)
 	defer teardown(t)
@@ -120,7 +219,8 @@ func TestUpdateOtherUserWord(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	resp := testEnv.MakeAuthRequest("PUT", "/api/userword", utils.GetStringPointer(string(body)), ctx)
+	resp := testEnv.MakeAuthRequestWithHeader("PUT", "/api/userword", utils.GetStringPointer(string(body)),
+		http.Header{"If-Match": {utils.ETag(otherUserWord.Version)}}, ctx)
 
 	var respBody utils.ResponseBody
 	err = json.Unmarshal(resp.Body.Bytes(), &respBody)
@@ -255,8 +355,13 @@ func TestDeleteWord(t *testing.T) {
 
 	assert.Equal(t, 200, resp.Code)
 
-	_, err = client.UserWord.Query().Where(entuserword.IDEQ(userWordCreated.ID)).Only(ctx)
-	assert.Error(t, err)
+	//The word is kept as a tombstone for the sync
+	deleted, err := client.UserWord.Query().Where(entuserword.IDEQ(userWordCreated.ID)).Only(ctx)
+	if assert.Nil(t, err) {
+		assert.NotNil(t, deleted.DeletedAt)
+	}
+	resp = testEnv.MakeAuthRequest("GET", "/api/userword/"+userWordCreated.ID.String(), nil, ctx)
+	assert.Equal(t, 404, resp.Code)
 }
 
 func TestDeleteOtherUserWord(t *testing.T) {
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"vocablo/customerrors"
-	"vocablo/schema"
 	"vocablo/svc"
 	"vocablo/svc/userword"
 	"vocablo/utils"
@@ -16,7 +15,7 @@ func Create(c *gin.Context) {
 	var form userword.CreateForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -24,16 +23,11 @@ func Create(c *gin.Context) {
 	createdWord, err := svc.UserWord.Create(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.EmptyFormFieldsError:
-			res = utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields not present"), nil)
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		createdWordJson, err := json.Marshal(createdWord)
 		if err != nil {
-			res = utils.InternalError(err)
+			res = utils.Error(c.Request.Context(), err)
 		}
 		res = utils.SuccessResponse(createdWordJson)
 	}
@@ -44,7 +38,19 @@ func Update(c *gin.Context) {
 	var form userword.UpdateForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	//The words can only be changed from the version the client has, to not overwrite the changes of others
+	var header userword.UpdateHeader
+	err = c.ShouldBindHeader(&header)
+	if err == nil {
+		form.Versions = utils.IfMatchVersions(header.IfMatch)
+	}
+	//The * of If-Match would change any version, so it isn't accepted either
+	if form.Versions == nil {
+		res := utils.Error(c.Request.Context(), customerrors.PreconditionRequiredError{})
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -52,16 +58,12 @@ func Update(c *gin.Context) {
 	updatedWord, err := svc.UserWord.Update(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.EmptyFormFieldsError:
-			res = utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields not present"), nil)
-		case customerrors.NotAllowedResourceError:
-			res = utils.ErrorResponse(http.StatusForbidden,
-				utils.GetStringPointer("You dont have permissions to edit this element"), utils.GetStringPointer(customerrors.NOT_ALLOWED_RESOURCE))
-		default:
-			res = utils.InternalError(err)
+		if stale, ok := err.(customerrors.StaleVersionError); ok {
+			c.Header("ETag", utils.ETag(stale.Version))
 		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
+		c.Header("ETag", utils.ETag(updatedWord.Version))
 		res = utils.SuccessResponse(updatedWord)
 	}
 	c.JSON(res.Status, res.Body)
@@ -70,7 +72,14 @@ func Update(c *gin.Context) {
 func Get(c *gin.Context) {
 	id, present := c.Params.Get("id")
 	if !present {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields not present"), nil)
+		res := utils.Error(c.Request.Context(), customerrors.EmptyFormFieldsError{})
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	var header userword.GetHeader
+	err := c.ShouldBindHeader(&header)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -78,13 +87,14 @@ func Get(c *gin.Context) {
 	word, err := svc.UserWord.Get(c.Request.Context(), id)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.NotFoundError:
-			res = utils.ErrorResponse(http.StatusNotFound, utils.GetStringPointer("Word not found"), nil)
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
+		etag := utils.ETag(word.Version)
+		c.Header("ETag", etag)
+		if utils.IfNoneMatch(header.IfNoneMatch, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
 		res = utils.SuccessResponse(word)
 	}
 	c.JSON(res.Status, res.Body)
@@ -94,7 +104,7 @@ func Search(c *gin.Context) {
 	var form userword.SearchForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer(err.Error()), nil)
+		res := utils.InvalidRequest(c.Request.Context(), err)
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -103,7 +113,7 @@ func Search(c *gin.Context) {
 	page, err := svc.UserWord.Search(c.Request.Context(), form)
 	var res utils.HttpResponse
 	if err != nil {
-		res = utils.InternalError(err)
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(page)
 	}
@@ -113,7 +123,7 @@ func Search(c *gin.Context) {
 func Delete(c *gin.Context) {
 	id, present := c.Params.Get("id")
 	if !present {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields not present"), nil)
+		res := utils.Error(c.Request.Context(), customerrors.EmptyFormFieldsError{})
 		c.AbortWithStatusJSON(res.Status, res.Body)
 		return
 	}
@@ -121,13 +131,7 @@ func Delete(c *gin.Context) {
 	err := svc.UserWord.Delete(c.Request.Context(), id)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.NotAllowedResourceError:
-			res = utils.ErrorResponse(http.StatusForbidden, utils.GetStringPointer("You dont have permissions to delete this element"),
-				utils.GetStringPointer(customerrors.NOT_ALLOWED_RESOURCE))
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(nil)
 	}
@@ -136,40 +140,12 @@ func Delete(c *gin.Context) {
 
 func UserProgress(c *gin.Context) {
 	svc := svc.Get()
-
-	//We search the total words of the user
-	searchForm := userword.SearchForm{Count: true}
-
-	totalWordsPage, err := svc.UserWord.Search(c.Request.Context(), searchForm)
-	if err != nil {
-		res := utils.InternalError(err)
-		c.JSON(res.Status, res.Body)
-		return
-	}
-
-	//We search the learned words of the user
-	searchForm.Learned = utils.GetBoolPointer(true)
-	learnedWordsPage, err := svc.UserWord.Search(c.Request.Context(), searchForm)
-	if err != nil {
-		res := utils.InternalError(err)
-		c.JSON(res.Status, res.Body)
-		return
-	}
-
-	//We search the not learned words of the user
-	searchForm.Learned = utils.GetBoolPointer(false)
-	notLearnedWordsPage, err := svc.UserWord.Search(c.Request.Context(), searchForm)
+	progress, err := svc.UserWord.Progress(c.Request.Context())
+	var res utils.HttpResponse
 	if err != nil {
-		res := utils.InternalError(err)
-		c.JSON(res.Status, res.Body)
-		return
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(progress)
 	}
-
-	var userProgress schema.UserWordProgress = schema.UserWordProgress{
-		TotalWords:     totalWordsPage.NElements,
-		LearnedWords:   learnedWordsPage.NElements,
-		UnlearnedWords: notLearnedWordsPage.NElements}
-
-	res := utils.SuccessResponse(userProgress)
 	c.JSON(res.Status, res.Body)
 }
@@ -1,7 +1,6 @@
 package word
 
 import (
-	"net/http"
 	"vocablo/customerrors"
 	"vocablo/svc"
 	"vocablo/svc/word"
@@ -14,12 +13,12 @@ func Search(c *gin.Context) {
 	var form word.SearchForm
 	err := c.ShouldBind(&form)
 	if err != nil {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields are empty"), nil)
+		res := utils.Error(c.Request.Context(), customerrors.EmptyFormFieldsError{})
 		c.JSON(res.Status, res.Body)
 		return
 	}
 	if form.Term == "" || form.Lang == "" {
-		res := utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields are empty"), nil)
+		res := utils.Error(c.Request.Context(), customerrors.EmptyFormFieldsError{})
 		c.JSON(res.Status, res.Body)
 		return
 	}
@@ -28,14 +27,41 @@ func Search(c *gin.Context) {
 	words, err := svc.Word.Search(c.Request.Context(), form.Lang, form.Term)
 	var res utils.HttpResponse
 	if err != nil {
-		switch err.(type) {
-		case customerrors.EmptyFormFieldsError:
-			res = utils.ErrorResponse(http.StatusBadRequest, utils.GetStringPointer("Mandatory fields are empty"), nil)
-		default:
-			res = utils.InternalError(err)
-		}
+		res = utils.Error(c.Request.Context(), err)
 	} else {
 		res = utils.SuccessResponse(words)
 	}
 	c.JSON(res.Status, res.Body)
 }
+
+func Update(c *gin.Context) {
+	var form word.UpdateForm
+	err := c.ShouldBind(&form)
+	if err != nil {
+		res := utils.InvalidRequest(c.Request.Context(), err)
+		c.AbortWithStatusJSON(res.Status, res.Body)
+		return
+	}
+	svc := svc.Get()
+	updatedWord, err := svc.Word.Update(c.Request.Context(), form)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(updatedWord)
+	}
+	c.JSON(res.Status, res.Body)
+}
+
+func Delete(c *gin.Context) {
+	id, _ := c.Params.Get("id")
+	svc := svc.Get()
+	err := svc.Word.Delete(c.Request.Context(), id)
+	var res utils.HttpResponse
+	if err != nil {
+		res = utils.Error(c.Request.Context(), err)
+	} else {
+		res = utils.SuccessResponse(nil)
+	}
+	c.JSON(res.Status, res.Body)
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/user"
+	"vocablo/svc"
+	"vocablo/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RunCreateAdmin parses the create-admin arguments and bootstraps the first admin. The password can be passed
+// in the ADMIN_PASSWORD env var to keep it out of the shell history
+func RunCreateAdmin(ctx context.Context, client *ent.Client, args []string) (*ent.User, error) {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := flags.String("username", "", "username of the admin, an existing user is promoted")
+	email := flags.String("email", "", "email of the admin, only needed when the user doesn't exist")
+	password := flags.String("password", os.Getenv("ADMIN_PASSWORD"), "password of the admin, only needed when the user doesn't exist")
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if *username == "" {
+		return nil, errors.New("the username is mandatory")
+	}
+	return CreateAdmin(ctx, client, *username, *email, *password)
+}
+
+// CreateAdmin promotes the user with the given username to admin, creating it if it doesn't exist. It is refused
+// once there is an admin, as the next ones should be managed from the admin API
+func CreateAdmin(ctx context.Context, client *ent.Client, username string, email string, password string) (*ent.User, error) {
+	adminExists, err := client.User.Query().Where(user.RoleEQ(utils.ADMIN_ROLE)).Exist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if adminExists {
+		return nil, customerrors.AdminAlreadyExistsError{}
+	}
+	existingUser, err := client.User.Query().Where(user.UsernameEQ(username)).Only(ctx)
+	if err == nil {
+		return client.User.UpdateOne(existingUser).SetRole(utils.ADMIN_ROLE).SetValidated(true).SetDisabled(false).Save(ctx)
+	}
+	if !ent.IsNotFound(err) {
+		return nil, err
+	}
+	if email == "" || password == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	if err := svc.Get().Password.Check(password, username, email); err != nil {
+		return nil, err
+	}
+	bytesPass, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	if err != nil {
+		return nil, err
+	}
+	return client.User.Create().SetUsername(username).SetEmail(email).SetPassword(string(bytesPass[:])).
+		SetValidated(true).SetRole(utils.ADMIN_ROLE).Save(ctx)
+}
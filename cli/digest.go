@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"time"
+	"vocablo/ent"
+	"vocablo/ent/user"
+	"vocablo/svc"
+)
+
+// RunSendDigests parses the send-digests arguments and sends the weekly digests that are due, as the background
+// job does. With a username the digest of that user is sent even if it is not due, to try the email out. The
+// queued emails are delivered before returning, and the number of digests sent is returned
+func RunSendDigests(ctx context.Context, client *ent.Client, args []string) (int, error) {
+	flags := flag.NewFlagSet("send-digests", flag.ContinueOnError)
+	username := flags.String("username", "", "send the digest only to this user, even if it is not due")
+	nowFlag := flags.String("now", "", "RFC 3339 date the digests are generated at, the current one by default")
+	err := flags.Parse(args)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	if *nowFlag != "" {
+		now, err = time.Parse(time.RFC3339, *nowFlag)
+		if err != nil {
+			return 0, err
+		}
+	}
+	sent := 1
+	if *username != "" {
+		digestUser, err := client.User.Query().Where(user.UsernameEQ(*username)).Only(ctx)
+		if err != nil {
+			return 0, err
+		}
+		err = svc.Get().Notification.SendDigest(ctx, digestUser.ID, now)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		sent, err = svc.Get().Notification.SendDigests(ctx, now)
+		if err != nil {
+			return sent, err
+		}
+	}
+	//The process exits right after, so the outbox is not left to the scheduled delivery
+	for {
+		delivered, err := svc.Get().Mail.Deliver(ctx)
+		if err != nil {
+			return sent, err
+		}
+		if delivered == 0 {
+			return sent, nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"vocablo/ent"
+	"vocablo/svc/auth"
+)
+
+// Login logs in, keeping the session for the next requests
+func (c *Client) Login(ctx context.Context, form auth.LoginForm) error {
+	var csrf string
+	err := c.do(ctx, "POST", "/api/public/login", form, &csrf)
+	if err != nil {
+		return err
+	}
+	c.csrf = csrf
+	return nil
+}
+
+// Logout forgets the session. The API has no logout, the token is valid until it expires or is revoked
+func (c *Client) Logout() {
+	c.SetSession("", "")
+}
+
+// SignUp creates an account, which has to be validated with the code sent by email before logging in
+func (c *Client) SignUp(ctx context.Context, form auth.SignUpForm) (*ent.User, error) {
+	var user ent.User
+	err := c.do(ctx, "POST", "/api/public/register", form, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) ValidateAccount(ctx context.Context, username string, code string) error {
+	return c.do(ctx, "POST", "/api/public/validate/"+url.PathEscape(username)+"/"+url.PathEscape(code), nil, nil)
+}
+
+func (c *Client) ResendValidationCode(ctx context.Context, username string) error {
+	return c.do(ctx, "POST", "/api/public/validate/"+url.PathEscape(username)+"/resend", nil, nil)
+}
+
+func (c *Client) SendForgottenPasswordCode(ctx context.Context, username string) error {
+	return c.do(ctx, "POST", "/api/public/forgotten-password/"+url.PathEscape(username), nil, nil)
+}
+
+// ResetPassword sets a new password with the code sent by SendForgottenPasswordCode
+func (c *Client) ResetPassword(ctx context.Context, username string, code string, newPassword string) error {
+	return c.send(ctx, "POST", "/api/public/reset-password/"+url.PathEscape(username)+"/"+url.PathEscape(code),
+		http.Header{"Content-Type": {"text/plain"}}, strings.NewReader(newPassword), nil)
+}
+
+// Self returns the user of the session
+func (c *Client) Self(ctx context.Context) (*ent.User, error) {
+	var user ent.User
+	err := c.do(ctx, "GET", "/api/self", nil, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangePassword changes the password of the user. The other sessions are revoked, and this one is renewed
+func (c *Client) ChangePassword(ctx context.Context, form auth.ChangePasswordForm) error {
+	var csrf string
+	err := c.do(ctx, "PUT", "/api/self/password", form, &csrf)
+	if err != nil {
+		return err
+	}
+	c.csrf = csrf
+	return nil
+}
+
+// DeleteAccount deletes the account of the user and forgets the session
+func (c *Client) DeleteAccount(ctx context.Context) error {
+	err := c.do(ctx, "DELETE", "/api/account", nil, nil)
+	if err != nil {
+		return err
+	}
+	c.Logout()
+	return nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"vocablo/utils"
+)
+
+const (
+	jwtCookie  = "JWT_TOKEN"
+	csrfHeader = "X-API-CSRF"
+)
+
+// Client calls the Vocablo API. It keeps the session of the last login, sending its cookie and CSRF header in
+// the requests. It isn't safe to log in with it from several goroutines at the same time
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+	jwt        string
+	csrf       string
+}
+
+// New creates a client of the API served at the base URL, e.g. https://vocablo.example.com. Without an HTTP
+// client the default one is used. The session cookie is handled by the client, so it doesn't need a jar
+func New(baseUrl string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseUrl: strings.TrimSuffix(baseUrl, "/"), httpClient: httpClient}
+}
+
+// SetSession sets the session of a login made elsewhere, from its token and CSRF token
+func (c *Client) SetSession(jwt string, csrf string) {
+	c.jwt = jwt
+	c.csrf = csrf
+}
+
+// Session returns the token and CSRF token of the current session, empty without one
+func (c *Client) Session() (string, string) {
+	return c.jwt, c.csrf
+}
+
+// do sends the request with the body as JSON, and decodes the data of the response envelope into data. The
+// responses with an error code are returned as an *Error
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, data interface{}) error {
+	return c.doWithHeader(ctx, method, path, http.Header{}, body, data)
+}
+
+// doWithHeader is do sending the header too, e.g. with the preconditions of the request
+func (c *Client) doWithHeader(ctx context.Context, method string, path string, header http.Header, body interface{},
+	data interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(encoded)
+		header.Set("Content-Type", "application/json")
+	}
+	return c.send(ctx, method, path, header, bodyReader, data)
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, header http.Header, body io.Reader, data interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	if c.jwt != "" {
+		req.AddCookie(&http.Cookie{Name: jwtCookie, Value: c.jwt})
+		req.Header.Set(csrfHeader, c.csrf)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == jwtCookie {
+			c.jwt = cookie.Value
+		}
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var rawData json.RawMessage
+	envelope := utils.ResponseBody{Data: &rawData}
+	err = json.Unmarshal(respBody, &envelope)
+	if err != nil {
+		//The errors of the router, e.g. an unknown route, aren't in the envelope
+		if resp.StatusCode >= http.StatusBadRequest {
+			return newError(resp.StatusCode, resp.Header, utils.ResponseBody{ErrorMessage: utils.GetStringPointer(string(respBody))}, nil)
+		}
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest || envelope.ErrorCode != nil {
+		return newError(resp.StatusCode, resp.Header, envelope, rawData)
+	}
+	if data == nil || len(rawData) == 0 {
+		return nil
+	}
+	return json.Unmarshal(rawData, data)
+}
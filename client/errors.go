@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"vocablo/customerrors"
+	"vocablo/utils"
+)
+
+// Error is an error answered by the API. It wraps the error of the services of its code, so it can be checked
+// with errors.As, e.g. for a customerrors.NotEnoughWordsForQuizError
+type Error struct {
+	Status    int
+	Code      string
+	Message   string
+	RequestId string
+	// Data are the details of the error, e.g. the failed rules of a weak password
+	Data json.RawMessage
+	// ETag of the resource, e.g. the current version of a stale one
+	ETag  string
+	cause error
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// codeErrors are the errors of the services answered with each code. The rest of the codes, like the ones of an
+// invalid session, don't have one
+var codeErrors = map[string]func(e *Error) error{
+	customerrors.EMPTY_FORM_FIELDS:            func(e *Error) error { return customerrors.EmptyFormFieldsError{} },
+	customerrors.NOT_VALIDATED_ACCOUNT:        func(e *Error) error { return customerrors.NotValidatedAccountError{} },
+	customerrors.DISABLED_ACCOUNT:             func(e *Error) error { return customerrors.DisabledAccountError{} },
+	customerrors.ALREADY_VALIDATED_ACCOUNT:    func(e *Error) error { return customerrors.UserAlreadyValidatedError{} },
+	customerrors.USERNAME_ALREADY_IN_USE:      func(e *Error) error { return customerrors.UsernameAlreadyInUseError{} },
+	customerrors.EMAIL_ALREADY_IN_USE:         func(e *Error) error { return customerrors.EmailAlreadyInUseError{} },
+	customerrors.ALREADY_USED_VALIDATION_CODE: func(e *Error) error { return customerrors.AlreadyUsedValidationCodeError{} },
+	customerrors.EXPIRED_VALIDATION_CODE:      func(e *Error) error { return customerrors.ExpiredValidationCodeError{} },
+	customerrors.INCORRECT_VALIDATION_CODE:    func(e *Error) error { return customerrors.IncorrectValidationCodeError{} },
+	customerrors.INVALID_MAGIC_LINK:           func(e *Error) error { return customerrors.InvalidMagicLinkError{} },
+	customerrors.UNKNOWN_OIDC_PROVIDER:        func(e *Error) error { return customerrors.UnknownOIDCProviderError{} },
+	customerrors.INVALID_OIDC_STATE:           func(e *Error) error { return customerrors.InvalidOIDCStateError{} },
+	customerrors.OIDC_AUTHENTICATION_FAILED:   func(e *Error) error { return customerrors.OIDCAuthenticationError{} },
+	customerrors.NOT_VERIFIED_OIDC_EMAIL:      func(e *Error) error { return customerrors.NotVerifiedOIDCEmailError{} },
+	customerrors.NOT_ALLOWED_RESOURCE:         func(e *Error) error { return customerrors.NotAllowedResourceError{} },
+	customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ:    func(e *Error) error { return customerrors.NotEnoughWordsForQuizError{} },
+	customerrors.EXPIRED_DOWNLOAD_TOKEN:       func(e *Error) error { return customerrors.ExpiredDownloadTokenError{} },
+	customerrors.INVALID_ROLE:                 func(e *Error) error { return customerrors.InvalidRoleError{} },
+	customerrors.ADMIN_ALREADY_EXISTS:         func(e *Error) error { return customerrors.AdminAlreadyExistsError{} },
+	customerrors.LANGUAGE_ALREADY_EXISTS:      func(e *Error) error { return customerrors.LanguageAlreadyExistsError{} },
+	customerrors.INVALID_TIMEZONE:             func(e *Error) error { return customerrors.InvalidTimezoneError{} },
+	customerrors.INVALID_REMINDER_TIME:        func(e *Error) error { return customerrors.InvalidReminderTimeError{} },
+	customerrors.INVALID_UNSUBSCRIBE_TOKEN:    func(e *Error) error { return customerrors.InvalidUnsubscribeTokenError{} },
+	customerrors.DICTIONARY_UNAVAILABLE:       func(e *Error) error { return customerrors.DictionaryUnavailableError{} },
+	customerrors.INVALID_SYNC_CURSOR:          func(e *Error) error { return customerrors.InvalidSyncCursorError{} },
+	customerrors.PRECONDITION_REQUIRED:        func(e *Error) error { return customerrors.PreconditionRequiredError{} },
+	customerrors.INVALID_CREDENTIALS: func(e *Error) error {
+		//The incorrect current password of a change shares the code, with another status
+		if e.Status == http.StatusForbidden {
+			return customerrors.IncorrectCurrentPasswordError{}
+		}
+		return customerrors.InvalidCredentialsError{}
+	},
+	customerrors.INVALID_LANGUAGE_CODE: func(e *Error) error {
+		return customerrors.InvalidLanguageCodeError{Code: strings.TrimPrefix(e.Message, "Invalid ISO 639-1 language code: ")}
+	},
+	customerrors.LANGUAGE_NOT_FOUND: func(e *Error) error {
+		return customerrors.LanguageNotFoundError{Code: strings.TrimPrefix(e.Message, "Language not supported: ")}
+	},
+	customerrors.NOT_FOUND: func(e *Error) error {
+		return customerrors.NotFoundError{Resource: strings.TrimSuffix(e.Message, " not found")}
+	},
+	customerrors.STALE_VERSION: func(e *Error) error {
+		//The current version is the one of its tag, and its resource is in the data
+		version, _ := strconv.Atoi(strings.Trim(e.ETag, `"`))
+		return customerrors.StaleVersionError{Version: version, Current: e.Data}
+	},
+	customerrors.WEAK_PASSWORD: func(e *Error) error {
+		var rules []string
+		json.Unmarshal(e.Data, &rules)
+		return customerrors.WeakPasswordError{Rules: rules}
+	},
+}
+
+func newError(status int, header http.Header, body utils.ResponseBody, data json.RawMessage) *Error {
+	err := &Error{Status: status, RequestId: body.RequestId, Data: data, ETag: header.Get("ETag")}
+	if body.ErrorCode != nil {
+		err.Code = *body.ErrorCode
+	}
+	if body.ErrorMessage != nil {
+		err.Message = *body.ErrorMessage
+	}
+	if codeError, ok := codeErrors[err.Code]; ok {
+		err.cause = codeError(err)
+	}
+	return err
+}
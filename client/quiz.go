@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"vocablo/svc/quiz"
+)
+
+// CreateQuiz creates a quiz with the words of the user
+func (c *Client) CreateQuiz(ctx context.Context, form quiz.CreateForm) (*quiz.Quiz, error) {
+	var createdQuiz quiz.Quiz
+	err := c.do(ctx, "POST", "/api/quiz", form, &createdQuiz)
+	if err != nil {
+		return nil, err
+	}
+	return &createdQuiz, nil
+}
+
+// AnswerQuiz sends the quiz with the answers filled, and returns the score
+func (c *Client) AnswerQuiz(ctx context.Context, filledQuiz quiz.Quiz) (int, error) {
+	var score int
+	err := c.do(ctx, "POST", "/api/quiz/answer", filledQuiz, &score)
+	return score, err
+}
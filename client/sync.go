@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"vocablo/svc/deltasync"
+)
+
+// PullChanges returns the changes of the words of the user since the cursor of the last pull, all the words
+// without one
+func (c *Client) PullChanges(ctx context.Context, form deltasync.PullForm) (*deltasync.Changes, error) {
+	query := url.Values{}
+	if form.Since != "" {
+		query.Set("since", form.Since)
+	}
+	if form.Limit > 0 {
+		query.Set("limit", strconv.Itoa(form.Limit))
+	}
+	path := "/api/sync"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var changes deltasync.Changes
+	err := c.do(ctx, "GET", path, nil, &changes)
+	if err != nil {
+		return nil, err
+	}
+	return &changes, nil
+}
+
+// PushChanges sends the changes and quiz answers made offline, and returns how each one was resolved
+func (c *Client) PushChanges(ctx context.Context, form deltasync.PushForm) (*deltasync.PushResult, error) {
+	var result deltasync.PushResult
+	err := c.do(ctx, "POST", "/api/sync", form, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
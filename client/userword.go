@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"vocablo/ent"
+	"vocablo/schema"
+	"vocablo/svc/userword"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+)
+
+// CreateUserWord adds a word to the user
+func (c *Client) CreateUserWord(ctx context.Context, form userword.CreateForm) (*ent.UserWord, error) {
+	//The created word is sent as its encoded JSON
+	var encoded []byte
+	err := c.do(ctx, "POST", "/api/userword", form, &encoded)
+	if err != nil {
+		return nil, err
+	}
+	var userWord ent.UserWord
+	err = json.Unmarshal(encoded, &userWord)
+	if err != nil {
+		return nil, err
+	}
+	return &userWord, nil
+}
+
+// UpdateUserWord changes a word of the user if it is still in the version given. Otherwise it returns a
+// customerrors.StaleVersionError with the current word as JSON
+func (c *Client) UpdateUserWord(ctx context.Context, form userword.UpdateForm, version int) (*ent.UserWord, error) {
+	var userWord ent.UserWord
+	err := c.doWithHeader(ctx, "PUT", "/api/userword", http.Header{"If-Match": {utils.ETag(version)}}, form, &userWord)
+	if err != nil {
+		return nil, err
+	}
+	return &userWord, nil
+}
+
+func (c *Client) GetUserWord(ctx context.Context, id uuid.UUID) (*ent.UserWord, error) {
+	var userWord ent.UserWord
+	err := c.do(ctx, "GET", "/api/userword/"+id.String(), nil, &userWord)
+	if err != nil {
+		return nil, err
+	}
+	return &userWord, nil
+}
+
+func (c *Client) DeleteUserWord(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, "DELETE", "/api/userword/"+id.String(), nil, nil)
+}
+
+func (c *Client) SearchUserWords(ctx context.Context, form userword.SearchForm) (*utils.Page[*ent.UserWord], error) {
+	var page utils.Page[*ent.UserWord]
+	err := c.do(ctx, "POST", "/api/userword/search", form, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UserWordProgress returns the number of learned and pending words of the user
+func (c *Client) UserWordProgress(ctx context.Context) (*schema.UserWordProgress, error) {
+	var progress schema.UserWordProgress
+	err := c.do(ctx, "GET", "/api/userword/progress", nil, &progress)
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
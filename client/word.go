@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"vocablo/ent"
+	"vocablo/svc/word"
+	"vocablo/utils"
+)
+
+// SearchWord searches a word in the dictionary
+func (c *Client) SearchWord(ctx context.Context, form word.SearchForm) (*utils.Page[*ent.Word], error) {
+	var page utils.Page[*ent.Word]
+	err := c.do(ctx, "POST", "/api/word/search", form, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Languages returns the supported languages
+func (c *Client) Languages(ctx context.Context) ([]*ent.Language, error) {
+	var languages []*ent.Language
+	err := c.do(ctx, "GET", "/api/language", nil, &languages)
+	if err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
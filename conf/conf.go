@@ -5,20 +5,38 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
 type Conf struct {
-	Env    string
-	Dev    EnvConf
-	Prod   EnvConf
-	IP     string
-	Port   string
-	DB     DatabaseConf
-	Mail   MailConf
-	JwtKey string
+	Env               string
+	Dev               EnvConf
+	Prod              EnvConf
+	IP                string
+	Port              string
+	Server            ServerConf
+	DB                DatabaseConf
+	Mail              MailConf
+	JwtKey            string
+	Jwt               JwtConf
+	PasswordPolicy    PasswordPolicyConf
+	OIDC              OIDCConf
+	VerificationCodes VerificationCodesConf
+	Notifications     NotificationsConf
+	Dictionary        DictionaryConf
+	Health            HealthConf
+	Metrics           MetricsConf
+	Grpc              GrpcConf
+	Sync              SyncConf
+	Exports           ExportsConf
+	Docs              DocsConf
+	Tracing           TracingConf
+	// ISO 639-1 codes of the languages seeded at startup
+	Languages []string
 }
 
 type EnvConf struct {
@@ -27,6 +45,33 @@ type EnvConf struct {
 	CookieHttpOnly bool
 	CorsHost       string
 	FrontUrl       string
+	// Public url of the API, for the links of the emails handled by it and not by the web app. The one of the web
+	// app when unset, for when it proxies the API
+	ApiUrl string
+}
+
+type ServerConf struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// How long the server keeps accepting requests, while reporting not ready, before shutting down, so the
+	// load balancer stops sending traffic first
+	DrainDelay time.Duration
+	// How long the in-flight requests and background jobs are waited for on shutdown
+	ShutdownTimeout time.Duration
+	TLS             TLSConf
+	// IPs or CIDRs of the reverse proxies whose X-Forwarded-For header is trusted to get the IP of the client.
+	// With none, the IP of the client is the one of the connection
+	TrustedProxies []string
+}
+
+// TLSConf enables HTTPS when the certificate and key files are set, relative to the configuration file
+type TLSConf struct {
+	CertFile string
+	KeyFile  string
+	// Minimum TLS version, 1.2 or 1.3. 1.2 by default
+	MinVersion string
 }
 
 type DatabaseConf struct {
@@ -42,21 +87,168 @@ type MailConf struct {
 	Pass     string
 	SmtpHost string
 	SmtpPort string
-	Texts    MailTexts
+	// Limit for the whole delivery of an email to the SMTP server
+	SmtpTimeout time.Duration
+	FromName    string
+	// Directory with the mail templates, relative to the configuration file
+	TemplatesDir string
+	// Locale used when there is no template in the one of the recipient
+	DefaultLocale string
+	// How the emails are delivered: smtp, file (a maildir in FileDir, for local development) or noop
+	Driver  string
+	FileDir string
+	Outbox  OutboxConf
+}
+
+// OutboxConf controls the delivery of the queued emails. A failed email is retried with an exponential backoff
+// from MinBackoff to MaxBackoff, and after MaxAttempts it is kept as dead. The bodies of the sent and the dead
+// emails are cleared, as they may have codes and links
+type OutboxConf struct {
+	Interval    time.Duration
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// How long the sent emails are kept
+	Retention time.Duration
+}
+
+// JwtConf has the asymmetric keys used to sign the session tokens. The active key signs the new tokens,
+// and the rest are only used to verify the ones issued before rotating it, so they can be kept with only
+// the public key. Without keys, the tokens are signed with HS256 and JwtKey.
+type JwtConf struct {
+	ActiveKey string
+	Keys      []JwtKeyConf
+	// Keeps accepting the tokens signed with JwtKey once there is an active key, while the sessions issued
+	// before moving to the keys expire. Off by default, so a leaked secret stops working
+	AcceptSharedSecret bool
+}
+
+type JwtKeyConf struct {
+	Id             string
+	PrivateKeyFile string
+	PublicKeyFile  string
+}
+
+type PasswordPolicyConf struct {
+	MinLength     int
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// File with the SHA-1 of breached passwords, one per line in the "HASH:COUNT" format of Pwned Passwords,
+	// ordered by hash
+	BreachedListFile string
+	// Fails the startup when the breached list can't be opened, instead of going on without it
+	RequireBreachedList bool
+}
+
+type NotificationsConf struct {
+	// How often the users due for a study reminder are checked
+	ReminderInterval time.Duration
+	// How long the unsubscribe links of the emails work
+	UnsubscribeTTL time.Duration
+	// How often the users due for the weekly digest are checked
+	DigestInterval time.Duration
+	// Day of the week the digest is sent, in the timezone of every user
+	DigestWeekday string
+}
+
+// DictionaryConf is the external dictionary API used for the words not found in the database. After
+// FailureThreshold consecutive failures it isn't called for OpenDuration
+type DictionaryConf struct {
+	Url              string
+	Timeout          time.Duration
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+type HealthConf struct {
+	// Time limit of every readiness check
+	CheckTimeout time.Duration
+	// Pending emails in the outbox from which it is reported as degraded
+	MaxOutboxBacklog int
+}
+
+type MetricsConf struct {
+	Enabled bool
+	// Port of a separate listener for /metrics, to keep it out of the public one. Without it, /metrics is
+	// served with the API
+	Port string
+}
+
+// GrpcConf serves the UserWord, Word and Quiz services with gRPC in their own port, with the TLS of the server
+type GrpcConf struct {
+	Enabled bool
+	Port    string
+}
+
+// TracingConf exports the spans of the requests, services and DB queries to stdout or an OTLP collector
+type TracingConf struct {
+	// none, stdout or otlp
+	Exporter string
+	// URL of the OTLP/HTTP collector, the OTEL_EXPORTER_OTLP_ENDPOINT env var is used without it
+	Endpoint    string
+	ServiceName string
+	// Fraction of the traces sampled, between 0 and 1, all of them when unset
+	SampleRatio float64
+}
+
+// SyncConf controls how long the deleted words are kept for the clients that sync offline. A client that didn't
+// sync since before a deleted word was purged has to sync again from the start
+type SyncConf struct {
+	// How often the deleted words older than the retention are purged
+	PurgeInterval time.Duration
+	// How long the deleted words are kept
+	TombstoneRetention time.Duration
+}
+
+type DocsConf struct {
+	// Standalone bundle of Redoc 2.1.5, relative to the configuration file, served to render the documentation
+	// instead of loading it from a CDN. It is the one of https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js
+	RedocFile string
+}
+
+type ExportsConf struct {
+	// How often the expired data exports are deleted with their archives
+	PurgeInterval time.Duration
+}
+
+type VerificationCodesConf struct {
+	// How often the used and expired codes are deleted
+	PurgeInterval time.Duration
+	// Settings by code type, e.g. validate_account or reset_password
+	Types map[string]VerificationCodeConf
 }
 
-type MailTexts struct {
-	ValidateEmail MailText
-	ResetPassword MailText
+type VerificationCodeConf struct {
+	Length int
+	TTL    time.Duration
 }
 
-type MailText struct {
-	Subject string
-	Body    string
+type OIDCConf struct {
+	Providers []OIDCProviderConf
+}
+
+// OIDCProviderConf describes an external identity provider. Type is "oidc" for providers
+// exposing a discovery document (Google or any generic OIDC issuer) and "github" for GitHub,
+// which only implements plain OAuth2.
+type OIDCProviderConf struct {
+	Name         string
+	Type         string
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+	AuthUrl      string
+	TokenUrl     string
+	ApiUrl       string
 }
 
 var conf Conf
 
+const defaultJwtKey = "vocablosecret"
+
 func Setup() error {
 	_, currentPath, _, _ := runtime.Caller(0)
 
@@ -79,10 +271,50 @@ func Setup() error {
 	if os.Getenv("MAIL_PASS") != "" {
 		conf.Mail.Pass = os.Getenv("MAIL_PASS")
 	}
+	for i, provider := range conf.OIDC.Providers {
+		envPrefix := "OIDC_" + strings.ToUpper(provider.Name)
+		if os.Getenv(envPrefix+"_CLIENT_ID") != "" {
+			conf.OIDC.Providers[i].ClientId = os.Getenv(envPrefix + "_CLIENT_ID")
+		}
+		if os.Getenv(envPrefix+"_CLIENT_SECRET") != "" {
+			conf.OIDC.Providers[i].ClientSecret = os.Getenv(envPrefix + "_CLIENT_SECRET")
+		}
+	}
 	log.Info().Msg("Conf file loaded succesfully")
 	return nil
 }
 
+// ResolvePath returns the path relative to the configuration file directory, if it isn't absolute
+func ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(viper.ConfigFileUsed()), path)
+}
+
+// Validate checks that the configuration is safe to run with
+func Validate() error {
+	if conf.Env == "prod" && (conf.JwtKey == "" || conf.JwtKey == defaultJwtKey) {
+		return errors.New("the default JWT secret can't be used in prod, set the JWT_SECRET env var")
+	}
+	tlsConf := conf.Server.TLS
+	if (tlsConf.CertFile == "") != (tlsConf.KeyFile == "") {
+		return errors.New("both the TLS certificate and key files are needed to enable TLS")
+	}
+	if tlsConf.MinVersion != "" && tlsConf.MinVersion != "1.2" && tlsConf.MinVersion != "1.3" {
+		return errors.New("the minimum TLS version must be 1.2 or 1.3")
+	}
+	switch conf.Tracing.Exporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		return errors.New("the tracing exporter must be none, stdout or otlp")
+	}
+	if conf.Tracing.SampleRatio < 0 || conf.Tracing.SampleRatio > 1 {
+		return errors.New("the tracing sample ratio must be between 0 and 1")
+	}
+	return nil
+}
+
 func readAndUnmarshal() error {
 	err := viper.ReadInConfig()
 	if err != nil {
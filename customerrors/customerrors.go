@@ -1,5 +1,7 @@
 package customerrors
 
+import "strings"
+
 const (
 	NOT_CSRF_TOKEN               = "NOT_CSRF_TOKEN"
 	NOT_JWT_TOKEN                = "NOT_JWT_TOKEN"
@@ -14,8 +16,93 @@ const (
 	ALREADY_USED_VALIDATION_CODE = "ALREADY_USED_VALIDATION_CODE"
 	NOT_ALLOWED_RESOURCE         = "NOT_ALLOWED_RESOURCE"
 	NOT_ENOUGH_WORDS_FOR_QUIZ    = "NOT_ENOUGH_WORDS_FOR_QUIZ"
+	UNKNOWN_OIDC_PROVIDER        = "UNKNOWN_OIDC_PROVIDER"
+	INVALID_OIDC_STATE           = "INVALID_OIDC_STATE"
+	OIDC_AUTHENTICATION_FAILED   = "OIDC_AUTHENTICATION_FAILED"
+	NOT_VERIFIED_OIDC_EMAIL      = "NOT_VERIFIED_OIDC_EMAIL"
+	EXPIRED_DOWNLOAD_TOKEN       = "EXPIRED_DOWNLOAD_TOKEN"
+	ADMIN_REQUIRED               = "ADMIN_REQUIRED"
+	DISABLED_ACCOUNT             = "DISABLED_ACCOUNT"
+	INVALID_ROLE                 = "INVALID_ROLE"
+	ADMIN_ALREADY_EXISTS         = "ADMIN_ALREADY_EXISTS"
+	LANGUAGE_ALREADY_EXISTS      = "LANGUAGE_ALREADY_EXISTS"
+	INVALID_LANGUAGE_CODE        = "INVALID_LANGUAGE_CODE"
+	LANGUAGE_NOT_FOUND           = "LANGUAGE_NOT_FOUND"
+	INVALID_MAGIC_LINK           = "INVALID_MAGIC_LINK"
+	WEAK_PASSWORD                = "WEAK_PASSWORD"
+	PASSWORD_TOO_SHORT           = "PASSWORD_TOO_SHORT"
+	PASSWORD_NO_LOWERCASE        = "PASSWORD_NO_LOWERCASE"
+	PASSWORD_NO_UPPERCASE        = "PASSWORD_NO_UPPERCASE"
+	PASSWORD_NO_DIGIT            = "PASSWORD_NO_DIGIT"
+	PASSWORD_NO_SYMBOL           = "PASSWORD_NO_SYMBOL"
+	PASSWORD_EQUALS_USERNAME     = "PASSWORD_EQUALS_USERNAME"
+	PASSWORD_EQUALS_EMAIL        = "PASSWORD_EQUALS_EMAIL"
+	PASSWORD_BREACHED            = "PASSWORD_BREACHED"
+	INVALID_TIMEZONE             = "INVALID_TIMEZONE"
+	INVALID_REMINDER_TIME        = "INVALID_REMINDER_TIME"
+	INVALID_UNSUBSCRIBE_TOKEN    = "INVALID_UNSUBSCRIBE_TOKEN"
+	DICTIONARY_UNAVAILABLE       = "DICTIONARY_UNAVAILABLE"
+	INVALID_SYNC_CURSOR          = "INVALID_SYNC_CURSOR"
+	PRECONDITION_REQUIRED        = "PRECONDITION_REQUIRED"
+	STALE_VERSION                = "STALE_VERSION"
+	NOT_READY                    = "NOT_READY"
+	EMPTY_FORM_FIELDS            = "EMPTY_FORM_FIELDS"
+	NOT_FOUND                    = "NOT_FOUND"
+	INTERNAL_ERROR               = "INTERNAL_ERROR"
+	INVALID_REQUEST              = "INVALID_REQUEST"
 )
 
+// Codes are all the error codes the API can answer with, to be published in its specification
+var Codes = []string{
+	NOT_CSRF_TOKEN,
+	NOT_JWT_TOKEN,
+	INVALID_TOKEN,
+	USERNAME_ALREADY_IN_USE,
+	EMAIL_ALREADY_IN_USE,
+	ALREADY_VALIDATED_ACCOUNT,
+	INVALID_CREDENTIALS,
+	NOT_VALIDATED_ACCOUNT,
+	EXPIRED_VALIDATION_CODE,
+	INCORRECT_VALIDATION_CODE,
+	ALREADY_USED_VALIDATION_CODE,
+	NOT_ALLOWED_RESOURCE,
+	NOT_ENOUGH_WORDS_FOR_QUIZ,
+	UNKNOWN_OIDC_PROVIDER,
+	INVALID_OIDC_STATE,
+	OIDC_AUTHENTICATION_FAILED,
+	NOT_VERIFIED_OIDC_EMAIL,
+	EXPIRED_DOWNLOAD_TOKEN,
+	ADMIN_REQUIRED,
+	DISABLED_ACCOUNT,
+	INVALID_ROLE,
+	ADMIN_ALREADY_EXISTS,
+	LANGUAGE_ALREADY_EXISTS,
+	INVALID_LANGUAGE_CODE,
+	LANGUAGE_NOT_FOUND,
+	INVALID_MAGIC_LINK,
+	WEAK_PASSWORD,
+	PASSWORD_TOO_SHORT,
+	PASSWORD_NO_LOWERCASE,
+	PASSWORD_NO_UPPERCASE,
+	PASSWORD_NO_DIGIT,
+	PASSWORD_NO_SYMBOL,
+	PASSWORD_EQUALS_USERNAME,
+	PASSWORD_EQUALS_EMAIL,
+	PASSWORD_BREACHED,
+	INVALID_TIMEZONE,
+	INVALID_REMINDER_TIME,
+	INVALID_UNSUBSCRIBE_TOKEN,
+	DICTIONARY_UNAVAILABLE,
+	INVALID_SYNC_CURSOR,
+	PRECONDITION_REQUIRED,
+	STALE_VERSION,
+	NOT_READY,
+	EMPTY_FORM_FIELDS,
+	NOT_FOUND,
+	INTERNAL_ERROR,
+	INVALID_REQUEST,
+}
+
 type AlreadyUsedValidationCodeError struct{}
 
 func (e AlreadyUsedValidationCodeError) Error() string {
@@ -31,7 +118,7 @@ func (e EmailAlreadyInUseError) Error() string {
 type EmptyFormFieldsError struct{}
 
 func (e EmptyFormFieldsError) Error() string {
-	return "Empty form fields"
+	return "Mandatory fields are empty"
 }
 
 type ExpiredValidationCodeError struct{}
@@ -52,10 +139,16 @@ func (e InvalidCredentialsError) Error() string {
 	return "Invalid credentials"
 }
 
+type IncorrectCurrentPasswordError struct{}
+
+func (e IncorrectCurrentPasswordError) Error() string {
+	return "Incorrect current password"
+}
+
 type NotAllowedResourceError struct{}
 
 func (e NotAllowedResourceError) Error() string {
-	return "Not allowed resource"
+	return "You dont have permissions to access this element"
 }
 
 type NotValidatedAccountError struct{}
@@ -87,7 +180,7 @@ type NotFoundError struct {
 }
 
 func (e NotFoundError) Error() string {
-	return e.Resource + " not found: "
+	return e.Resource + " not found"
 }
 
 type NotEnoughWordsForQuizError struct{}
@@ -95,3 +188,137 @@ type NotEnoughWordsForQuizError struct{}
 func (e NotEnoughWordsForQuizError) Error() string {
 	return "Not enough words to create a quiz"
 }
+
+type UnknownOIDCProviderError struct{}
+
+func (e UnknownOIDCProviderError) Error() string {
+	return "Unknown OIDC provider"
+}
+
+type InvalidOIDCStateError struct{}
+
+func (e InvalidOIDCStateError) Error() string {
+	return "Invalid or expired OIDC state"
+}
+
+type OIDCAuthenticationError struct {
+	Reason string
+}
+
+func (e OIDCAuthenticationError) Error() string {
+	return "OIDC authentication failed: " + e.Reason
+}
+
+type NotVerifiedOIDCEmailError struct{}
+
+func (e NotVerifiedOIDCEmailError) Error() string {
+	return "The identity provider did not return a verified email"
+}
+
+type ExpiredDownloadTokenError struct{}
+
+func (e ExpiredDownloadTokenError) Error() string {
+	return "Expired download token"
+}
+
+type DisabledAccountError struct{}
+
+func (e DisabledAccountError) Error() string {
+	return "Disabled account"
+}
+
+type InvalidRoleError struct{}
+
+func (e InvalidRoleError) Error() string {
+	return "Invalid role"
+}
+
+type AdminAlreadyExistsError struct{}
+
+func (e AdminAlreadyExistsError) Error() string {
+	return "There is already an admin"
+}
+
+type LanguageAlreadyExistsError struct{}
+
+func (e LanguageAlreadyExistsError) Error() string {
+	return "Language already exists"
+}
+
+type InvalidLanguageCodeError struct {
+	Code string
+}
+
+func (e InvalidLanguageCodeError) Error() string {
+	return "Invalid ISO 639-1 language code: " + e.Code
+}
+
+type LanguageNotFoundError struct {
+	Code string
+}
+
+func (e LanguageNotFoundError) Error() string {
+	return "Language not supported: " + e.Code
+}
+
+type InvalidMagicLinkError struct{}
+
+func (e InvalidMagicLinkError) Error() string {
+	return "Invalid or expired login link"
+}
+
+// WeakPasswordError lists the codes of the password policy rules that failed
+type WeakPasswordError struct {
+	Rules []string
+}
+
+func (e WeakPasswordError) Error() string {
+	return "Weak password: " + strings.Join(e.Rules, ", ")
+}
+
+type InvalidTimezoneError struct{}
+
+func (e InvalidTimezoneError) Error() string {
+	return "Invalid IANA timezone"
+}
+
+type InvalidReminderTimeError struct{}
+
+func (e InvalidReminderTimeError) Error() string {
+	return "Invalid reminder time, it must be in the HH:MM format"
+}
+
+type InvalidUnsubscribeTokenError struct{}
+
+func (e InvalidUnsubscribeTokenError) Error() string {
+	return "Invalid unsubscribe link"
+}
+
+type DictionaryUnavailableError struct{}
+
+func (e DictionaryUnavailableError) Error() string {
+	return "The dictionary is not available, try again later"
+}
+
+type InvalidSyncCursorError struct{}
+
+func (e InvalidSyncCursorError) Error() string {
+	return "Invalid sync cursor, sync again from the start"
+}
+
+type PreconditionRequiredError struct{}
+
+func (e PreconditionRequiredError) Error() string {
+	return "The version to change is required, in the HTTP API as the ETag of the If-Match header"
+}
+
+// StaleVersionError is returned when a change is made on an old version of a resource. Current is the resource as
+// it is now, for the client to merge the change
+type StaleVersionError struct {
+	Version int
+	Current interface{}
+}
+
+func (e StaleVersionError) Error() string {
+	return "It was changed since the version of the request"
+}
@@ -3,16 +3,24 @@ package db
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"vocablo/conf"
 	"vocablo/ent"
 	"vocablo/ent/migrate"
+	"vocablo/metrics"
+	"vocablo/tracing"
 
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
 var db *ent.Client
 
+// migrated is set once the schema migration finishes, for the readiness check
+var migrated atomic.Bool
+
 func GetClient() *ent.Client {
 	return db
 }
@@ -33,10 +41,28 @@ func Setup() error {
 	if err != nil {
 		log.Fatal().Msgf("failed creating schema resources: %v", err)
 	}
+	MarkMigrated()
 
 	return nil
 }
 
+// MarkMigrated records that the schema of the client is up to date, for the clients migrated elsewhere
+func MarkMigrated() {
+	migrated.Store(true)
+}
+
+func Migrated() bool {
+	return migrated.Load()
+}
+
+// Close closes the connections of the client, once nothing uses it
+func Close() error {
+	if db == nil {
+		return nil
+	}
+	return db.Close()
+}
+
 func getConnection() string {
 	conf := conf.Get()
 	connString := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", conf.DB.User, conf.DB.Pass,
@@ -46,10 +72,11 @@ func getConnection() string {
 
 func StartConnection() error {
 	connString := getConnection()
-	var err error
-	db, err = ent.Open("postgres", connString)
+	driver, err := entsql.Open(dialect.Postgres, connString)
 	if err != nil {
 		return err
 	}
+	//The driver is wrapped to trace and time the queries
+	db = ent.NewClient(ent.Driver(tracing.NewDriver(metrics.NewDriver(driver))))
 	return nil
 }
@@ -0,0 +1,71 @@
+//go:build ignore
+
+package main
+
+import (
+	"log"
+	"strings"
+	"unicode"
+
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent/entc"
+	"entgo.io/ent/entc/gen"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// removeNodeQueries removes the Relay node and nodes queries, which would load any entity by its ID without the
+// ownership checks of the services
+func removeNodeQueries(_ *gen.Graph, s *ast.Schema) error {
+	query := s.Types["Query"]
+	if query == nil {
+		return nil
+	}
+	var fields ast.FieldList
+	for _, field := range query.Fields {
+		if field.Name != "node" && field.Name != "nodes" {
+			fields = append(fields, field)
+		}
+	}
+	query.Fields = fields
+	if len(fields) == 0 {
+		delete(s.Types, "Query")
+	}
+	return nil
+}
+
+// camelFieldNames keeps the camel case of the ent field names in the GraphQL fields, which entgql lowercases when
+// the Go name of the field is given with @goField
+func camelFieldNames(_ *gen.Graph, s *ast.Schema) error {
+	for _, def := range s.Types {
+		for _, field := range def.Fields {
+			goField := field.Directives.ForName("goField")
+			if goField == nil {
+				continue
+			}
+			name := goField.Arguments.ForName("name")
+			if name == nil || !strings.EqualFold(name.Value.Raw, field.Name) {
+				continue
+			}
+			runes := []rune(name.Value.Raw)
+			runes[0] = unicode.ToLower(runes[0])
+			field.Name = string(runes)
+		}
+	}
+	return nil
+}
+
+func main() {
+	extension, err := entgql.NewExtension(
+		entgql.WithSchemaGenerator(),
+		entgql.WithSchemaPath("graph/ent.graphql"),
+		entgql.WithConfigPath("gqlgen.yml"),
+		entgql.WithSchemaHook(removeNodeQueries, camelFieldNames),
+	)
+	if err != nil {
+		log.Fatalf("Error creating the entgql extension: %v", err)
+	}
+	err = entc.Generate("./schema", &gen.Config{Target: "./ent", Package: "vocablo/ent"}, entc.Extensions(extension))
+	if err != nil {
+		log.Fatalf("Error generating ent: %v", err)
+	}
+}
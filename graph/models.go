@@ -0,0 +1,19 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graph
+
+import (
+	"vocablo/schema"
+
+	"github.com/google/uuid"
+)
+
+// The fields to change of a word of the user, the ones without a value are kept.
+type UpdateUserWordInput struct {
+	ID uuid.UUID `json:"id"`
+	// Version of the word the change was made on. When it isn't the current one, the update fails with the
+	// STALE_VERSION code and the current word in the data of the extensions.
+	Version     int                  `json:"version"`
+	Term        *string              `json:"term,omitempty"`
+	Definitions []*schema.Definition `json:"definitions,omitempty"`
+}
@@ -0,0 +1,4 @@
+package graph
+
+// Resolver resolves the GraphQL API with the services, which check that the data belongs to the user of the context
+type Resolver struct{}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"context"
+	"vocablo/utils"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// NewServer creates the handler of the GraphQL queries, sent with POST
+func NewServer() *handler.Server {
+	server := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{}}))
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.SetErrorPresenter(presentError)
+	return server
+}
+
+// presentError gives the errors of the services the code and message of the REST API, in the extensions of the
+// GraphQL error. The unexpected errors are hidden behind the request ID as well
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		//Errors of the query itself, like a syntax error
+		return gqlErr
+	}
+	res := utils.Error(ctx, gqlErr.Err)
+	gqlErr.Message = *res.Body.ErrorMessage
+	gqlErr.Extensions = map[string]interface{}{"code": *res.Body.ErrorCode}
+	if res.Body.Data != nil {
+		gqlErr.Extensions["data"] = res.Body.Data
+	}
+	if res.Body.RequestId != "" {
+		gqlErr.Extensions["requestId"] = res.Body.RequestId
+	}
+	return gqlErr
+}
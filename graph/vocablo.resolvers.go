@@ -0,0 +1,94 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.55
+
+import (
+	"context"
+	"vocablo/ent"
+	"vocablo/schema"
+	"vocablo/svc"
+	"vocablo/svc/quiz"
+	"vocablo/svc/userword"
+	"vocablo/utils"
+
+	"entgo.io/contrib/entgql"
+	"github.com/google/uuid"
+)
+
+// CreateUserWord is the resolver for the createUserWord field.
+func (r *mutationResolver) CreateUserWord(ctx context.Context, input userword.CreateForm) (*ent.UserWord, error) {
+	return svc.Get().UserWord.Create(ctx, input)
+}
+
+// UpdateUserWord is the resolver for the updateUserWord field.
+func (r *mutationResolver) UpdateUserWord(ctx context.Context, input UpdateUserWordInput) (*ent.UserWord, error) {
+	form := userword.UpdateForm{ID: input.ID.String(), Term: input.Term, Versions: []int{input.Version}}
+	if input.Definitions != nil {
+		definitions := make([]schema.Definition, len(input.Definitions))
+		for i, definition := range input.Definitions {
+			definitions[i] = *definition
+		}
+		form.Definitions = &definitions
+	}
+	return svc.Get().UserWord.Update(ctx, form)
+}
+
+// DeleteUserWord is the resolver for the deleteUserWord field.
+func (r *mutationResolver) DeleteUserWord(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	err := svc.Get().UserWord.Delete(ctx, id.String())
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// CreateQuiz is the resolver for the createQuiz field.
+func (r *mutationResolver) CreateQuiz(ctx context.Context, nQuestions int) (*quiz.Quiz, error) {
+	return svc.Get().Quiz.Create(ctx, quiz.CreateForm{NQuestions: nQuestions})
+}
+
+// AnswerQuiz is the resolver for the answerQuiz field.
+func (r *mutationResolver) AnswerQuiz(ctx context.Context, input quiz.Quiz) (int, error) {
+	return svc.Get().Quiz.Answer(ctx, input)
+}
+
+// Self is the resolver for the self field.
+func (r *queryResolver) Self(ctx context.Context) (*ent.User, error) {
+	return svc.Get().User.Get(ctx, ctx.Value(utils.UserIdKey).(uuid.UUID))
+}
+
+// UserWords is the resolver for the userWords field.
+func (r *queryResolver) UserWords(ctx context.Context, after *entgql.Cursor[uuid.UUID], first *int, before *entgql.Cursor[uuid.UUID], last *int, orderBy *ent.UserWordOrder, term *string, lang *string, learned *bool) (*ent.UserWordConnection, error) {
+	form := userword.SearchForm{Term: term, Lang: lang, Learned: learned}
+	return svc.Get().UserWord.Query(ctx, form).Paginate(ctx, after, first, before, last, ent.WithUserWordOrder(orderBy))
+}
+
+// Progress is the resolver for the progress field.
+func (r *queryResolver) Progress(ctx context.Context) (*schema.UserWordProgress, error) {
+	return svc.Get().UserWord.Progress(ctx)
+}
+
+// SearchWord is the resolver for the searchWord field.
+func (r *queryResolver) SearchWord(ctx context.Context, term string, lang string) ([]*ent.Word, error) {
+	page, err := svc.Get().Word.Search(ctx, lang, term)
+	if err != nil {
+		return nil, err
+	}
+	return page.Content, nil
+}
+
+// Languages is the resolver for the languages field.
+func (r *queryResolver) Languages(ctx context.Context) ([]*ent.Language, error) {
+	return svc.Get().Language.List(ctx)
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
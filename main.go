@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
 	"vocablo/api"
+	"vocablo/cli"
 	"vocablo/conf"
 	"vocablo/db"
 	"vocablo/svc"
 	"vocablo/svc/mail"
+	"vocablo/tracing"
+	"vocablo/utils"
 
 	"github.com/rs/zerolog/log"
 )
@@ -17,11 +24,91 @@ func main() {
 		log.Fatal().Err(err).Msg("Fatal error in configuration setup")
 		return
 	}
+	err = conf.Validate()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid configuration")
+		return
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), conf.Get().Tracing)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Fatal error in tracing setup")
+		return
+	}
+	err = utils.SetupJwtKeys()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Fatal error loading the JWT keys")
+		return
+	}
 	err = db.Setup()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Fatal error in db setup")
 		return
 	}
-	svc.Setup(db.GetClient(), &mail.MailSvcImpl{})
-	api.Start()
+	sender, err := mail.NewSender(conf.Get().Mail)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Fatal error in mail setup")
+		return
+	}
+	err = svc.Setup(db.GetClient(), sender)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Fatal error in services setup")
+		return
+	}
+	err = svc.Get().Language.Seed(context.Background(), conf.Get().Languages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Fatal error seeding the languages")
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		admin, err := cli.RunCreateAdmin(context.Background(), db.GetClient(), os.Args[2:])
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error creating the admin")
+			return
+		}
+		log.Info().Str("username", admin.Username).Msg("Admin created")
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "send-digests" {
+		sent, err := cli.RunSendDigests(context.Background(), db.GetClient(), os.Args[2:])
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error sending the weekly digests")
+			return
+		}
+		log.Info().Int("sent", sent).Msg("Weekly digests sent")
+		return
+	}
+	//The workers are stopped after the server, so the requests being drained can still use them
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	svc.Get().VerificationCode.SchedulePurge(workersCtx, conf.Get().VerificationCodes.PurgeInterval)
+	svc.Get().Mail.ScheduleDelivery(workersCtx, conf.Get().Mail.Outbox.Interval)
+	svc.Get().Notification.ScheduleReminders(workersCtx, conf.Get().Notifications.ReminderInterval)
+	svc.Get().Notification.ScheduleDigests(workersCtx, conf.Get().Notifications.DigestInterval)
+	svc.Get().Sync.SchedulePurge(workersCtx, conf.Get().Sync.PurgeInterval)
+	svc.Get().Export.SchedulePurge(workersCtx, conf.Get().Exports.PurgeInterval)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	exitCode := 0
+	err = api.Start(ctx)
+	stop()
+	if err != nil {
+		log.Error().Err(err).Msg("Error serving the API")
+		exitCode = 1
+	}
+	stopWorkers()
+	if !svc.Wait(conf.Get().Server.ShutdownTimeout) {
+		log.Warn().Msg("The background jobs didn't finish before the shutdown timeout")
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.Get().Server.ShutdownTimeout)
+	err = shutdownTracing(shutdownCtx)
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Msg("Error flushing the traces")
+	}
+	err = db.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("Error closing the db connection")
+		exitCode = 1
+	}
+	log.Info().Msg("Application stopped")
+	os.Exit(exitCode)
 }
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect"
+)
+
+// Driver times the statements run by ent, in and out of transactions
+type Driver struct {
+	dialect.Driver
+}
+
+func NewDriver(driver dialect.Driver) *Driver {
+	return &Driver{Driver: driver}
+}
+
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+	defer observeQuery(query, time.Now())
+	return d.Driver.Exec(ctx, query, args, v)
+}
+
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+	defer observeQuery(query, time.Now())
+	return d.Driver.Query(ctx, query, args, v)
+}
+
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// BeginTx is used by ent when the transaction has options, if the driver supports them
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	beginner, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return d.Tx(ctx)
+	}
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+type Tx struct {
+	dialect.Tx
+}
+
+func (t *Tx) Exec(ctx context.Context, query string, args, v any) error {
+	defer observeQuery(query, time.Now())
+	return t.Tx.Exec(ctx, query, args, v)
+}
+
+func (t *Tx) Query(ctx context.Context, query string, args, v any) error {
+	defer observeQuery(query, time.Now())
+	return t.Tx.Query(ctx, query, args, v)
+}
+
+// StatementKind returns the first keyword of the query, lowercased, so the label has a bounded set of values
+func StatementKind(query string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	switch keyword = strings.ToLower(keyword); keyword {
+	case "select", "insert", "update", "delete":
+		return keyword
+	default:
+		return "other"
+	}
+}
+
+func observeQuery(query string, start time.Time) {
+	DBQueryDuration.WithLabelValues(StatementKind(query)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "vocablo"
+
+// Registry has the metrics of the application, besides the Go runtime and process ones
+var Registry = prometheus.NewRegistry()
+
+var (
+	HttpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "http_requests_total", Help: "HTTP requests by route, method and status code.",
+	}, []string{"method", "route", "status"})
+	HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "http_request_duration_seconds", Help: "Latency of the HTTP requests by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "db_query_duration_seconds", Help: "Latency of the database statements by kind.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"statement"})
+	DictionaryLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "dictionary_lookups_total",
+		Help: "Word searches answered from the database (hit) or the external dictionary (miss).",
+	}, []string{"result"})
+	DictionaryRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "dictionary_request_duration_seconds", Help: "Latency of the external dictionary API by status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"status"})
+	QuizzesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace, Name: "quizzes_created_total", Help: "Quizzes created.",
+	})
+	QuizzesAnswered = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace, Name: "quizzes_answered_total", Help: "Quizzes answered.",
+	})
+	SignUps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "signups_total", Help: "Accounts registered by method.",
+	}, []string{"method"})
+	LoginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "login_failures_total", Help: "Failed password logins by reason.",
+	}, []string{"reason"})
+)
+
+// Values of the labels
+const (
+	HIT_RESULT  = "hit"
+	MISS_RESULT = "miss"
+	// Route of the requests that don't match any, to bound the cardinality
+	UNMATCHED_ROUTE = "unmatched"
+	// Status of the upstream requests that got no response
+	ERROR_STATUS = "error"
+	// Sign-up methods
+	PASSWORD_METHOD = "password"
+	OIDC_METHOD     = "oidc"
+	// Login failure reasons
+	UNKNOWN_USER_REASON       = "unknown_user"
+	INCORRECT_PASSWORD_REASON = "incorrect_password"
+	DISABLED_ACCOUNT_REASON   = "disabled_account"
+	NOT_VALIDATED_REASON      = "not_validated"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HttpRequests, HttpRequestDuration, DBQueryDuration, DictionaryLookups, DictionaryRequestDuration,
+		QuizzesCreated, QuizzesAnswered, SignUps, LoginFailures,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	"vocablo/customerrors"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Admin must be used after Authentication, as it relies on the role it adds to the context
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Request.Context().Value(utils.UserRoleKey).(string)
+		if role != utils.ADMIN_ROLE {
+			res := utils.ErrorResponse(http.StatusForbidden, utils.GetStringPointer("Admin role required"), utils.GetStringPointer(customerrors.ADMIN_REQUIRED))
+			c.AbortWithStatusJSON(res.Status, res.Body)
+			return
+		}
+		c.Next()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"vocablo/customerrors"
+	"vocablo/svc"
 	"vocablo/utils"
 
 	"github.com/gin-gonic/gin"
@@ -32,8 +33,21 @@ func Authentication() gin.HandlerFunc {
 			c.AbortWithStatusJSON(res.Status, res.Body)
 			return
 		}
+		//The session is revoked when the user changes the credentials or deletes the account
+		sessionUser, err := svc.Get().User.Get(c.Request.Context(), tokenClaims.Id)
+		if _, notFound := err.(customerrors.NotFoundError); err != nil && !notFound {
+			res := utils.Error(c.Request.Context(), err)
+			c.AbortWithStatusJSON(res.Status, res.Body)
+			return
+		}
+		if sessionUser == nil || sessionUser.SessionVersion != tokenClaims.SessionVersion {
+			res := utils.ErrorResponse(http.StatusUnauthorized, utils.GetStringPointer("Revoked session"), utils.GetStringPointer(customerrors.INVALID_TOKEN))
+			c.AbortWithStatusJSON(res.Status, res.Body)
+			return
+		}
 		newCtx := c.Request.Context()
 		newCtx = context.WithValue(newCtx, utils.UserIdKey, tokenClaims.Id)
+		newCtx = context.WithValue(newCtx, utils.UserRoleKey, sessionUser.Role)
 		c.Request = c.Request.WithContext(newCtx)
 
 		c.Next()
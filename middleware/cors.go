@@ -17,8 +17,8 @@ func Cors() gin.HandlerFunc {
 		}
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Header("Access-Control-Allow-Headers", "Accept, Content-Type, credentials, Content-Length, Accept-Encoding, X-API-CSRF, Authorization")
-		c.Header("Access-Control-Expose-Headers", "Set-Cookie")
+		c.Header("Access-Control-Allow-Headers", "Accept, Content-Type, credentials, Content-Length, Accept-Encoding, X-API-CSRF, Authorization, X-Request-ID, If-Match, If-None-Match")
+		c.Header("Access-Control-Expose-Headers", "Set-Cookie, X-Request-ID, ETag")
 		if c.Request.Method == http.MethodOptions {
 			c.AbortWithStatus(http.StatusNoContent)
 			return
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"time"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger logs every request once answered, with its ID and the context of the request so the trace IDs are added
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			path += "?" + c.Request.URL.RawQuery
+		}
+		c.Next()
+		status := c.Writer.Status()
+		var event *zerolog.Event
+		switch {
+		case status >= 500:
+			event = log.Error()
+		case status >= 400:
+			event = log.Warn()
+		default:
+			event = log.Info()
+		}
+		msg := c.Errors.String()
+		if msg == "" {
+			msg = "Request"
+		}
+		requestId, _ := c.Request.Context().Value(utils.RequestIdKey).(string)
+		event.Ctx(c.Request.Context()).Str("request_id", requestId).Str("method", c.Request.Method).Str("path", path).
+			Dur("resp_time", time.Since(start)).Int("status", status).Str("client_ip", c.ClientIP()).Msg(msg)
+	}
+}
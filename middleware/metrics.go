@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+	"vocablo/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts and times the requests by route template, so the path parameters don't become labels
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = metrics.UNMATCHED_ROUTE
+		}
+		metrics.HttpRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HttpRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
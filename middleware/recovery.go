@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"fmt"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery answers the panics of the handlers as unexpected errors, so they are logged with the request ID
+// and the client gets the generic message
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		res := utils.Error(c.Request.Context(), fmt.Errorf("panic: %v", recovered))
+		c.AbortWithStatusJSON(res.Status, res.Body)
+	})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"vocablo/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIdHeader = "X-Request-ID"
+
+// RequestInfo adds to the context the request ID, reusing the one sent by the client or proxy if present,
+// and the client IP and user agent, so the services can record them
+func RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.Request.Header.Get(requestIdHeader)
+		if requestId == "" || len(requestId) > 128 {
+			requestId = uuid.NewString()
+		}
+		c.Header(requestIdHeader, requestId)
+		newCtx := c.Request.Context()
+		newCtx = context.WithValue(newCtx, utils.RequestIdKey, requestId)
+		newCtx = context.WithValue(newCtx, utils.ClientIpKey, c.ClientIP())
+		newCtx = context.WithValue(newCtx, utils.UserAgentKey, c.Request.UserAgent())
+		c.Request = c.Request.WithContext(newCtx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,534 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: vocablo/v1/quiz.proto
+
+package vocablov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QuizQuestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// UUID of the word of the user asked
+	UserWordId       string   `protobuf:"bytes,1,opt,name=user_word_id,json=userWordId,proto3" json:"user_word_id,omitempty"`
+	Question         string   `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	Options          []string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"`
+	CorrectOptionPos int32    `protobuf:"varint,4,opt,name=correct_option_pos,json=correctOptionPos,proto3" json:"correct_option_pos,omitempty"`
+	AnswerPos        *int32   `protobuf:"varint,5,opt,name=answer_pos,json=answerPos,proto3,oneof" json:"answer_pos,omitempty"`
+}
+
+func (x *QuizQuestion) Reset() {
+	*x = QuizQuestion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_quiz_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuizQuestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizQuestion) ProtoMessage() {}
+
+func (x *QuizQuestion) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_quiz_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizQuestion.ProtoReflect.Descriptor instead.
+func (*QuizQuestion) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_quiz_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuizQuestion) GetUserWordId() string {
+	if x != nil {
+		return x.UserWordId
+	}
+	return ""
+}
+
+func (x *QuizQuestion) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *QuizQuestion) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *QuizQuestion) GetCorrectOptionPos() int32 {
+	if x != nil {
+		return x.CorrectOptionPos
+	}
+	return 0
+}
+
+func (x *QuizQuestion) GetAnswerPos() int32 {
+	if x != nil && x.AnswerPos != nil {
+		return *x.AnswerPos
+	}
+	return 0
+}
+
+type Quiz struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Questions []*QuizQuestion `protobuf:"bytes,1,rep,name=questions,proto3" json:"questions,omitempty"`
+	Score     int32           `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (x *Quiz) Reset() {
+	*x = Quiz{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_quiz_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Quiz) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quiz) ProtoMessage() {}
+
+func (x *Quiz) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_quiz_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quiz.ProtoReflect.Descriptor instead.
+func (*Quiz) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_quiz_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Quiz) GetQuestions() []*QuizQuestion {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+func (x *Quiz) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type CreateQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 10 by default
+	NQuestions int32 `protobuf:"varint,1,opt,name=n_questions,json=nQuestions,proto3" json:"n_questions,omitempty"`
+}
+
+func (x *CreateQuizRequest) Reset() {
+	*x = CreateQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_quiz_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQuizRequest) ProtoMessage() {}
+
+func (x *CreateQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_quiz_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQuizRequest.ProtoReflect.Descriptor instead.
+func (*CreateQuizRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_quiz_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateQuizRequest) GetNQuestions() int32 {
+	if x != nil {
+		return x.NQuestions
+	}
+	return 0
+}
+
+type CreateQuizResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Quiz *Quiz `protobuf:"bytes,1,opt,name=quiz,proto3" json:"quiz,omitempty"`
+}
+
+func (x *CreateQuizResponse) Reset() {
+	*x = CreateQuizResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_quiz_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateQuizResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQuizResponse) ProtoMessage() {}
+
+func (x *CreateQuizResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_quiz_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQuizResponse.ProtoReflect.Descriptor instead.
+func (*CreateQuizResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_quiz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateQuizResponse) GetQuiz() *Quiz {
+	if x != nil {
+		return x.Quiz
+	}
+	return nil
+}
+
+type AnswerQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Quiz *Quiz `protobuf:"bytes,1,opt,name=quiz,proto3" json:"quiz,omitempty"`
+}
+
+func (x *AnswerQuizRequest) Reset() {
+	*x = AnswerQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_quiz_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnswerQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnswerQuizRequest) ProtoMessage() {}
+
+func (x *AnswerQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_quiz_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnswerQuizRequest.ProtoReflect.Descriptor instead.
+func (*AnswerQuizRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_quiz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AnswerQuizRequest) GetQuiz() *Quiz {
+	if x != nil {
+		return x.Quiz
+	}
+	return nil
+}
+
+type AnswerQuizResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Score int32 `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (x *AnswerQuizResponse) Reset() {
+	*x = AnswerQuizResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_quiz_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnswerQuizResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnswerQuizResponse) ProtoMessage() {}
+
+func (x *AnswerQuizResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_quiz_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnswerQuizResponse.ProtoReflect.Descriptor instead.
+func (*AnswerQuizResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_quiz_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AnswerQuizResponse) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+var File_vocablo_v1_quiz_proto protoreflect.FileDescriptor
+
+var file_vocablo_v1_quiz_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x69,
+	0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f,
+	0x2e, 0x76, 0x31, 0x22, 0xc7, 0x01, 0x0a, 0x0c, 0x51, 0x75, 0x69, 0x7a, 0x51, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x77, 0x6f, 0x72,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x72,
+	0x57, 0x6f, 0x72, 0x64, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x12,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70,
+	0x6f, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63,
+	0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x73, 0x12, 0x22, 0x0a, 0x0a, 0x61, 0x6e,
+	0x73, 0x77, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00,
+	0x52, 0x09, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x50, 0x6f, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0d,
+	0x0a, 0x0b, 0x5f, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x73, 0x22, 0x54, 0x0a,
+	0x04, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x36, 0x0a, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62,
+	0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x69, 0x7a, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x22, 0x34, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69,
+	0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x5f, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6e,
+	0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3a, 0x0a, 0x12, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x24, 0x0a, 0x04, 0x71, 0x75, 0x69, 0x7a, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x69, 0x7a, 0x52,
+	0x04, 0x71, 0x75, 0x69, 0x7a, 0x22, 0x39, 0x0a, 0x11, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x51,
+	0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x71, 0x75,
+	0x69, 0x7a, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62,
+	0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x04, 0x71, 0x75, 0x69, 0x7a,
+	0x22, 0x2a, 0x0a, 0x12, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x32, 0xa7, 0x01, 0x0a,
+	0x0b, 0x51, 0x75, 0x69, 0x7a, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0a,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1d, 0x2e, 0x76, 0x6f, 0x63,
+	0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75,
+	0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x76, 0x6f, 0x63, 0x61,
+	0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69,
+	0x7a, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0a, 0x41, 0x6e, 0x73,
+	0x77, 0x65, 0x72, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1d, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x51, 0x75, 0x69, 0x7a, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c,
+	0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f,
+	0x76, 0x31, 0x3b, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_vocablo_v1_quiz_proto_rawDescOnce sync.Once
+	file_vocablo_v1_quiz_proto_rawDescData = file_vocablo_v1_quiz_proto_rawDesc
+)
+
+func file_vocablo_v1_quiz_proto_rawDescGZIP() []byte {
+	file_vocablo_v1_quiz_proto_rawDescOnce.Do(func() {
+		file_vocablo_v1_quiz_proto_rawDescData = protoimpl.X.CompressGZIP(file_vocablo_v1_quiz_proto_rawDescData)
+	})
+	return file_vocablo_v1_quiz_proto_rawDescData
+}
+
+var file_vocablo_v1_quiz_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_vocablo_v1_quiz_proto_goTypes = []any{
+	(*QuizQuestion)(nil),       // 0: vocablo.v1.QuizQuestion
+	(*Quiz)(nil),               // 1: vocablo.v1.Quiz
+	(*CreateQuizRequest)(nil),  // 2: vocablo.v1.CreateQuizRequest
+	(*CreateQuizResponse)(nil), // 3: vocablo.v1.CreateQuizResponse
+	(*AnswerQuizRequest)(nil),  // 4: vocablo.v1.AnswerQuizRequest
+	(*AnswerQuizResponse)(nil), // 5: vocablo.v1.AnswerQuizResponse
+}
+var file_vocablo_v1_quiz_proto_depIdxs = []int32{
+	0, // 0: vocablo.v1.Quiz.questions:type_name -> vocablo.v1.QuizQuestion
+	1, // 1: vocablo.v1.CreateQuizResponse.quiz:type_name -> vocablo.v1.Quiz
+	1, // 2: vocablo.v1.AnswerQuizRequest.quiz:type_name -> vocablo.v1.Quiz
+	2, // 3: vocablo.v1.QuizService.CreateQuiz:input_type -> vocablo.v1.CreateQuizRequest
+	4, // 4: vocablo.v1.QuizService.AnswerQuiz:input_type -> vocablo.v1.AnswerQuizRequest
+	3, // 5: vocablo.v1.QuizService.CreateQuiz:output_type -> vocablo.v1.CreateQuizResponse
+	5, // 6: vocablo.v1.QuizService.AnswerQuiz:output_type -> vocablo.v1.AnswerQuizResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_vocablo_v1_quiz_proto_init() }
+func file_vocablo_v1_quiz_proto_init() {
+	if File_vocablo_v1_quiz_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_vocablo_v1_quiz_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*QuizQuestion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_quiz_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Quiz); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_quiz_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_quiz_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateQuizResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_quiz_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*AnswerQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_quiz_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*AnswerQuizResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_vocablo_v1_quiz_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vocablo_v1_quiz_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vocablo_v1_quiz_proto_goTypes,
+		DependencyIndexes: file_vocablo_v1_quiz_proto_depIdxs,
+		MessageInfos:      file_vocablo_v1_quiz_proto_msgTypes,
+	}.Build()
+	File_vocablo_v1_quiz_proto = out.File
+	file_vocablo_v1_quiz_proto_rawDesc = nil
+	file_vocablo_v1_quiz_proto_goTypes = nil
+	file_vocablo_v1_quiz_proto_depIdxs = nil
+}
@@ -0,0 +1,154 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: vocablo/v1/quiz.proto
+
+package vocablov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	QuizService_CreateQuiz_FullMethodName = "/vocablo.v1.QuizService/CreateQuiz"
+	QuizService_AnswerQuiz_FullMethodName = "/vocablo.v1.QuizService/AnswerQuiz"
+)
+
+// QuizServiceClient is the client API for QuizService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// QuizService creates quizzes with the words of the user of the token
+type QuizServiceClient interface {
+	CreateQuiz(ctx context.Context, in *CreateQuizRequest, opts ...grpc.CallOption) (*CreateQuizResponse, error)
+	// Scores a quiz with the answers filled, adding progress to the words answered correctly
+	AnswerQuiz(ctx context.Context, in *AnswerQuizRequest, opts ...grpc.CallOption) (*AnswerQuizResponse, error)
+}
+
+type quizServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQuizServiceClient(cc grpc.ClientConnInterface) QuizServiceClient {
+	return &quizServiceClient{cc}
+}
+
+func (c *quizServiceClient) CreateQuiz(ctx context.Context, in *CreateQuizRequest, opts ...grpc.CallOption) (*CreateQuizResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateQuizResponse)
+	err := c.cc.Invoke(ctx, QuizService_CreateQuiz_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) AnswerQuiz(ctx context.Context, in *AnswerQuizRequest, opts ...grpc.CallOption) (*AnswerQuizResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnswerQuizResponse)
+	err := c.cc.Invoke(ctx, QuizService_AnswerQuiz_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QuizServiceServer is the server API for QuizService service.
+// All implementations must embed UnimplementedQuizServiceServer
+// for forward compatibility
+//
+// QuizService creates quizzes with the words of the user of the token
+type QuizServiceServer interface {
+	CreateQuiz(context.Context, *CreateQuizRequest) (*CreateQuizResponse, error)
+	// Scores a quiz with the answers filled, adding progress to the words answered correctly
+	AnswerQuiz(context.Context, *AnswerQuizRequest) (*AnswerQuizResponse, error)
+	mustEmbedUnimplementedQuizServiceServer()
+}
+
+// UnimplementedQuizServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedQuizServiceServer struct {
+}
+
+func (UnimplementedQuizServiceServer) CreateQuiz(context.Context, *CreateQuizRequest) (*CreateQuizResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateQuiz not implemented")
+}
+func (UnimplementedQuizServiceServer) AnswerQuiz(context.Context, *AnswerQuizRequest) (*AnswerQuizResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnswerQuiz not implemented")
+}
+func (UnimplementedQuizServiceServer) mustEmbedUnimplementedQuizServiceServer() {}
+
+// UnsafeQuizServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QuizServiceServer will
+// result in compilation errors.
+type UnsafeQuizServiceServer interface {
+	mustEmbedUnimplementedQuizServiceServer()
+}
+
+func RegisterQuizServiceServer(s grpc.ServiceRegistrar, srv QuizServiceServer) {
+	s.RegisterService(&QuizService_ServiceDesc, srv)
+}
+
+func _QuizService_CreateQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).CreateQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_CreateQuiz_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).CreateQuiz(ctx, req.(*CreateQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_AnswerQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnswerQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).AnswerQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_AnswerQuiz_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).AnswerQuiz(ctx, req.(*AnswerQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// QuizService_ServiceDesc is the grpc.ServiceDesc for QuizService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QuizService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vocablo.v1.QuizService",
+	HandlerType: (*QuizServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateQuiz",
+			Handler:    _QuizService_CreateQuiz_Handler,
+		},
+		{
+			MethodName: "AnswerQuiz",
+			Handler:    _QuizService_AnswerQuiz_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vocablo/v1/quiz.proto",
+}
@@ -0,0 +1,1079 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: vocablo/v1/userword.proto
+
+package vocablov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateUserWordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Term string `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	// ISO 639-1 code of the language
+	Lang        string        `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	Definitions []*Definition `protobuf:"bytes,3,rep,name=definitions,proto3" json:"definitions,omitempty"`
+}
+
+func (x *CreateUserWordRequest) Reset() {
+	*x = CreateUserWordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateUserWordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserWordRequest) ProtoMessage() {}
+
+func (x *CreateUserWordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserWordRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserWordRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateUserWordRequest) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *CreateUserWordRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *CreateUserWordRequest) GetDefinitions() []*Definition {
+	if x != nil {
+		return x.Definitions
+	}
+	return nil
+}
+
+type CreateUserWordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserWord *UserWord `protobuf:"bytes,1,opt,name=user_word,json=userWord,proto3" json:"user_word,omitempty"`
+}
+
+func (x *CreateUserWordResponse) Reset() {
+	*x = CreateUserWordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateUserWordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserWordResponse) ProtoMessage() {}
+
+func (x *CreateUserWordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserWordResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserWordResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateUserWordResponse) GetUserWord() *UserWord {
+	if x != nil {
+		return x.UserWord
+	}
+	return nil
+}
+
+type UpdateUserWordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Term *string `protobuf:"bytes,2,opt,name=term,proto3,oneof" json:"term,omitempty"`
+	// The definitions are only replaced when update_definitions is set, so they can't be emptied by mistake
+	Definitions       []*Definition `protobuf:"bytes,3,rep,name=definitions,proto3" json:"definitions,omitempty"`
+	UpdateDefinitions bool          `protobuf:"varint,4,opt,name=update_definitions,json=updateDefinitions,proto3" json:"update_definitions,omitempty"`
+	// Version of the word the change was made on, required. When it isn't the current one, the update fails with
+	// FAILED_PRECONDITION and the STALE_VERSION reason, whose metadata has the current version
+	ExpectedVersion *int32 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+}
+
+func (x *UpdateUserWordRequest) Reset() {
+	*x = UpdateUserWordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserWordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserWordRequest) ProtoMessage() {}
+
+func (x *UpdateUserWordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserWordRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserWordRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateUserWordRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateUserWordRequest) GetTerm() string {
+	if x != nil && x.Term != nil {
+		return *x.Term
+	}
+	return ""
+}
+
+func (x *UpdateUserWordRequest) GetDefinitions() []*Definition {
+	if x != nil {
+		return x.Definitions
+	}
+	return nil
+}
+
+func (x *UpdateUserWordRequest) GetUpdateDefinitions() bool {
+	if x != nil {
+		return x.UpdateDefinitions
+	}
+	return false
+}
+
+func (x *UpdateUserWordRequest) GetExpectedVersion() int32 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateUserWordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserWord *UserWord `protobuf:"bytes,1,opt,name=user_word,json=userWord,proto3" json:"user_word,omitempty"`
+}
+
+func (x *UpdateUserWordResponse) Reset() {
+	*x = UpdateUserWordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserWordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserWordResponse) ProtoMessage() {}
+
+func (x *UpdateUserWordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserWordResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserWordResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateUserWordResponse) GetUserWord() *UserWord {
+	if x != nil {
+		return x.UserWord
+	}
+	return nil
+}
+
+type GetUserWordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetUserWordRequest) Reset() {
+	*x = GetUserWordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserWordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserWordRequest) ProtoMessage() {}
+
+func (x *GetUserWordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserWordRequest.ProtoReflect.Descriptor instead.
+func (*GetUserWordRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserWordRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetUserWordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserWord *UserWord `protobuf:"bytes,1,opt,name=user_word,json=userWord,proto3" json:"user_word,omitempty"`
+}
+
+func (x *GetUserWordResponse) Reset() {
+	*x = GetUserWordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserWordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserWordResponse) ProtoMessage() {}
+
+func (x *GetUserWordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserWordResponse.ProtoReflect.Descriptor instead.
+func (*GetUserWordResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUserWordResponse) GetUserWord() *UserWord {
+	if x != nil {
+		return x.UserWord
+	}
+	return nil
+}
+
+type DeleteUserWordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteUserWordRequest) Reset() {
+	*x = DeleteUserWordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteUserWordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserWordRequest) ProtoMessage() {}
+
+func (x *DeleteUserWordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserWordRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserWordRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteUserWordRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteUserWordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteUserWordResponse) Reset() {
+	*x = DeleteUserWordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteUserWordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserWordResponse) ProtoMessage() {}
+
+func (x *DeleteUserWordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserWordResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserWordResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{7}
+}
+
+type SearchUserWordsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Part of the term, ignoring the case
+	Term    *string `protobuf:"bytes,1,opt,name=term,proto3,oneof" json:"term,omitempty"`
+	Lang    *string `protobuf:"bytes,2,opt,name=lang,proto3,oneof" json:"lang,omitempty"`
+	Learned *bool   `protobuf:"varint,3,opt,name=learned,proto3,oneof" json:"learned,omitempty"`
+	// creationDate, term or learningProgress
+	OrderBy *string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3,oneof" json:"order_by,omitempty"`
+	// asc or desc, desc by default
+	OrderDir *string `protobuf:"bytes,5,opt,name=order_dir,json=orderDir,proto3,oneof" json:"order_dir,omitempty"`
+	Page     int32   `protobuf:"varint,6,opt,name=page,proto3" json:"page,omitempty"`
+	// 10 by default
+	PageSize int32 `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Only counts the words, without returning them
+	Count bool `protobuf:"varint,8,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *SearchUserWordsRequest) Reset() {
+	*x = SearchUserWordsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchUserWordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUserWordsRequest) ProtoMessage() {}
+
+func (x *SearchUserWordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUserWordsRequest.ProtoReflect.Descriptor instead.
+func (*SearchUserWordsRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchUserWordsRequest) GetTerm() string {
+	if x != nil && x.Term != nil {
+		return *x.Term
+	}
+	return ""
+}
+
+func (x *SearchUserWordsRequest) GetLang() string {
+	if x != nil && x.Lang != nil {
+		return *x.Lang
+	}
+	return ""
+}
+
+func (x *SearchUserWordsRequest) GetLearned() bool {
+	if x != nil && x.Learned != nil {
+		return *x.Learned
+	}
+	return false
+}
+
+func (x *SearchUserWordsRequest) GetOrderBy() string {
+	if x != nil && x.OrderBy != nil {
+		return *x.OrderBy
+	}
+	return ""
+}
+
+func (x *SearchUserWordsRequest) GetOrderDir() string {
+	if x != nil && x.OrderDir != nil {
+		return *x.OrderDir
+	}
+	return ""
+}
+
+func (x *SearchUserWordsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SearchUserWordsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchUserWordsRequest) GetCount() bool {
+	if x != nil {
+		return x.Count
+	}
+	return false
+}
+
+type SearchUserWordsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserWords  []*UserWord `protobuf:"bytes,1,rep,name=user_words,json=userWords,proto3" json:"user_words,omitempty"`
+	PageNumber int32       `protobuf:"varint,2,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
+	HasNext    bool        `protobuf:"varint,3,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	NElements  int32       `protobuf:"varint,4,opt,name=n_elements,json=nElements,proto3" json:"n_elements,omitempty"`
+}
+
+func (x *SearchUserWordsResponse) Reset() {
+	*x = SearchUserWordsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchUserWordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUserWordsResponse) ProtoMessage() {}
+
+func (x *SearchUserWordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUserWordsResponse.ProtoReflect.Descriptor instead.
+func (*SearchUserWordsResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchUserWordsResponse) GetUserWords() []*UserWord {
+	if x != nil {
+		return x.UserWords
+	}
+	return nil
+}
+
+func (x *SearchUserWordsResponse) GetPageNumber() int32 {
+	if x != nil {
+		return x.PageNumber
+	}
+	return 0
+}
+
+func (x *SearchUserWordsResponse) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *SearchUserWordsResponse) GetNElements() int32 {
+	if x != nil {
+		return x.NElements
+	}
+	return 0
+}
+
+type GetProgressRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetProgressRequest) Reset() {
+	*x = GetProgressRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProgressRequest) ProtoMessage() {}
+
+func (x *GetProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProgressRequest.ProtoReflect.Descriptor instead.
+func (*GetProgressRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{10}
+}
+
+type GetProgressResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalWords     int32 `protobuf:"varint,1,opt,name=total_words,json=totalWords,proto3" json:"total_words,omitempty"`
+	LearnedWords   int32 `protobuf:"varint,2,opt,name=learned_words,json=learnedWords,proto3" json:"learned_words,omitempty"`
+	UnlearnedWords int32 `protobuf:"varint,3,opt,name=unlearned_words,json=unlearnedWords,proto3" json:"unlearned_words,omitempty"`
+}
+
+func (x *GetProgressResponse) Reset() {
+	*x = GetProgressResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_userword_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProgressResponse) ProtoMessage() {}
+
+func (x *GetProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_userword_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProgressResponse.ProtoReflect.Descriptor instead.
+func (*GetProgressResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_userword_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetProgressResponse) GetTotalWords() int32 {
+	if x != nil {
+		return x.TotalWords
+	}
+	return 0
+}
+
+func (x *GetProgressResponse) GetLearnedWords() int32 {
+	if x != nil {
+		return x.LearnedWords
+	}
+	return 0
+}
+
+func (x *GetProgressResponse) GetUnlearnedWords() int32 {
+	if x != nil {
+		return x.UnlearnedWords
+	}
+	return 0
+}
+
+var File_vocablo_v1_userword_proto protoreflect.FileDescriptor
+
+var file_vocablo_v1_userword_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x73, 0x65,
+	0x72, 0x77, 0x6f, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x76, 0x6f, 0x63,
+	0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x1a, 0x16, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f,
+	0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x79, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x12, 0x0a, 0x04,
+	0x6c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x67,
+	0x12, 0x38, 0x0a, 0x0b, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x64,
+	0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x4b, 0x0a, 0x16, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x77, 0x6f, 0x72,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x08, 0x75,
+	0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x22, 0xf7, 0x01, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x0b, 0x64, 0x65,
+	0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x66,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x64,
+	0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x11, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x2e, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52,
+	0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x42, 0x13, 0x0a, 0x11,
+	0x5f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x4b, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57,
+	0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x57, 0x6f, 0x72, 0x64, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x22, 0x24,
+	0x0a, 0x12, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x48, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x57,
+	0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x57, 0x6f, 0x72, 0x64, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x22, 0x27,
+	0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x18, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0xab, 0x02, 0x0a, 0x16, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x55, 0x73, 0x65, 0x72,
+	0x57, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x04,
+	0x74, 0x65, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65,
+	0x72, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x1d,
+	0x0a, 0x07, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x02, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a,
+	0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x03, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a,
+	0x09, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x04, 0x52, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x69, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70,
+	0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x6c, 0x61, 0x6e, 0x67, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6c, 0x65, 0x61,
+	0x72, 0x6e, 0x65, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62,
+	0x79, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x72, 0x22,
+	0xa9, 0x01, 0x0a, 0x17, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f,
+	0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x0a, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x61, 0x73, 0x5f, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x61, 0x73, 0x4e, 0x65, 0x78, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6e, 0x5f, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x6e, 0x45, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x14, 0x0a, 0x12, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x84, 0x01, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x65,
+	0x61, 0x72, 0x6e, 0x65, 0x64, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0c, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x12,
+	0x27, 0x0a, 0x0f, 0x75, 0x6e, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x5f, 0x77, 0x6f, 0x72,
+	0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x75, 0x6e, 0x6c, 0x65, 0x61, 0x72,
+	0x6e, 0x65, 0x64, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x32, 0x98, 0x04, 0x0a, 0x0f, 0x55, 0x73, 0x65,
+	0x72, 0x57, 0x6f, 0x72, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x57, 0x0a, 0x0e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x21,
+	0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x22, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x21, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57,
+	0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x76, 0x6f, 0x63,
+	0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e,
+	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x1e, 0x2e,
+	0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57,
+	0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64,
+	0x12, 0x21, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x0f, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x22, 0x2e, 0x76, 0x6f, 0x63,
+	0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x55, 0x73,
+	0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x55, 0x73, 0x65, 0x72, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x1e, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x76, 0x31, 0x3b,
+	0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_vocablo_v1_userword_proto_rawDescOnce sync.Once
+	file_vocablo_v1_userword_proto_rawDescData = file_vocablo_v1_userword_proto_rawDesc
+)
+
+func file_vocablo_v1_userword_proto_rawDescGZIP() []byte {
+	file_vocablo_v1_userword_proto_rawDescOnce.Do(func() {
+		file_vocablo_v1_userword_proto_rawDescData = protoimpl.X.CompressGZIP(file_vocablo_v1_userword_proto_rawDescData)
+	})
+	return file_vocablo_v1_userword_proto_rawDescData
+}
+
+var file_vocablo_v1_userword_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_vocablo_v1_userword_proto_goTypes = []any{
+	(*CreateUserWordRequest)(nil),   // 0: vocablo.v1.CreateUserWordRequest
+	(*CreateUserWordResponse)(nil),  // 1: vocablo.v1.CreateUserWordResponse
+	(*UpdateUserWordRequest)(nil),   // 2: vocablo.v1.UpdateUserWordRequest
+	(*UpdateUserWordResponse)(nil),  // 3: vocablo.v1.UpdateUserWordResponse
+	(*GetUserWordRequest)(nil),      // 4: vocablo.v1.GetUserWordRequest
+	(*GetUserWordResponse)(nil),     // 5: vocablo.v1.GetUserWordResponse
+	(*DeleteUserWordRequest)(nil),   // 6: vocablo.v1.DeleteUserWordRequest
+	(*DeleteUserWordResponse)(nil),  // 7: vocablo.v1.DeleteUserWordResponse
+	(*SearchUserWordsRequest)(nil),  // 8: vocablo.v1.SearchUserWordsRequest
+	(*SearchUserWordsResponse)(nil), // 9: vocablo.v1.SearchUserWordsResponse
+	(*GetProgressRequest)(nil),      // 10: vocablo.v1.GetProgressRequest
+	(*GetProgressResponse)(nil),     // 11: vocablo.v1.GetProgressResponse
+	(*Definition)(nil),              // 12: vocablo.v1.Definition
+	(*UserWord)(nil),                // 13: vocablo.v1.UserWord
+}
+var file_vocablo_v1_userword_proto_depIdxs = []int32{
+	12, // 0: vocablo.v1.CreateUserWordRequest.definitions:type_name -> vocablo.v1.Definition
+	13, // 1: vocablo.v1.CreateUserWordResponse.user_word:type_name -> vocablo.v1.UserWord
+	12, // 2: vocablo.v1.UpdateUserWordRequest.definitions:type_name -> vocablo.v1.Definition
+	13, // 3: vocablo.v1.UpdateUserWordResponse.user_word:type_name -> vocablo.v1.UserWord
+	13, // 4: vocablo.v1.GetUserWordResponse.user_word:type_name -> vocablo.v1.UserWord
+	13, // 5: vocablo.v1.SearchUserWordsResponse.user_words:type_name -> vocablo.v1.UserWord
+	0,  // 6: vocablo.v1.UserWordService.CreateUserWord:input_type -> vocablo.v1.CreateUserWordRequest
+	2,  // 7: vocablo.v1.UserWordService.UpdateUserWord:input_type -> vocablo.v1.UpdateUserWordRequest
+	4,  // 8: vocablo.v1.UserWordService.GetUserWord:input_type -> vocablo.v1.GetUserWordRequest
+	6,  // 9: vocablo.v1.UserWordService.DeleteUserWord:input_type -> vocablo.v1.DeleteUserWordRequest
+	8,  // 10: vocablo.v1.UserWordService.SearchUserWords:input_type -> vocablo.v1.SearchUserWordsRequest
+	10, // 11: vocablo.v1.UserWordService.GetProgress:input_type -> vocablo.v1.GetProgressRequest
+	1,  // 12: vocablo.v1.UserWordService.CreateUserWord:output_type -> vocablo.v1.CreateUserWordResponse
+	3,  // 13: vocablo.v1.UserWordService.UpdateUserWord:output_type -> vocablo.v1.UpdateUserWordResponse
+	5,  // 14: vocablo.v1.UserWordService.GetUserWord:output_type -> vocablo.v1.GetUserWordResponse
+	7,  // 15: vocablo.v1.UserWordService.DeleteUserWord:output_type -> vocablo.v1.DeleteUserWordResponse
+	9,  // 16: vocablo.v1.UserWordService.SearchUserWords:output_type -> vocablo.v1.SearchUserWordsResponse
+	11, // 17: vocablo.v1.UserWordService.GetProgress:output_type -> vocablo.v1.GetProgressResponse
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_vocablo_v1_userword_proto_init() }
+func file_vocablo_v1_userword_proto_init() {
+	if File_vocablo_v1_userword_proto != nil {
+		return
+	}
+	file_vocablo_v1_types_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_vocablo_v1_userword_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateUserWordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateUserWordResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateUserWordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateUserWordResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserWordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserWordResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteUserWordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteUserWordResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchUserWordsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchUserWordsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*GetProgressRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_userword_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*GetProgressResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_vocablo_v1_userword_proto_msgTypes[2].OneofWrappers = []any{}
+	file_vocablo_v1_userword_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vocablo_v1_userword_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vocablo_v1_userword_proto_goTypes,
+		DependencyIndexes: file_vocablo_v1_userword_proto_depIdxs,
+		MessageInfos:      file_vocablo_v1_userword_proto_msgTypes,
+	}.Build()
+	File_vocablo_v1_userword_proto = out.File
+	file_vocablo_v1_userword_proto_rawDesc = nil
+	file_vocablo_v1_userword_proto_goTypes = nil
+	file_vocablo_v1_userword_proto_depIdxs = nil
+}
@@ -0,0 +1,308 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: vocablo/v1/userword.proto
+
+package vocablov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	UserWordService_CreateUserWord_FullMethodName  = "/vocablo.v1.UserWordService/CreateUserWord"
+	UserWordService_UpdateUserWord_FullMethodName  = "/vocablo.v1.UserWordService/UpdateUserWord"
+	UserWordService_GetUserWord_FullMethodName     = "/vocablo.v1.UserWordService/GetUserWord"
+	UserWordService_DeleteUserWord_FullMethodName  = "/vocablo.v1.UserWordService/DeleteUserWord"
+	UserWordService_SearchUserWords_FullMethodName = "/vocablo.v1.UserWordService/SearchUserWords"
+	UserWordService_GetProgress_FullMethodName     = "/vocablo.v1.UserWordService/GetProgress"
+)
+
+// UserWordServiceClient is the client API for UserWordService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserWordService manages the words of the user of the token
+type UserWordServiceClient interface {
+	CreateUserWord(ctx context.Context, in *CreateUserWordRequest, opts ...grpc.CallOption) (*CreateUserWordResponse, error)
+	// Changes the fields that are set, keeping the rest
+	UpdateUserWord(ctx context.Context, in *UpdateUserWordRequest, opts ...grpc.CallOption) (*UpdateUserWordResponse, error)
+	GetUserWord(ctx context.Context, in *GetUserWordRequest, opts ...grpc.CallOption) (*GetUserWordResponse, error)
+	DeleteUserWord(ctx context.Context, in *DeleteUserWordRequest, opts ...grpc.CallOption) (*DeleteUserWordResponse, error)
+	SearchUserWords(ctx context.Context, in *SearchUserWordsRequest, opts ...grpc.CallOption) (*SearchUserWordsResponse, error)
+	// Counts the learned and not learned words
+	GetProgress(ctx context.Context, in *GetProgressRequest, opts ...grpc.CallOption) (*GetProgressResponse, error)
+}
+
+type userWordServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserWordServiceClient(cc grpc.ClientConnInterface) UserWordServiceClient {
+	return &userWordServiceClient{cc}
+}
+
+func (c *userWordServiceClient) CreateUserWord(ctx context.Context, in *CreateUserWordRequest, opts ...grpc.CallOption) (*CreateUserWordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateUserWordResponse)
+	err := c.cc.Invoke(ctx, UserWordService_CreateUserWord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userWordServiceClient) UpdateUserWord(ctx context.Context, in *UpdateUserWordRequest, opts ...grpc.CallOption) (*UpdateUserWordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateUserWordResponse)
+	err := c.cc.Invoke(ctx, UserWordService_UpdateUserWord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userWordServiceClient) GetUserWord(ctx context.Context, in *GetUserWordRequest, opts ...grpc.CallOption) (*GetUserWordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserWordResponse)
+	err := c.cc.Invoke(ctx, UserWordService_GetUserWord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userWordServiceClient) DeleteUserWord(ctx context.Context, in *DeleteUserWordRequest, opts ...grpc.CallOption) (*DeleteUserWordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserWordResponse)
+	err := c.cc.Invoke(ctx, UserWordService_DeleteUserWord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userWordServiceClient) SearchUserWords(ctx context.Context, in *SearchUserWordsRequest, opts ...grpc.CallOption) (*SearchUserWordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchUserWordsResponse)
+	err := c.cc.Invoke(ctx, UserWordService_SearchUserWords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userWordServiceClient) GetProgress(ctx context.Context, in *GetProgressRequest, opts ...grpc.CallOption) (*GetProgressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProgressResponse)
+	err := c.cc.Invoke(ctx, UserWordService_GetProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserWordServiceServer is the server API for UserWordService service.
+// All implementations must embed UnimplementedUserWordServiceServer
+// for forward compatibility
+//
+// UserWordService manages the words of the user of the token
+type UserWordServiceServer interface {
+	CreateUserWord(context.Context, *CreateUserWordRequest) (*CreateUserWordResponse, error)
+	// Changes the fields that are set, keeping the rest
+	UpdateUserWord(context.Context, *UpdateUserWordRequest) (*UpdateUserWordResponse, error)
+	GetUserWord(context.Context, *GetUserWordRequest) (*GetUserWordResponse, error)
+	DeleteUserWord(context.Context, *DeleteUserWordRequest) (*DeleteUserWordResponse, error)
+	SearchUserWords(context.Context, *SearchUserWordsRequest) (*SearchUserWordsResponse, error)
+	// Counts the learned and not learned words
+	GetProgress(context.Context, *GetProgressRequest) (*GetProgressResponse, error)
+	mustEmbedUnimplementedUserWordServiceServer()
+}
+
+// UnimplementedUserWordServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUserWordServiceServer struct {
+}
+
+func (UnimplementedUserWordServiceServer) CreateUserWord(context.Context, *CreateUserWordRequest) (*CreateUserWordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUserWord not implemented")
+}
+func (UnimplementedUserWordServiceServer) UpdateUserWord(context.Context, *UpdateUserWordRequest) (*UpdateUserWordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserWord not implemented")
+}
+func (UnimplementedUserWordServiceServer) GetUserWord(context.Context, *GetUserWordRequest) (*GetUserWordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserWord not implemented")
+}
+func (UnimplementedUserWordServiceServer) DeleteUserWord(context.Context, *DeleteUserWordRequest) (*DeleteUserWordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserWord not implemented")
+}
+func (UnimplementedUserWordServiceServer) SearchUserWords(context.Context, *SearchUserWordsRequest) (*SearchUserWordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUserWords not implemented")
+}
+func (UnimplementedUserWordServiceServer) GetProgress(context.Context, *GetProgressRequest) (*GetProgressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProgress not implemented")
+}
+func (UnimplementedUserWordServiceServer) mustEmbedUnimplementedUserWordServiceServer() {}
+
+// UnsafeUserWordServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserWordServiceServer will
+// result in compilation errors.
+type UnsafeUserWordServiceServer interface {
+	mustEmbedUnimplementedUserWordServiceServer()
+}
+
+func RegisterUserWordServiceServer(s grpc.ServiceRegistrar, srv UserWordServiceServer) {
+	s.RegisterService(&UserWordService_ServiceDesc, srv)
+}
+
+func _UserWordService_CreateUserWord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserWordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserWordServiceServer).CreateUserWord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserWordService_CreateUserWord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserWordServiceServer).CreateUserWord(ctx, req.(*CreateUserWordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserWordService_UpdateUserWord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserWordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserWordServiceServer).UpdateUserWord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserWordService_UpdateUserWord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserWordServiceServer).UpdateUserWord(ctx, req.(*UpdateUserWordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserWordService_GetUserWord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserWordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserWordServiceServer).GetUserWord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserWordService_GetUserWord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserWordServiceServer).GetUserWord(ctx, req.(*GetUserWordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserWordService_DeleteUserWord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserWordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserWordServiceServer).DeleteUserWord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserWordService_DeleteUserWord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserWordServiceServer).DeleteUserWord(ctx, req.(*DeleteUserWordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserWordService_SearchUserWords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUserWordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserWordServiceServer).SearchUserWords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserWordService_SearchUserWords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserWordServiceServer).SearchUserWords(ctx, req.(*SearchUserWordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserWordService_GetProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserWordServiceServer).GetProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserWordService_GetProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserWordServiceServer).GetProgress(ctx, req.(*GetProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserWordService_ServiceDesc is the grpc.ServiceDesc for UserWordService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserWordService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vocablo.v1.UserWordService",
+	HandlerType: (*UserWordServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUserWord",
+			Handler:    _UserWordService_CreateUserWord_Handler,
+		},
+		{
+			MethodName: "UpdateUserWord",
+			Handler:    _UserWordService_UpdateUserWord_Handler,
+		},
+		{
+			MethodName: "GetUserWord",
+			Handler:    _UserWordService_GetUserWord_Handler,
+		},
+		{
+			MethodName: "DeleteUserWord",
+			Handler:    _UserWordService_DeleteUserWord_Handler,
+		},
+		{
+			MethodName: "SearchUserWords",
+			Handler:    _UserWordService_SearchUserWords_Handler,
+		},
+		{
+			MethodName: "GetProgress",
+			Handler:    _UserWordService_GetProgress_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vocablo/v1/userword.proto",
+}
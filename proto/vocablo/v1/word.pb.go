@@ -0,0 +1,357 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: vocablo/v1/word.proto
+
+package vocablov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchWordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Term string `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	// ISO 639-1 code of the language
+	Lang string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+}
+
+func (x *SearchWordRequest) Reset() {
+	*x = SearchWordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_word_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchWordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchWordRequest) ProtoMessage() {}
+
+func (x *SearchWordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_word_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchWordRequest.ProtoReflect.Descriptor instead.
+func (*SearchWordRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_word_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchWordRequest) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *SearchWordRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type SearchWordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Words []*Word `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+}
+
+func (x *SearchWordResponse) Reset() {
+	*x = SearchWordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_word_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchWordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchWordResponse) ProtoMessage() {}
+
+func (x *SearchWordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_word_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchWordResponse.ProtoReflect.Descriptor instead.
+func (*SearchWordResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_word_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchWordResponse) GetWords() []*Word {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+type ListLanguagesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListLanguagesRequest) Reset() {
+	*x = ListLanguagesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_word_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLanguagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLanguagesRequest) ProtoMessage() {}
+
+func (x *ListLanguagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_word_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLanguagesRequest.ProtoReflect.Descriptor instead.
+func (*ListLanguagesRequest) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_word_proto_rawDescGZIP(), []int{2}
+}
+
+type ListLanguagesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Languages []*Language `protobuf:"bytes,1,rep,name=languages,proto3" json:"languages,omitempty"`
+}
+
+func (x *ListLanguagesResponse) Reset() {
+	*x = ListLanguagesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vocablo_v1_word_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLanguagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLanguagesResponse) ProtoMessage() {}
+
+func (x *ListLanguagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vocablo_v1_word_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLanguagesResponse.ProtoReflect.Descriptor instead.
+func (*ListLanguagesResponse) Descriptor() ([]byte, []int) {
+	return file_vocablo_v1_word_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListLanguagesResponse) GetLanguages() []*Language {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+var File_vocablo_v1_word_proto protoreflect.FileDescriptor
+
+var file_vocablo_v1_word_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x77, 0x6f, 0x72,
+	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f,
+	0x2e, 0x76, 0x31, 0x1a, 0x16, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x76, 0x31, 0x2f,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3b, 0x0a, 0x11, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x65, 0x72, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x67, 0x22, 0x3c, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26,
+	0x0a, 0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x64, 0x52,
+	0x05, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61,
+	0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4b,
+	0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75,
+	0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x76, 0x6f, 0x63,
+	0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
+	0x52, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x32, 0xb0, 0x01, 0x0a, 0x0b,
+	0x57, 0x6f, 0x72, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0a, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x1d, 0x2e, 0x76, 0x6f, 0x63, 0x61,
+	0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x57, 0x6f, 0x72,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x76, 0x6f, 0x63, 0x61, 0x62,
+	0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x57, 0x6f, 0x72, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74,
+	0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x76, 0x6f, 0x63, 0x61,
+	0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x75,
+	0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x76, 0x6f,
+	0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x6e,
+	0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x24,
+	0x5a, 0x22, 0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x76, 0x6f, 0x63, 0x61, 0x62, 0x6c, 0x6f, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x6f, 0x63, 0x61, 0x62,
+	0x6c, 0x6f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_vocablo_v1_word_proto_rawDescOnce sync.Once
+	file_vocablo_v1_word_proto_rawDescData = file_vocablo_v1_word_proto_rawDesc
+)
+
+func file_vocablo_v1_word_proto_rawDescGZIP() []byte {
+	file_vocablo_v1_word_proto_rawDescOnce.Do(func() {
+		file_vocablo_v1_word_proto_rawDescData = protoimpl.X.CompressGZIP(file_vocablo_v1_word_proto_rawDescData)
+	})
+	return file_vocablo_v1_word_proto_rawDescData
+}
+
+var file_vocablo_v1_word_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_vocablo_v1_word_proto_goTypes = []any{
+	(*SearchWordRequest)(nil),     // 0: vocablo.v1.SearchWordRequest
+	(*SearchWordResponse)(nil),    // 1: vocablo.v1.SearchWordResponse
+	(*ListLanguagesRequest)(nil),  // 2: vocablo.v1.ListLanguagesRequest
+	(*ListLanguagesResponse)(nil), // 3: vocablo.v1.ListLanguagesResponse
+	(*Word)(nil),                  // 4: vocablo.v1.Word
+	(*Language)(nil),              // 5: vocablo.v1.Language
+}
+var file_vocablo_v1_word_proto_depIdxs = []int32{
+	4, // 0: vocablo.v1.SearchWordResponse.words:type_name -> vocablo.v1.Word
+	5, // 1: vocablo.v1.ListLanguagesResponse.languages:type_name -> vocablo.v1.Language
+	0, // 2: vocablo.v1.WordService.SearchWord:input_type -> vocablo.v1.SearchWordRequest
+	2, // 3: vocablo.v1.WordService.ListLanguages:input_type -> vocablo.v1.ListLanguagesRequest
+	1, // 4: vocablo.v1.WordService.SearchWord:output_type -> vocablo.v1.SearchWordResponse
+	3, // 5: vocablo.v1.WordService.ListLanguages:output_type -> vocablo.v1.ListLanguagesResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_vocablo_v1_word_proto_init() }
+func file_vocablo_v1_word_proto_init() {
+	if File_vocablo_v1_word_proto != nil {
+		return
+	}
+	file_vocablo_v1_types_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_vocablo_v1_word_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchWordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_word_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchWordResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_word_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ListLanguagesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vocablo_v1_word_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ListLanguagesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vocablo_v1_word_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vocablo_v1_word_proto_goTypes,
+		DependencyIndexes: file_vocablo_v1_word_proto_depIdxs,
+		MessageInfos:      file_vocablo_v1_word_proto_msgTypes,
+	}.Build()
+	File_vocablo_v1_word_proto = out.File
+	file_vocablo_v1_word_proto_rawDesc = nil
+	file_vocablo_v1_word_proto_goTypes = nil
+	file_vocablo_v1_word_proto_depIdxs = nil
+}
@@ -0,0 +1,154 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: vocablo/v1/word.proto
+
+package vocablov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	WordService_SearchWord_FullMethodName    = "/vocablo.v1.WordService/SearchWord"
+	WordService_ListLanguages_FullMethodName = "/vocablo.v1.WordService/ListLanguages"
+)
+
+// WordServiceClient is the client API for WordService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WordService looks up the dictionary
+type WordServiceClient interface {
+	// Searches a word, in the external dictionary when it isn't cached
+	SearchWord(ctx context.Context, in *SearchWordRequest, opts ...grpc.CallOption) (*SearchWordResponse, error)
+	ListLanguages(ctx context.Context, in *ListLanguagesRequest, opts ...grpc.CallOption) (*ListLanguagesResponse, error)
+}
+
+type wordServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWordServiceClient(cc grpc.ClientConnInterface) WordServiceClient {
+	return &wordServiceClient{cc}
+}
+
+func (c *wordServiceClient) SearchWord(ctx context.Context, in *SearchWordRequest, opts ...grpc.CallOption) (*SearchWordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchWordResponse)
+	err := c.cc.Invoke(ctx, WordService_SearchWord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wordServiceClient) ListLanguages(ctx context.Context, in *ListLanguagesRequest, opts ...grpc.CallOption) (*ListLanguagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLanguagesResponse)
+	err := c.cc.Invoke(ctx, WordService_ListLanguages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WordServiceServer is the server API for WordService service.
+// All implementations must embed UnimplementedWordServiceServer
+// for forward compatibility
+//
+// WordService looks up the dictionary
+type WordServiceServer interface {
+	// Searches a word, in the external dictionary when it isn't cached
+	SearchWord(context.Context, *SearchWordRequest) (*SearchWordResponse, error)
+	ListLanguages(context.Context, *ListLanguagesRequest) (*ListLanguagesResponse, error)
+	mustEmbedUnimplementedWordServiceServer()
+}
+
+// UnimplementedWordServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedWordServiceServer struct {
+}
+
+func (UnimplementedWordServiceServer) SearchWord(context.Context, *SearchWordRequest) (*SearchWordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchWord not implemented")
+}
+func (UnimplementedWordServiceServer) ListLanguages(context.Context, *ListLanguagesRequest) (*ListLanguagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLanguages not implemented")
+}
+func (UnimplementedWordServiceServer) mustEmbedUnimplementedWordServiceServer() {}
+
+// UnsafeWordServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WordServiceServer will
+// result in compilation errors.
+type UnsafeWordServiceServer interface {
+	mustEmbedUnimplementedWordServiceServer()
+}
+
+func RegisterWordServiceServer(s grpc.ServiceRegistrar, srv WordServiceServer) {
+	s.RegisterService(&WordService_ServiceDesc, srv)
+}
+
+func _WordService_SearchWord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchWordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordServiceServer).SearchWord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WordService_SearchWord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordServiceServer).SearchWord(ctx, req.(*SearchWordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WordService_ListLanguages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLanguagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordServiceServer).ListLanguages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WordService_ListLanguages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordServiceServer).ListLanguages(ctx, req.(*ListLanguagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WordService_ServiceDesc is the grpc.ServiceDesc for WordService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WordService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vocablo.v1.WordService",
+	HandlerType: (*WordServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchWord",
+			Handler:    _WordService_SearchWord_Handler,
+		},
+		{
+			MethodName: "ListLanguages",
+			Handler:    _WordService_ListLanguages_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vocablo/v1/word.proto",
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"vocablo/ent"
+	vocablov1 "vocablo/proto/vocablo/v1"
+	"vocablo/schema"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toDefinitions(definitions []schema.Definition) []*vocablov1.Definition {
+	messages := make([]*vocablov1.Definition, len(definitions))
+	for i, definition := range definitions {
+		messages[i] = &vocablov1.Definition{PartOfSpeech: definition.PartOfSpeech, Definition: definition.Definition,
+			Example: definition.Example}
+	}
+	return messages
+}
+
+func fromDefinitions(messages []*vocablov1.Definition) []schema.Definition {
+	definitions := make([]schema.Definition, len(messages))
+	for i, message := range messages {
+		definitions[i] = schema.Definition{PartOfSpeech: message.GetPartOfSpeech(),
+			Definition: message.GetDefinition(), Example: message.GetExample()}
+	}
+	return definitions
+}
+
+func toUserWord(userWord *ent.UserWord) *vocablov1.UserWord {
+	message := &vocablov1.UserWord{Id: userWord.ID.String(), CreationDate: timestamppb.New(userWord.CreationDate),
+		Term: userWord.Term, Definitions: toDefinitions(userWord.Definitions),
+		LearningProgress: userWord.LearningProgress, Version: int32(userWord.Version)}
+	if userWord.LearnedDate != nil {
+		message.LearnedDate = timestamppb.New(*userWord.LearnedDate)
+	}
+	return message
+}
+
+func toWord(word *ent.Word) *vocablov1.Word {
+	return &vocablov1.Word{Id: word.ID.String(), CreationDate: timestamppb.New(word.CreationDate), Term: word.Term,
+		Definitions: toDefinitions(word.Definitions)}
+}
+
+func toLanguage(language *ent.Language) *vocablov1.Language {
+	return &vocablov1.Language{Id: language.ID.String(), Code: language.Code, Name: language.Name,
+		NativeName: language.NativeName, Direction: language.Direction}
+}
@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"vocablo/customerrors"
+	"vocablo/utils"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of the errors
+const errorDomain = "vocablo"
+
+// httpCodes are the gRPC codes of the statuses the errors have in the HTTP API
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusUnauthorized:         codes.Unauthenticated,
+	http.StatusForbidden:            codes.PermissionDenied,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusConflict:             codes.FailedPrecondition,
+	http.StatusGone:                 codes.FailedPrecondition,
+	http.StatusPreconditionFailed:   codes.FailedPrecondition,
+	http.StatusPreconditionRequired: codes.FailedPrecondition,
+	http.StatusTooManyRequests:      codes.ResourceExhausted,
+	http.StatusInternalServerError:  codes.Internal,
+	http.StatusServiceUnavailable:   codes.Unavailable,
+	http.StatusGatewayTimeout:       codes.DeadlineExceeded,
+}
+
+// statusError maps the error with the same rules as the HTTP API. The error code is in the reason of an ErrorInfo
+// detail, with the request ID in its metadata
+func statusError(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	res := utils.Error(ctx, err)
+	code, ok := httpCodes[res.Status]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, *res.Body.ErrorMessage)
+	info := &errdetails.ErrorInfo{Reason: *res.Body.ErrorCode, Domain: errorDomain}
+	info.Metadata = map[string]string{}
+	if res.Body.RequestId != "" {
+		info.Metadata["requestId"] = res.Body.RequestId
+	}
+	//The client needs the current version to make the change again
+	if stale, ok := err.(customerrors.StaleVersionError); ok {
+		info.Metadata["version"] = strconv.Itoa(stale.Version)
+	}
+	detailed, detailErr := st.WithDetails(info)
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// invalidArgument is the error of a request whose fields can't be converted to the forms of the services
+func invalidArgument(ctx context.Context, err error) error {
+	res := utils.InvalidRequest(ctx, err)
+	st := status.New(codes.InvalidArgument, *res.Body.ErrorMessage)
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: *res.Body.ErrorCode, Domain: errorDomain})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"vocablo/customerrors"
+	"vocablo/svc"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+const requestIdMetadata = "x-request-id"
+
+// publicServices are answered without a token
+var publicServices = []string{grpc_health_v1.Health_ServiceDesc.ServiceName,
+	grpc_reflection_v1.ServerReflection_ServiceDesc.ServiceName,
+	grpc_reflection_v1alpha.ServerReflection_ServiceDesc.ServiceName}
+
+// requestInfo adds to the context the request ID, reusing the one sent in the metadata if present, and the client
+// IP and user agent, as the HTTP middleware does
+func requestInfo(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestId := firstValue(md, requestIdMetadata)
+	if requestId == "" || len(requestId) > 128 {
+		requestId = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIdMetadata, requestId))
+	clientIp := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		clientIp = p.Addr.String()
+	}
+	ctx = context.WithValue(ctx, utils.RequestIdKey, requestId)
+	ctx = context.WithValue(ctx, utils.ClientIpKey, clientIp)
+	ctx = context.WithValue(ctx, utils.UserAgentKey, firstValue(md, "user-agent"))
+	return ctx
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// authenticate checks the bearer token of the authorization metadata, which is the JWT of the session, and adds the
+// user to the context under utils.UserIdKey. No CSRF token is needed, as the token isn't sent by the browser
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token, found := strings.CutPrefix(firstValue(md, "authorization"), "Bearer ")
+	if !found || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "Not bearer token present")
+	}
+	tokenClaims, err := utils.ValidateToken(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+	}
+	//The session is revoked when the user changes the credentials or deletes the account
+	sessionUser, err := svc.Get().User.Get(ctx, tokenClaims.Id)
+	if _, notFound := err.(customerrors.NotFoundError); err != nil && !notFound {
+		return nil, statusError(ctx, err)
+	}
+	if sessionUser == nil || sessionUser.SessionVersion != tokenClaims.SessionVersion {
+		return nil, status.Error(codes.Unauthenticated, "Revoked session")
+	}
+	ctx = context.WithValue(ctx, utils.UserIdKey, tokenClaims.Id)
+	ctx = context.WithValue(ctx, utils.UserRoleKey, sessionUser.Role)
+	return ctx, nil
+}
+
+func isPublic(fullMethod string) bool {
+	for _, service := range publicServices {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// logCall logs every call once answered, with its ID and the context so the trace IDs are added
+func logCall(ctx context.Context, fullMethod string, start time.Time, err error) {
+	code := status.Code(err)
+	var event *zerolog.Event
+	switch code {
+	case codes.OK:
+		event = log.Info()
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		event = log.Error()
+	default:
+		event = log.Warn()
+	}
+	requestId, _ := ctx.Value(utils.RequestIdKey).(string)
+	clientIp, _ := ctx.Value(utils.ClientIpKey).(string)
+	event.Ctx(ctx).Str("request_id", requestId).Str("method", fullMethod).Dur("resp_time", time.Since(start)).
+		Str("code", code.String()).Str("client_ip", clientIp).Msg("Call")
+}
+
+// unaryInterceptor adds the request info, authenticates the calls of the private services and logs them. The errors
+// of the services are mapped to statuses, and the panics answered as unexpected errors
+func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+	ctx = requestInfo(ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = statusError(ctx, fmt.Errorf("panic: %v", recovered))
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+	if !isPublic(info.FullMethod) {
+		userCtx, authErr := authenticate(ctx)
+		if authErr != nil {
+			return nil, authErr
+		}
+		ctx = userCtx
+	}
+	resp, err = handler(ctx, req)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return resp, nil
+}
+
+// wrappedStream overrides the context of a stream
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamInterceptor does for the streams, like the health watch and reflection, what unaryInterceptor does for the
+// unary calls
+func streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	ctx := requestInfo(stream.Context())
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = statusError(ctx, fmt.Errorf("panic: %v", recovered))
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+	if !isPublic(info.FullMethod) {
+		userCtx, authErr := authenticate(ctx)
+		if authErr != nil {
+			return authErr
+		}
+		ctx = userCtx
+	}
+	err = handler(srv, &wrappedStream{ServerStream: stream, ctx: ctx})
+	if err != nil {
+		return statusError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	vocablov1 "vocablo/proto/vocablo/v1"
+	"vocablo/svc"
+	"vocablo/svc/quiz"
+
+	"github.com/google/uuid"
+)
+
+type quizServer struct {
+	vocablov1.UnimplementedQuizServiceServer
+}
+
+func (s *quizServer) CreateQuiz(ctx context.Context,
+	req *vocablov1.CreateQuizRequest) (*vocablov1.CreateQuizResponse, error) {
+	createdQuiz, err := svc.Get().Quiz.Create(ctx, quiz.CreateForm{NQuestions: int(req.GetNQuestions())})
+	if err != nil {
+		return nil, err
+	}
+	message := &vocablov1.Quiz{Questions: make([]*vocablov1.QuizQuestion, len(createdQuiz.Questions)),
+		Score: int32(createdQuiz.Score)}
+	for i, question := range createdQuiz.Questions {
+		message.Questions[i] = &vocablov1.QuizQuestion{UserWordId: question.UserWordID.String(),
+			Question: question.Question, Options: question.Options, CorrectOptionPos: int32(question.CorrectOptionPos)}
+	}
+	return &vocablov1.CreateQuizResponse{Quiz: message}, nil
+}
+
+func (s *quizServer) AnswerQuiz(ctx context.Context,
+	req *vocablov1.AnswerQuizRequest) (*vocablov1.AnswerQuizResponse, error) {
+	questions := req.GetQuiz().GetQuestions()
+	filledQuiz := quiz.Quiz{Questions: make([]quiz.QuizQuestion, len(questions))}
+	for i, question := range questions {
+		userWordId, err := uuid.Parse(question.GetUserWordId())
+		if err != nil {
+			return nil, invalidArgument(ctx, err)
+		}
+		//The positions are checked here, as the service indexes the options with them
+		nOptions := int32(len(question.GetOptions()))
+		if question.GetCorrectOptionPos() < 0 || question.GetCorrectOptionPos() >= nOptions ||
+			(question.AnswerPos != nil && (question.GetAnswerPos() < 0 || question.GetAnswerPos() >= nOptions)) {
+			return nil, invalidArgument(ctx, fmt.Errorf("the positions of the question %d are out of its options", i))
+		}
+		filledQuiz.Questions[i] = quiz.QuizQuestion{UserWordID: userWordId, Question: question.GetQuestion(),
+			Options: question.GetOptions(), CorrectOptionPos: int(question.GetCorrectOptionPos())}
+		if question.AnswerPos != nil {
+			answerPos := int(question.GetAnswerPos())
+			filledQuiz.Questions[i].AnswerPos = &answerPos
+		}
+	}
+	score, err := svc.Get().Quiz.Answer(ctx, filledQuiz)
+	if err != nil {
+		return nil, err
+	}
+	return &vocablov1.AnswerQuizResponse{Score: int32(score)}, nil
+}
@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+	vocablov1 "vocablo/proto/vocablo/v1"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Server is the gRPC server with its health service, to report it's not serving on shutdown
+type Server struct {
+	*grpc.Server
+	Health *health.Server
+}
+
+// NewServer creates the gRPC server of the UserWord, Word and Quiz services, with reflection and the health
+// service. It serves TLS when the config is set
+func NewServer(tlsConfig *tls.Config) *Server {
+	options := []grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptor), grpc.ChainStreamInterceptor(streamInterceptor)}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := &Server{Server: grpc.NewServer(options...), Health: health.NewServer()}
+	vocablov1.RegisterUserWordServiceServer(server, &userWordServer{})
+	vocablov1.RegisterWordServiceServer(server, &wordServer{})
+	vocablov1.RegisterQuizServiceServer(server, &quizServer{})
+	grpc_health_v1.RegisterHealthServer(server, server.Health)
+	reflection.Register(server)
+	for service := range server.GetServiceInfo() {
+		server.Health.SetServingStatus(service, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+	return server
+}
+
+// Serve accepts the connections of the listener until the context is done. Then it reports not serving and waits
+// for the in-flight calls up to the shutdown timeout before closing the rest
+func Serve(ctx context.Context, server *Server, listener net.Listener, shutdownTimeout time.Duration) error {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	log.Info().Msg("Shutting down the gRPC server")
+	server.Health.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		server.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"context"
+	"vocablo/customerrors"
+	vocablov1 "vocablo/proto/vocablo/v1"
+	"vocablo/svc"
+	"vocablo/svc/userword"
+)
+
+// userWordServer exposes the UserWord service, which only lets the user of the context access their words
+type userWordServer struct {
+	vocablov1.UnimplementedUserWordServiceServer
+}
+
+func (s *userWordServer) CreateUserWord(ctx context.Context,
+	req *vocablov1.CreateUserWordRequest) (*vocablov1.CreateUserWordResponse, error) {
+	form := userword.CreateForm{Term: req.GetTerm(), Lang: req.GetLang(), Definitions: fromDefinitions(req.GetDefinitions())}
+	userWord, err := svc.Get().UserWord.Create(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	return &vocablov1.CreateUserWordResponse{UserWord: toUserWord(userWord)}, nil
+}
+
+func (s *userWordServer) UpdateUserWord(ctx context.Context,
+	req *vocablov1.UpdateUserWordRequest) (*vocablov1.UpdateUserWordResponse, error) {
+	//As in the HTTP API, the words can only be changed from the version the client has
+	if req.ExpectedVersion == nil {
+		return nil, customerrors.PreconditionRequiredError{}
+	}
+	form := userword.UpdateForm{ID: req.GetId(), Term: req.Term, Versions: []int{int(req.GetExpectedVersion())}}
+	if req.GetUpdateDefinitions() {
+		definitions := fromDefinitions(req.GetDefinitions())
+		form.Definitions = &definitions
+	}
+	userWord, err := svc.Get().UserWord.Update(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	return &vocablov1.UpdateUserWordResponse{UserWord: toUserWord(userWord)}, nil
+}
+
+func (s *userWordServer) GetUserWord(ctx context.Context,
+	req *vocablov1.GetUserWordRequest) (*vocablov1.GetUserWordResponse, error) {
+	userWord, err := svc.Get().UserWord.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &vocablov1.GetUserWordResponse{UserWord: toUserWord(userWord)}, nil
+}
+
+func (s *userWordServer) DeleteUserWord(ctx context.Context,
+	req *vocablov1.DeleteUserWordRequest) (*vocablov1.DeleteUserWordResponse, error) {
+	err := svc.Get().UserWord.Delete(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &vocablov1.DeleteUserWordResponse{}, nil
+}
+
+func (s *userWordServer) SearchUserWords(ctx context.Context,
+	req *vocablov1.SearchUserWordsRequest) (*vocablov1.SearchUserWordsResponse, error) {
+	form := userword.SearchForm{Term: req.Term, Lang: req.Lang, Learned: req.Learned, OrderBy: req.OrderBy,
+		OrderDir: req.OrderDir, Page: int(req.GetPage()), PageSize: int(req.GetPageSize()), Count: req.GetCount()}
+	page, err := svc.Get().UserWord.Search(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	res := &vocablov1.SearchUserWordsResponse{UserWords: make([]*vocablov1.UserWord, len(page.Content)),
+		PageNumber: int32(page.PageNumber), HasNext: page.HasNext, NElements: int32(page.NElements)}
+	for i, userWord := range page.Content {
+		res.UserWords[i] = toUserWord(userWord)
+	}
+	return res, nil
+}
+
+func (s *userWordServer) GetProgress(ctx context.Context,
+	req *vocablov1.GetProgressRequest) (*vocablov1.GetProgressResponse, error) {
+	progress, err := svc.Get().UserWord.Progress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &vocablov1.GetProgressResponse{TotalWords: int32(progress.TotalWords),
+		LearnedWords: int32(progress.LearnedWords), UnlearnedWords: int32(progress.UnlearnedWords)}, nil
+}
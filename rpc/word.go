@@ -0,0 +1,37 @@
+package rpc
+
+import (
+	"context"
+	vocablov1 "vocablo/proto/vocablo/v1"
+	"vocablo/svc"
+)
+
+type wordServer struct {
+	vocablov1.UnimplementedWordServiceServer
+}
+
+func (s *wordServer) SearchWord(ctx context.Context,
+	req *vocablov1.SearchWordRequest) (*vocablov1.SearchWordResponse, error) {
+	page, err := svc.Get().Word.Search(ctx, req.GetLang(), req.GetTerm())
+	if err != nil {
+		return nil, err
+	}
+	res := &vocablov1.SearchWordResponse{Words: make([]*vocablov1.Word, len(page.Content))}
+	for i, word := range page.Content {
+		res.Words[i] = toWord(word)
+	}
+	return res, nil
+}
+
+func (s *wordServer) ListLanguages(ctx context.Context,
+	req *vocablov1.ListLanguagesRequest) (*vocablov1.ListLanguagesResponse, error) {
+	languages, err := svc.Get().Language.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &vocablov1.ListLanguagesResponse{Languages: make([]*vocablov1.Language, len(languages))}
+	for i, language := range languages {
+		res.Languages[i] = toLanguage(language)
+	}
+	return res, nil
+}
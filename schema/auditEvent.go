@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuditEvent holds the schema definition for the AuditEvent entity. It records a security-relevant
+// action made on an account, like a login or a password reset.
+type AuditEvent struct {
+	ent.Schema
+}
+
+func (AuditEvent) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		CommonMixin{},
+	}
+}
+
+// Annotations of the AuditEvent. It isn't exposed in the GraphQL API
+func (AuditEvent) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
+
+// Fields of the AuditEvent.
+func (AuditEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("type").NotEmpty(),
+		//Kept so the event can still be identified after the account is deleted, or when it doesn't exist
+		field.String("username").Optional(),
+		field.String("ip").Default(""),
+		field.String("userAgent").Default(""),
+		field.String("requestId").Default(""),
+		field.String("detail").Optional(),
+	}
+}
+
+// Edges of the AuditEvent.
+func (AuditEvent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).Ref("auditEvents").Unique(),
+	}
+}
+
+// Indexes of the AuditEvent.
+func (AuditEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("creationDate"),
+	}
+}
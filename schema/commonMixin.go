@@ -3,6 +3,7 @@ package schema
 import (
 	"time"
 
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/mixin"
@@ -20,6 +21,27 @@ func (CommonMixin) Fields() []ent.Field {
 		field.UUID("id", uuid.UUID{}).Unique().Default(uuid.New).Immutable(),
 		field.Time("creationDate").
 			Immutable().
-			Default(time.Now),
+			Default(time.Now).
+			Annotations(entgql.OrderField("CREATION_DATE")),
+		//Set on every update, for the delta sync of the mobile clients
+		field.Time("updatedAt").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+// SyncMixin lets the mobile clients sync an entity offline. Every change of it is numbered with the sync version
+// of its user, and its deletions are kept as tombstones
+type SyncMixin struct {
+	mixin.Schema
+}
+
+func (SyncMixin) Fields() []ent.Field {
+	return []ent.Field{
+		//Sync versions of the user when the entity was created and last changed
+		field.Int("creationVersion").Default(0).Immutable(),
+		field.Int("version").Default(0),
+		//Set instead of deleting the entity, which is then only seen by the sync
+		field.Time("deletedAt").Optional().Nillable().Annotations(entgql.Skip()),
 	}
 }
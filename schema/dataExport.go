@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// DataExport holds the schema definition for the DataExport entity, an archive with all the user data.
+type DataExport struct {
+	ent.Schema
+}
+
+func (DataExport) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		CommonMixin{},
+	}
+}
+
+// Annotations of the DataExport. It isn't exposed in the GraphQL API
+func (DataExport) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
+
+// Fields of the DataExport.
+func (DataExport) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("status").NotEmpty(),
+		field.Bytes("archive").Optional().StructTag(`json:"-"`),
+		//Hash of the token sent by email to download the archive
+		field.String("downloadToken").Optional().StructTag(`json:"-"`),
+		field.Time("expireDate").Optional().Nillable(),
+	}
+}
+
+// Edges of the DataExport.
+func (DataExport) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).Ref("dataExports").Required().Unique(),
+	}
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
@@ -22,13 +23,16 @@ func (Language) Mixin() []ent.Mixin {
 func (Language) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("code").Unique().NotEmpty(),
+		field.String("name").Default(""),
+		field.String("nativeName").Default(""),
+		field.String("direction").Default("ltr"),
 	}
 }
 
-// Edges of the Language.
+// Edges of the Language. They aren't in the GraphQL API, the user words are of all the users
 func (Language) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("words", Word.Type).Annotations(entsql.OnDelete(entsql.Cascade)),
-		edge.To("userWords", UserWord.Type).Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("words", Word.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
+		edge.To("userWords", UserWord.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
 	}
 }
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Outbox holds the schema definition for the Outbox entity. It is an email waiting to be delivered, written in
+// the same transaction as the change that sends it, so the mail is only sent if the change is committed.
+type Outbox struct {
+	ent.Schema
+}
+
+func (Outbox) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		CommonMixin{},
+	}
+}
+
+// Annotations of the Outbox. It isn't exposed in the GraphQL API
+func (Outbox) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
+
+// Fields of the Outbox.
+func (Outbox) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("to").NotEmpty(),
+		field.String("template").Default(""),
+		field.String("subject"),
+		field.Text("text").StructTag(`json:"-"`),
+		field.Text("html").Default("").StructTag(`json:"-"`),
+		//One-click unsubscribe link of the emails sent without being requested, it has a token like the bodies
+		field.String("listUnsubscribe").Default("").StructTag(`json:"-"`),
+		field.String("status").NotEmpty(),
+		field.Int("attempts").Default(0),
+		//When it can be sent, or when the lease of the worker sending it expires
+		field.Time("nextAttemptDate").Default(time.Now),
+		field.String("lastError").Optional(),
+		field.Time("sentDate").Optional().Nillable(),
+	}
+}
+
+// Indexes of the Outbox.
+func (Outbox) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "nextAttemptDate"),
+	}
+}
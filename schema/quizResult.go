@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+// QuizResult holds the schema definition for the QuizResult entity, that stores every answered quiz.
+type QuizResult struct {
+	ent.Schema
+}
+
+type QuizAnswer struct {
+	UserWordID uuid.UUID `json:"userWordId"`
+	Term       string    `json:"term"`
+	Correct    bool      `json:"correct"`
+}
+
+func (QuizResult) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		CommonMixin{},
+	}
+}
+
+// Annotations of the QuizResult. It isn't exposed in the GraphQL API
+func (QuizResult) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
+
+// Fields of the QuizResult.
+func (QuizResult) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("score").Min(0).Max(100),
+		field.Int("nQuestions").Min(0),
+		field.Int("nCorrect").Min(0),
+		field.JSON("answers", []QuizAnswer{}).Default([]QuizAnswer{}),
+	}
+}
+
+// Edges of the QuizResult.
+func (QuizResult) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).Ref("quizResults").Required().Unique(),
+	}
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
@@ -23,15 +24,38 @@ func (User) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("Username").Unique().NotEmpty().StructTag(`json:"username"`),
 		field.String("Email").Unique().NotEmpty().StructTag(`json:"email"`),
-		field.String("Password").NotEmpty().StructTag(`json:"-"`),
+		field.String("Password").NotEmpty().StructTag(`json:"-"`).Annotations(entgql.Skip()),
 		field.Bool("Validated").StorageKey("validated").Default(false).StructTag(`json:"validated"`),
+		field.String("role").Default("user").StructTag(`json:"role"`),
+		field.Bool("disabled").Default(false).StructTag(`json:"disabled"`),
+		//Language of the emails, the default one of the mail templates when empty
+		field.String("locale").Default("").StructTag(`json:"locale"`),
+		//Study reminders, sent daily at the reminder time of the user timezone unless they opt out
+		field.Bool("remindersEnabled").Default(true).StructTag(`json:"remindersEnabled"`),
+		field.String("reminderTime").Default("18:00").StructTag(`json:"reminderTime"`),
+		field.String("timezone").Default("UTC").StructTag(`json:"timezone"`),
+		field.Time("lastReminderDate").Optional().Nillable().StructTag(`json:"-"`).Annotations(entgql.Skip()),
+		//Weekly progress digest, sent on the digest weekday only to the users that opt in
+		field.Bool("digestEnabled").Default(false).StructTag(`json:"digestEnabled"`),
+		field.Time("lastDigestDate").Optional().Nillable().StructTag(`json:"-"`).Annotations(entgql.Skip()),
+		//Incremented every time the credentials change, to invalidate the JWTs issued before
+		field.Int("sessionVersion").Default(0).StructTag(`json:"-"`).Annotations(entgql.Skip()),
+		//Incremented on every change of the words of the user, to number them for the delta sync
+		field.Int("syncVersion").Default(0).StructTag(`json:"-"`).Annotations(entgql.Skip()),
+		//Version of the last deleted word purged. The cursors before it would miss the deletion
+		field.Int("purgedSyncVersion").Default(0).StructTag(`json:"-"`).Annotations(entgql.Skip()),
 	}
 }
 
-// Edges of the User.
+// Edges of the User. In the GraphQL API the words are queried on their own, scoped to the user
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("codes", VerificationCode.Type).Annotations(entsql.OnDelete(entsql.Cascade)),
-		edge.To("userWords", UserWord.Type).Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("codes", VerificationCode.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
+		edge.To("userWords", UserWord.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
+		edge.To("identities", Identity.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
+		edge.To("quizResults", QuizResult.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
+		edge.To("dataExports", DataExport.Type).Annotations(entsql.OnDelete(entsql.Cascade), entgql.Skip()),
+		//The audit log outlives the account
+		edge.To("auditEvents", AuditEvent.Type).Annotations(entsql.OnDelete(entsql.SetNull), entgql.Skip()),
 	}
 }
@@ -1,9 +1,12 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 )
 
 // UserWord holds the schema definition for the UserWord entity.
@@ -20,15 +23,32 @@ type UserWordProgress struct {
 func (UserWord) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		CommonMixin{},
+		SyncMixin{},
+	}
+}
+
+// Annotations of the UserWord. The words of the user are paginated as a Relay connection
+func (UserWord) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.RelayConnection(),
 	}
 }
 
 // Fields of the UserWord.
 func (UserWord) Fields() []ent.Field {
 	return []ent.Field{
-		field.String("term").NotEmpty(),
-		field.JSON("definitions", []Definition{}).Default([]Definition{}),
-		field.Float("learningProgress").Default(0.0).Max(100).Min(0),
+		field.String("term").NotEmpty().Annotations(entgql.OrderField("TERM")),
+		field.JSON("definitions", []Definition{}).Default([]Definition{}).Annotations(entgql.Type("[Definition!]")),
+		field.Float("learningProgress").Default(0.0).Max(100).Min(0).Annotations(entgql.OrderField("LEARNING_PROGRESS")),
+		//When the learning progress reached 100, for the weekly digest
+		field.Time("learnedDate").Optional().Nillable(),
+	}
+}
+
+// Indexes of the UserWord. The sync reads the changes of a user in the order of their versions
+func (UserWord) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("version").Edges("user"),
 	}
 }
 
@@ -40,6 +60,7 @@ func (UserWord) Edges() []ent.Edge {
 			Unique(),
 		edge.From("user", User.Type).
 			Ref("userWords").
-			Unique(),
+			Unique().
+			Annotations(entgql.Skip()),
 	}
 }
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema"
@@ -22,6 +23,8 @@ func (VerificationCode) Mixin() []ent.Mixin {
 func (VerificationCode) Annotations() []schema.Annotation {
 	return []schema.Annotation{
 		entsql.Annotation{Table: "verification_codes"},
+		//It isn't exposed in the GraphQL API
+		entgql.Skip(entgql.SkipAll),
 	}
 }
 
@@ -29,9 +32,12 @@ func (VerificationCode) Annotations() []schema.Annotation {
 func (VerificationCode) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("type").NotEmpty(),
-		field.String("code").NotEmpty(),
+		//HMAC of the code, the plain one is only sent to the user
+		field.String("code").NotEmpty().StructTag(`json:"-"`),
 		field.Time("expireDate").StorageKey("expire_date"),
 		field.Bool("used").Default(false),
+		//Only for email change codes, the address that will replace the current one once the code is used
+		field.String("newEmail").Optional(),
 	}
 }
 
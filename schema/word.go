@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
@@ -27,7 +28,7 @@ func (Word) Mixin() []ent.Mixin {
 func (Word) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("term").NotEmpty(),
-		field.JSON("definitions", []Definition{}).Default([]Definition{}),
+		field.JSON("definitions", []Definition{}).Default([]Definition{}).Annotations(entgql.Type("[Definition!]")),
 	}
 }
 
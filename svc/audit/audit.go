@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"vocablo/ent"
+	"vocablo/ent/auditevent"
+	"vocablo/ent/user"
+	"vocablo/utils"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type Event struct {
+	Type string
+	// If nil, the user is looked up by the username
+	UserId   *uuid.UUID
+	Username string
+	Detail   string
+}
+
+type AuditSvc interface {
+	Record(ctx context.Context, event Event)
+	Search(ctx context.Context, form SearchForm) (*utils.Page[*ent.AuditEvent], error)
+}
+
+type AuditSvcImpl struct {
+	DB *ent.Client
+}
+
+// Record saves the event with the request info in the context. Errors are only logged, as the audit must not
+// make the audited action fail
+func (s *AuditSvcImpl) Record(ctx context.Context, event Event) {
+	eventCreate := s.DB.AuditEvent.Create().SetType(event.Type).SetDetail(event.Detail).
+		SetIP(ctxString(ctx, utils.ClientIpKey)).SetUserAgent(ctxString(ctx, utils.UserAgentKey)).
+		SetRequestId(ctxString(ctx, utils.RequestIdKey))
+	if event.UserId != nil {
+		eventUser, err := s.DB.User.Get(ctx, *event.UserId)
+		if err == nil {
+			eventCreate.SetUser(eventUser)
+			event.Username = eventUser.Username
+		}
+	} else if event.Username != "" {
+		userId, err := s.DB.User.Query().Where(user.UsernameEQ(event.Username)).FirstID(ctx)
+		if err == nil {
+			eventCreate.SetUserID(userId)
+		}
+	}
+	if event.Username != "" {
+		eventCreate.SetUsername(event.Username)
+	}
+	err := eventCreate.Exec(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("type", event.Type).Str("username", event.Username).Msg("Error recording the audit event")
+	}
+}
+
+// Search returns the events of the logged user, the newest first
+func (s *AuditSvcImpl) Search(ctx context.Context, form SearchForm) (*utils.Page[*ent.AuditEvent], error) {
+	if form.Page <= 0 {
+		form.Page = 0
+	}
+	if form.PageSize <= 0 {
+		form.PageSize = 10
+	}
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	query := s.DB.AuditEvent.Query().Where(auditevent.HasUserWith(user.IDEQ(userId)))
+	total, err := query.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := utils.Page[*ent.AuditEvent]{PageNumber: form.Page, NElements: total}
+	if total > (form.Page+1)*form.PageSize {
+		page.HasNext = true
+	}
+	events, err := query.Offset(form.Page * form.PageSize).Limit(form.PageSize).
+		Order(auditevent.ByCreationDate(sql.OrderDesc())).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page.Content = events
+	return &page, nil
+}
+
+func ctxString(ctx context.Context, key utils.CtxKey) string {
+	value, _ := ctx.Value(key).(string)
+	return value
+}
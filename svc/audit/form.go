@@ -0,0 +1,6 @@
+package audit
+
+type SearchForm struct {
+	Page     int `form:"page"`
+	PageSize int `form:"pageSize"`
+}
@@ -2,18 +2,32 @@ package auth
 
 import (
 	"context"
+	"strings"
 	"vocablo/customerrors"
 	"vocablo/ent"
 	"vocablo/ent/user"
+	"vocablo/metrics"
+	"vocablo/svc/audit"
+	"vocablo/svc/language"
+	"vocablo/svc/mail"
+	"vocablo/svc/password"
 	"vocablo/svc/verificationcode"
+	"vocablo/tracing"
 	"vocablo/utils"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthSvc interface {
 	Login(ctx context.Context, form LoginForm) (*LoginResult, error)
 	SignUp(ctx context.Context, form SignUpForm) (*ent.User, error)
+	ChangePassword(ctx context.Context, form ChangePasswordForm) (*LoginResult, error)
+	RequestEmailChange(ctx context.Context, form ChangeEmailForm) error
+	ConfirmEmailChange(ctx context.Context, code string) (*LoginResult, error)
+	RequestMagicLink(ctx context.Context, form verificationcode.MagicLinkForm) (string, error)
+	MagicLinkLogin(ctx context.Context, form verificationcode.UseMagicLinkForm) (*LoginResult, error)
 }
 
 type LoginResult struct {
@@ -24,6 +38,9 @@ type LoginResult struct {
 type AuthSvcImpl struct {
 	DB                  *ent.Client
 	VerificationCodeSvc verificationcode.VerificationCodeSvc
+	Mail                mail.MailSvc
+	Audit               audit.AuditSvc
+	Password            password.PasswordSvc
 }
 
 func checkPassword(hashPassword, password string) bool {
@@ -31,21 +48,40 @@ func checkPassword(hashPassword, password string) bool {
 	return err == nil
 }
 
-func (s *AuthSvcImpl) Login(ctx context.Context, form LoginForm) (*LoginResult, error) {
+func (s *AuthSvcImpl) Login(ctx context.Context, form LoginForm) (result *LoginResult, err error) {
 	if form.Username == "" || form.Password == "" {
 		return nil, customerrors.EmptyFormFieldsError{}
 	}
+	ctx, span := tracing.Start(ctx, "AuthSvc.Login")
+	defer tracing.End(span, &err)
 	loginUser, err := s.DB.User.Query().Where(user.UsernameEQ(form.Username)).Only(ctx)
 	if err != nil {
+		s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_FAILURE_EVENT, Username: form.Username, Detail: "unknown user"})
+		metrics.LoginFailures.WithLabelValues(metrics.UNKNOWN_USER_REASON).Inc()
 		return nil, customerrors.InvalidCredentialsError{}
 	}
 
 	if !checkPassword(loginUser.Password, form.Password) {
+		s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_FAILURE_EVENT, UserId: &loginUser.ID, Detail: "incorrect password"})
+		metrics.LoginFailures.WithLabelValues(metrics.INCORRECT_PASSWORD_REASON).Inc()
 		return nil, customerrors.InvalidCredentialsError{}
 	}
+	if loginUser.Disabled {
+		s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_FAILURE_EVENT, UserId: &loginUser.ID, Detail: "disabled account"})
+		metrics.LoginFailures.WithLabelValues(metrics.DISABLED_ACCOUNT_REASON).Inc()
+		return nil, customerrors.DisabledAccountError{}
+	}
 	if !loginUser.Validated {
+		s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_FAILURE_EVENT, UserId: &loginUser.ID, Detail: "not validated account"})
+		metrics.LoginFailures.WithLabelValues(metrics.NOT_VALIDATED_REASON).Inc()
 		return nil, customerrors.NotValidatedAccountError{}
 	}
+	s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_SUCCESS_EVENT, UserId: &loginUser.ID, Detail: "password"})
+	return GenerateLoginResult(loginUser)
+}
+
+// GenerateLoginResult creates the session tokens for an already authenticated user
+func GenerateLoginResult(loginUser *ent.User) (*LoginResult, error) {
 	csrfToken, err := utils.GenerateRandomToken(64)
 	if err != nil {
 		return nil, err
@@ -56,7 +92,7 @@ func (s *AuthSvcImpl) Login(ctx context.Context, form LoginForm) (*LoginResult,
 		return nil, err
 	}
 
-	tokenString, err := utils.GenerateJWT(loginUser.ID.String(), loginUser.Email, loginUser.Username, csrfToken)
+	tokenString, err := utils.GenerateJWT(loginUser.ID.String(), loginUser.Email, loginUser.Username, csrfToken, loginUser.SessionVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +103,11 @@ func (s *AuthSvcImpl) SignUp(ctx context.Context, form SignUpForm) (createdUser
 	if form.Username == "" || form.Email == "" || form.Password == "" {
 		return nil, customerrors.EmptyFormFieldsError{}
 	}
+	ctx, span := tracing.Start(ctx, "AuthSvc.SignUp")
+	defer tracing.End(span, &err)
+	if err := s.Password.Check(form.Password, form.Username, form.Email); err != nil {
+		return nil, err
+	}
 	bytesPass, err := bcrypt.GenerateFromPassword([]byte(form.Password), 14)
 
 	if err != nil {
@@ -85,7 +126,14 @@ func (s *AuthSvcImpl) SignUp(ctx context.Context, form SignUpForm) (createdUser
 	if err != nil {
 		return nil, err
 	}
-	user, err := clientTx.User.Create().SetUsername(form.Username).SetPassword(string(bytesPass[:])).SetEmail(form.Email).Save(ctx)
+	userCreate := clientTx.User.Create().SetUsername(form.Username).SetPassword(string(bytesPass[:])).SetEmail(form.Email)
+	//An unknown locale is ignored, the emails are sent in the default one
+	locale := strings.ToLower(strings.ReplaceAll(form.Locale, "_", "-"))
+	baseLanguage, _, _ := strings.Cut(locale, "-")
+	if _, ok := language.Lookup(baseLanguage); ok && len(locale) <= 16 {
+		userCreate.SetLocale(locale)
+	}
+	user, err := userCreate.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -99,5 +147,113 @@ func (s *AuthSvcImpl) SignUp(ctx context.Context, form SignUpForm) (createdUser
 	if err != nil {
 		return nil, err
 	}
+	s.Audit.Record(ctx, audit.Event{Type: utils.SIGN_UP_EVENT, UserId: &user.ID})
+	metrics.SignUps.WithLabelValues(metrics.PASSWORD_METHOD).Inc()
 	return createdUser, nil
 }
+
+// ChangePassword updates the password of the logged user, revoking the rest of sessions. It returns
+// the new tokens for the current session
+func (s *AuthSvcImpl) ChangePassword(ctx context.Context, form ChangePasswordForm) (*LoginResult, error) {
+	if form.CurrentPassword == "" || form.NewPassword == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	loggedUser, err := s.DB.User.Get(ctx, ctx.Value(utils.UserIdKey).(uuid.UUID))
+	if err != nil {
+		return nil, err
+	}
+	if !checkPassword(loggedUser.Password, form.CurrentPassword) {
+		return nil, customerrors.IncorrectCurrentPasswordError{}
+	}
+	if err := s.Password.Check(form.NewPassword, loggedUser.Username, loggedUser.Email); err != nil {
+		return nil, err
+	}
+	bytesPass, err := bcrypt.GenerateFromPassword([]byte(form.NewPassword), 14)
+	if err != nil {
+		return nil, err
+	}
+	updatedUser, err := s.DB.User.UpdateOne(loggedUser).SetPassword(string(bytesPass[:])).AddSessionVersion(1).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.Audit.Record(ctx, audit.Event{Type: utils.PASSWORD_CHANGE_EVENT, UserId: &updatedUser.ID})
+	s.Audit.Record(ctx, audit.Event{Type: utils.TOKEN_REVOCATION_EVENT, UserId: &updatedUser.ID, Detail: "password change"})
+	err = s.Mail.Send(ctx, nil, updatedUser.Email, updatedUser.Locale, mail.PASSWORD_CHANGED_TEMPLATE, mail.TemplateData{Username: updatedUser.Username})
+	if err != nil {
+		log.Warn().Err(err).Msg("Error queuing the password change notification")
+	}
+	return GenerateLoginResult(updatedUser)
+}
+
+// RequestEmailChange sends a code to the new address. The email is not changed until the code is confirmed
+func (s *AuthSvcImpl) RequestEmailChange(ctx context.Context, form ChangeEmailForm) error {
+	if form.NewEmail == "" {
+		return customerrors.EmptyFormFieldsError{}
+	}
+	loggedUser, err := s.DB.User.Get(ctx, ctx.Value(utils.UserIdKey).(uuid.UUID))
+	if err != nil {
+		return err
+	}
+	alreadyExistMail, err := s.DB.User.Query().Where(user.EmailEQ(form.NewEmail)).Exist(ctx)
+	if err != nil {
+		return err
+	}
+	if alreadyExistMail {
+		return customerrors.EmailAlreadyInUseError{}
+	}
+	return s.VerificationCodeSvc.Create(ctx, verificationcode.CreateForm{Username: loggedUser.Username,
+		Type: utils.EMAIL_TYPE, NewEmail: form.NewEmail}, nil)
+}
+
+// ConfirmEmailChange uses the code sent to the new address, revoking the rest of sessions. It returns
+// the new tokens for the current session
+func (s *AuthSvcImpl) ConfirmEmailChange(ctx context.Context, code string) (*LoginResult, error) {
+	loggedUser, err := s.DB.User.Get(ctx, ctx.Value(utils.UserIdKey).(uuid.UUID))
+	if err != nil {
+		return nil, err
+	}
+	err = s.VerificationCodeSvc.UseCode(ctx, verificationcode.UseForm{Username: loggedUser.Username,
+		Code: code, Type: utils.EMAIL_TYPE})
+	if err != nil {
+		return nil, err
+	}
+	updatedUser, err := s.DB.User.Get(ctx, loggedUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateLoginResult(updatedUser)
+}
+
+// RequestMagicLink sends a login link to the email and returns the nonce the browser has to keep to use it.
+// The nonce is returned even if there is no user with that email, to not reveal it
+func (s *AuthSvcImpl) RequestMagicLink(ctx context.Context, form verificationcode.MagicLinkForm) (string, error) {
+	if form.Email == "" {
+		return "", customerrors.EmptyFormFieldsError{}
+	}
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	form.Nonce = nonce
+	err = s.VerificationCodeSvc.CreateMagicLink(ctx, form)
+	if err != nil {
+		return "", err
+	}
+	return nonce, nil
+}
+
+func (s *AuthSvcImpl) MagicLinkLogin(ctx context.Context, form verificationcode.UseMagicLinkForm) (*LoginResult, error) {
+	if form.Token == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	loginUser, err := s.VerificationCodeSvc.UseMagicLink(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if loginUser.Disabled {
+		s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_FAILURE_EVENT, UserId: &loginUser.ID, Detail: "disabled account"})
+		return nil, customerrors.DisabledAccountError{}
+	}
+	s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_SUCCESS_EVENT, UserId: &loginUser.ID, Detail: "magic link"})
+	return GenerateLoginResult(loginUser)
+}
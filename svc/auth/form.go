@@ -9,4 +9,14 @@ type SignUpForm struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Locale   string `json:"locale"`
+}
+
+type ChangePasswordForm struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+type ChangeEmailForm struct {
+	NewEmail string `json:"newEmail" binding:"required"`
 }
@@ -0,0 +1,395 @@
+package deltasync
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"vocablo/conf"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/language"
+	"vocablo/ent/user"
+	"vocablo/ent/userword"
+	"vocablo/svc/quiz"
+	userwordsvc "vocablo/svc/userword"
+	"vocablo/tracing"
+	"vocablo/utils"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultPullLimit = 500
+	maxPullLimit     = 1000
+)
+
+const defaultTombstoneRetention = 90 * 24 * time.Hour
+
+// SyncSvc syncs the words of the user with the clients that work offline. The changes of every word are numbered
+// with the sync version of the user, so a client pulls the ones after the last it has seen
+type SyncSvc interface {
+	Pull(ctx context.Context, form PullForm) (*Changes, error)
+	Push(ctx context.Context, form PushForm) (*PushResult, error)
+	// Purge deletes the deleted words older than the retention, returning how many were deleted
+	Purge(ctx context.Context) (int, error)
+	SchedulePurge(ctx context.Context, interval time.Duration)
+	// Wait blocks until the scheduled purge stops, once its context is done
+	Wait()
+}
+
+type SyncSvcImpl struct {
+	DB      *ent.Client
+	Quiz    quiz.QuizSvc
+	workers sync.WaitGroup
+}
+
+// cursor is the position of a word in the order of the changes
+type cursor struct {
+	version int
+	id      uuid.UUID
+}
+
+func (c cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%s", c.version, c.id)))
+}
+
+func parseCursor(value string) (*cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, customerrors.InvalidSyncCursorError{}
+	}
+	version, id, found := strings.Cut(string(decoded), ".")
+	if !found {
+		return nil, customerrors.InvalidSyncCursorError{}
+	}
+	parsed := cursor{}
+	parsed.version, err = strconv.Atoi(version)
+	if err != nil {
+		return nil, customerrors.InvalidSyncCursorError{}
+	}
+	parsed.id, err = uuid.Parse(id)
+	if err != nil {
+		return nil, customerrors.InvalidSyncCursorError{}
+	}
+	return &parsed, nil
+}
+
+// Pull returns the changes of the words of the user after the cursor. Without one, all the words are returned as
+// created, and the deleted ones are skipped. A cursor from before the last purge of the deleted words is refused,
+// as the client would miss their deletion
+func (s *SyncSvcImpl) Pull(ctx context.Context, form PullForm) (changes *Changes, err error) {
+	ctx, span := tracing.Start(ctx, "SyncSvc.Pull")
+	defer tracing.End(span, &err)
+	limit := defaultPullLimit
+	if form.Limit > 0 {
+		limit = min(form.Limit, maxPullLimit)
+	}
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	query := s.DB.UserWord.Query().Where(userword.HasUserWith(user.IDEQ(userId)))
+	var since *cursor
+	if form.Since != "" {
+		since, err = parseCursor(form.Since)
+		if err != nil {
+			return nil, err
+		}
+		syncUser, err := s.DB.User.Get(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		if since.version < syncUser.PurgedSyncVersion {
+			return nil, customerrors.InvalidSyncCursorError{}
+		}
+		query = query.Where(userword.Or(userword.VersionGT(since.version),
+			userword.And(userword.VersionEQ(since.version), userword.IDGT(since.id))))
+	} else {
+		query = query.Where(userword.DeletedAtIsNil())
+	}
+	//We take one more to know if there are more changes
+	userWords, err := query.Order(userword.ByVersion(), userword.ByID()).Limit(limit + 1).WithLang().All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes = &Changes{Created: []*ent.UserWord{}, Updated: []*ent.UserWord{}, Deleted: []Tombstone{},
+		Cursor: form.Since}
+	if len(userWords) > limit {
+		changes.HasMore = true
+		userWords = userWords[:limit]
+	}
+	for _, userWord := range userWords {
+		switch {
+		case userWord.DeletedAt != nil:
+			changes.Deleted = append(changes.Deleted, Tombstone{ID: userWord.ID, Version: userWord.Version,
+				DeletedAt: *userWord.DeletedAt})
+		case since == nil || userWord.CreationVersion > since.version:
+			changes.Created = append(changes.Created, userWord)
+		default:
+			changes.Updated = append(changes.Updated, userWord)
+		}
+	}
+	if len(userWords) > 0 {
+		last := userWords[len(userWords)-1]
+		changes.Cursor = cursor{version: last.Version, id: last.ID}.String()
+	}
+	span.SetAttributes(attribute.Int("sync.changes", len(userWords)))
+	return changes, nil
+}
+
+// Push applies the changes made by a client while offline, and then the quizzes it answered. Every change is
+// resolved on its own: it is applied if the word wasn't changed since the version the client had, or if the client
+// changed it later than the server (last writer wins). Otherwise it is a conflict and the client gets the word of
+// the server. The changes the services refuse are rejected with the code of the error
+func (s *SyncSvcImpl) Push(ctx context.Context, form PushForm) (result *PushResult, err error) {
+	ctx, span := tracing.Start(ctx, "SyncSvc.Push", attribute.Int("sync.changes", len(form.Changes)),
+		attribute.Int("sync.quizzes", len(form.Quizzes)))
+	defer tracing.End(span, &err)
+	result = &PushResult{Changes: make([]ChangeResult, 0, len(form.Changes)),
+		Quizzes: make([]QuizResult, 0, len(form.Quizzes))}
+	for _, change := range form.Changes {
+		changeResult, err := s.applyChange(ctx, change)
+		if err != nil {
+			code, known := utils.ErrorCode(err)
+			if !known {
+				return nil, err
+			}
+			changeResult = &ChangeResult{ID: change.ID, Status: REJECTED_STATUS, ErrorCode: code}
+		}
+		result.Changes = append(result.Changes, *changeResult)
+	}
+	//The quizzes go after the changes, as they may be of the words created offline
+	for _, offlineAnswer := range form.Quizzes {
+		quizResult := QuizResult{ID: offlineAnswer.ID, Status: APPLIED_STATUS}
+		if !validAnswers(offlineAnswer.Questions) {
+			quizResult.Status = REJECTED_STATUS
+			quizResult.ErrorCode = customerrors.INVALID_REQUEST
+			result.Quizzes = append(result.Quizzes, quizResult)
+			continue
+		}
+		quizResult.Score, err = s.Quiz.AnswerOffline(ctx, offlineAnswer)
+		if err != nil {
+			code, known := utils.ErrorCode(err)
+			if !known {
+				return nil, err
+			}
+			quizResult.Status = REJECTED_STATUS
+			quizResult.ErrorCode = code
+		}
+		result.Quizzes = append(result.Quizzes, quizResult)
+	}
+	return result, nil
+}
+
+// applyChange resolves a change in its own transaction, so the rest are applied even if it is rejected
+func (s *SyncSvcImpl) applyChange(ctx context.Context, change Change) (*ChangeResult, error) {
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	clientTx, err := s.DB.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.resolveChange(ctx, clientTx, userId, change)
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	err = clientTx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	changeResult := &ChangeResult{ID: change.ID, Status: status}
+	changeResult.Word, err = s.DB.UserWord.Query().Where(userword.ID(change.ID)).WithLang().Only(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, err
+	}
+	return changeResult, nil
+}
+
+// resolveChange applies the change if it wins, returning its status
+func (s *SyncSvcImpl) resolveChange(ctx context.Context, clientTx *ent.Tx, userId uuid.UUID, change Change) (string, error) {
+	serverWord, err := clientTx.UserWord.Query().Where(userword.ID(change.ID)).WithUser().Only(ctx)
+	if ent.IsNotFound(err) {
+		//A word created and deleted offline has nothing to sync
+		if change.Deleted {
+			return APPLIED_STATUS, nil
+		}
+		return APPLIED_STATUS, s.createWord(ctx, clientTx, userId, change)
+	}
+	if err != nil {
+		return "", err
+	}
+	if serverWord.Edges.User.ID != userId {
+		return "", customerrors.NotAllowedResourceError{}
+	}
+	//A word deleted on both sides has nothing to resolve
+	if change.Deleted && serverWord.DeletedAt != nil {
+		return APPLIED_STATUS, nil
+	}
+	//The clock of the device can't set the change in the future
+	changedAt := change.UpdatedAt
+	if changedAt.After(time.Now()) {
+		changedAt = time.Now()
+	}
+	if serverWord.Version != change.BaseVersion && !changedAt.After(serverWord.UpdatedAt) {
+		return CONFLICT_STATUS, nil
+	}
+
+	version, err := userwordsvc.NextVersion(ctx, clientTx, userId)
+	if err != nil {
+		return "", err
+	}
+	updateBuilder := clientTx.UserWord.UpdateOne(serverWord).SetVersion(version)
+	if change.Deleted {
+		return APPLIED_STATUS, updateBuilder.SetDeletedAt(time.Now()).Exec(ctx)
+	}
+	//A word changed offline after being deleted on another device is restored
+	updateBuilder.ClearDeletedAt()
+	if change.Term != nil {
+		if *change.Term == "" {
+			return "", customerrors.EmptyFormFieldsError{}
+		}
+		updateBuilder.SetTerm(*change.Term)
+	}
+	if change.Definitions != nil {
+		if len(*change.Definitions) == 0 {
+			return "", customerrors.EmptyFormFieldsError{}
+		}
+		updateBuilder.SetDefinitions(*change.Definitions)
+	}
+	if change.Lang != nil {
+		lang, err := findLanguage(ctx, clientTx, *change.Lang)
+		if err != nil {
+			return "", err
+		}
+		updateBuilder.SetLang(lang)
+	}
+	return APPLIED_STATUS, updateBuilder.Exec(ctx)
+}
+
+// createWord creates a word added offline with the ID the client gave it
+func (s *SyncSvcImpl) createWord(ctx context.Context, clientTx *ent.Tx, userId uuid.UUID, change Change) error {
+	if change.Term == nil || *change.Term == "" || change.Lang == nil || change.Definitions == nil ||
+		len(*change.Definitions) == 0 {
+		return customerrors.EmptyFormFieldsError{}
+	}
+	lang, err := findLanguage(ctx, clientTx, *change.Lang)
+	if err != nil {
+		return err
+	}
+	version, err := userwordsvc.NextVersion(ctx, clientTx, userId)
+	if err != nil {
+		return err
+	}
+	return clientTx.UserWord.Create().SetID(change.ID).SetTerm(*change.Term).SetDefinitions(*change.Definitions).
+		SetLang(lang).SetUserID(userId).SetCreationVersion(version).SetVersion(version).Exec(ctx)
+}
+
+func findLanguage(ctx context.Context, clientTx *ent.Tx, code string) (*ent.Language, error) {
+	lang, err := clientTx.Language.Query().Where(language.CodeEQ(code)).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, customerrors.LanguageNotFoundError{Code: code}
+	}
+	return lang, err
+}
+
+// Purge deletes the deleted words older than the retention. The version of the last one purged of every user is
+// kept, to refuse the cursors that would miss them
+func (s *SyncSvcImpl) Purge(ctx context.Context) (int, error) {
+	retention := conf.Get().Sync.TombstoneRetention
+	if retention <= 0 {
+		retention = defaultTombstoneRetention
+	}
+	purgedBefore := time.Now().Add(-retention)
+	userIds, err := s.DB.User.Query().Where(user.HasUserWordsWith(userword.DeletedAtLT(purgedBefore))).IDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, userId := range userIds {
+		nPurged, err := s.purgeUser(ctx, userId, purgedBefore)
+		if err != nil {
+			return purged, err
+		}
+		purged += nPurged
+	}
+	return purged, nil
+}
+
+func (s *SyncSvcImpl) purgeUser(ctx context.Context, userId uuid.UUID, purgedBefore time.Time) (int, error) {
+	clientTx, err := s.DB.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tombstones := userword.And(userword.HasUserWith(user.IDEQ(userId)), userword.DeletedAtLT(purgedBefore))
+	last, err := clientTx.UserWord.Query().Where(tombstones).Order(userword.ByVersion(sql.OrderDesc())).First(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		if ent.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	err = clientTx.User.Update().Where(user.IDEQ(userId), user.PurgedSyncVersionLT(last.Version)).
+		SetPurgedSyncVersion(last.Version).Exec(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return 0, err
+	}
+	purged, err := clientTx.UserWord.Delete().Where(tombstones).Exec(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return 0, err
+	}
+	return purged, clientTx.Commit()
+}
+
+// SchedulePurge runs Purge every interval until the context is cancelled
+func (s *SyncSvcImpl) SchedulePurge(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				//A purge already started is finished on shutdown
+				purged, err := s.Purge(context.WithoutCancel(ctx))
+				if err != nil {
+					log.Error().Err(err).Msg("Error purging the deleted words")
+					continue
+				}
+				log.Info().Int("purged", purged).Msg("Deleted words purged")
+			}
+		}
+	}()
+}
+
+func (s *SyncSvcImpl) Wait() {
+	s.workers.Wait()
+}
+
+// validAnswers checks the positions of the questions of a quiz answered offline
+func validAnswers(questions []quiz.QuizQuestion) bool {
+	if len(questions) == 0 {
+		return false
+	}
+	for _, question := range questions {
+		nOptions := len(question.Options)
+		if question.CorrectOptionPos < 0 || question.CorrectOptionPos >= nOptions ||
+			(question.AnswerPos != nil && (*question.AnswerPos < 0 || *question.AnswerPos >= nOptions)) {
+			return false
+		}
+	}
+	return true
+}
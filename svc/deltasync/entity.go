@@ -0,0 +1,51 @@
+package deltasync
+
+import (
+	"time"
+	"vocablo/ent"
+
+	"github.com/google/uuid"
+)
+
+const (
+	APPLIED_STATUS  = "applied"
+	CONFLICT_STATUS = "conflict"
+	REJECTED_STATUS = "rejected"
+)
+
+// Changes are the changes of the words of the user since a cursor, in the order they were made
+type Changes struct {
+	Created []*ent.UserWord `json:"created"`
+	Updated []*ent.UserWord `json:"updated"`
+	Deleted []Tombstone     `json:"deleted"`
+	// Cursor is the one to pull the next changes from
+	Cursor  string `json:"cursor"`
+	HasMore bool   `json:"hasMore"`
+}
+
+type Tombstone struct {
+	ID        uuid.UUID `json:"id"`
+	Version   int       `json:"version"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type PushResult struct {
+	Changes []ChangeResult `json:"changes"`
+	Quizzes []QuizResult   `json:"quizzes"`
+}
+
+// ChangeResult is how a change was resolved. The word is the one on the server after it, so on a conflict the
+// client gets the version that won
+type ChangeResult struct {
+	ID        uuid.UUID     `json:"id"`
+	Status    string        `json:"status"`
+	Word      *ent.UserWord `json:"word,omitempty"`
+	ErrorCode string        `json:"errorCode,omitempty"`
+}
+
+type QuizResult struct {
+	ID        uuid.UUID `json:"id"`
+	Status    string    `json:"status"`
+	Score     int       `json:"score"`
+	ErrorCode string    `json:"errorCode,omitempty"`
+}
@@ -0,0 +1,32 @@
+package deltasync
+
+import (
+	"time"
+	"vocablo/schema"
+	"vocablo/svc/quiz"
+
+	"github.com/google/uuid"
+)
+
+type PullForm struct {
+	// Since is the cursor returned by the last pull, empty to pull all the words
+	Since string `form:"since"`
+	Limit int    `form:"limit"`
+}
+
+type PushForm struct {
+	Changes []Change             `json:"changes" binding:"max=500"`
+	Quizzes []quiz.OfflineAnswer `json:"quizzes" binding:"max=100"`
+}
+
+// Change is a change made to a word by a client while offline. BaseVersion is the version of the word the client
+// changed, and UpdatedAt when it did, to resolve the conflicts with the changes of the other devices
+type Change struct {
+	ID          uuid.UUID            `json:"id" binding:"required"`
+	BaseVersion int                  `json:"baseVersion"`
+	UpdatedAt   time.Time            `json:"updatedAt"`
+	Deleted     bool                 `json:"deleted"`
+	Term        *string              `json:"term"`
+	Lang        *string              `json:"lang"`
+	Definitions *[]schema.Definition `json:"definitions"`
+}
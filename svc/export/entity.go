@@ -0,0 +1,50 @@
+package export
+
+import (
+	"time"
+	"vocablo/schema"
+
+	"github.com/google/uuid"
+)
+
+// The entities below are the content of the exported files. They only include what belongs to the user
+// and never secrets like the password hash or the verification codes themselves.
+
+type Profile struct {
+	ID           uuid.UUID  `json:"id"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	Validated    bool       `json:"validated"`
+	CreationDate time.Time  `json:"creationDate"`
+	Identities   []Identity `json:"identities"`
+}
+
+type Identity struct {
+	Provider     string    `json:"provider"`
+	Email        string    `json:"email"`
+	CreationDate time.Time `json:"creationDate"`
+}
+
+type UserWord struct {
+	ID               uuid.UUID           `json:"id"`
+	Term             string              `json:"term"`
+	Lang             string              `json:"lang"`
+	Definitions      []schema.Definition `json:"definitions"`
+	LearningProgress float64             `json:"learningProgress"`
+	CreationDate     time.Time           `json:"creationDate"`
+}
+
+type QuizResult struct {
+	Score        int                 `json:"score"`
+	NQuestions   int                 `json:"nQuestions"`
+	NCorrect     int                 `json:"nCorrect"`
+	Answers      []schema.QuizAnswer `json:"answers"`
+	CreationDate time.Time           `json:"creationDate"`
+}
+
+type VerificationCode struct {
+	Type         string    `json:"type"`
+	Used         bool      `json:"used"`
+	CreationDate time.Time `json:"creationDate"`
+	ExpireDate   time.Time `json:"expireDate"`
+}
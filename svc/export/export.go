@@ -0,0 +1,291 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/dataexport"
+	"vocablo/ent/quizresult"
+	"vocablo/ent/user"
+	"vocablo/ent/userword"
+	"vocablo/ent/verificationcode"
+	"vocablo/svc/mail"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	PENDING_STATUS = "pending"
+	READY_STATUS   = "ready"
+	FAILED_STATUS  = "failed"
+)
+
+const downloadTokenTTL = 24 * time.Hour
+
+// generationTimeout is how long an export can be generating. After it, the server that was generating it is
+// considered crashed, and the export failed
+const generationTimeout = 30 * time.Minute
+
+type ExportSvc interface {
+	Request(ctx context.Context) (*ent.DataExport, error)
+	Get(ctx context.Context, id string) (*ent.DataExport, error)
+	Download(ctx context.Context, token string) (*ent.DataExport, error)
+	// Purge deletes the expired exports with their archives, and returns how many were deleted
+	Purge(ctx context.Context) (int, error)
+	// SchedulePurge runs Purge every interval until the context is cancelled. It doesn't run with a zero interval
+	SchedulePurge(ctx context.Context, interval time.Duration)
+	// Wait blocks until the exports being generated are finished, and the scheduled purge stops once its context
+	// is done
+	Wait()
+}
+
+type ExportSvcImpl struct {
+	DB      *ent.Client
+	Mail    mail.MailSvc
+	jobs    sync.WaitGroup
+	workers sync.WaitGroup
+}
+
+// Request starts the generation of an archive with the logged user data. The user is notified by email when
+// it is ready to download
+func (s *ExportSvcImpl) Request(ctx context.Context) (*ent.DataExport, error) {
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	//An export left pending by a crash would block the new ones forever
+	_, err := s.DB.DataExport.Update().Where(dataexport.HasUserWith(user.IDEQ(userId)), dataexport.StatusEQ(PENDING_STATUS),
+		dataexport.CreationDateLT(time.Now().Add(-generationTimeout))).SetStatus(FAILED_STATUS).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	//If there is already an export being generated, we don't start another one
+	pendingExport, err := s.DB.DataExport.Query().Where(dataexport.HasUserWith(user.IDEQ(userId)),
+		dataexport.StatusEQ(PENDING_STATUS)).First(ctx)
+	if err == nil {
+		return pendingExport, nil
+	}
+	if !ent.IsNotFound(err) {
+		return nil, err
+	}
+	//The expired archives are not needed anymore
+	_, err = s.DB.DataExport.Delete().Where(dataexport.HasUserWith(user.IDEQ(userId)),
+		dataexport.ExpireDateLT(time.Now())).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dataExport, err := s.DB.DataExport.Create().SetStatus(PENDING_STATUS).SetUserID(userId).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		//After the timeout the export is considered failed, so the job doesn't go on
+		jobCtx, cancel := context.WithTimeout(context.Background(), generationTimeout)
+		defer cancel()
+		s.generate(jobCtx, dataExport.ID, userId)
+	}()
+	return dataExport, nil
+}
+
+func (s *ExportSvcImpl) Purge(ctx context.Context) (int, error) {
+	return s.DB.DataExport.Delete().Where(dataexport.ExpireDateLT(time.Now())).Exec(ctx)
+}
+
+// SchedulePurge runs Purge every interval until the context is cancelled
+func (s *ExportSvcImpl) SchedulePurge(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				//A purge already started is finished on shutdown
+				purged, err := s.Purge(context.WithoutCancel(ctx))
+				if err != nil {
+					log.Error().Err(err).Msg("Error purging the data exports")
+					continue
+				}
+				log.Info().Int("purged", purged).Msg("Data exports purged")
+			}
+		}
+	}()
+}
+
+func (s *ExportSvcImpl) Wait() {
+	s.jobs.Wait()
+	s.workers.Wait()
+}
+
+func (s *ExportSvcImpl) generate(ctx context.Context, exportId uuid.UUID, userId uuid.UUID) {
+	archive, err := s.buildArchive(ctx, userId)
+	if err != nil {
+		log.Error().Err(err).Str("export", exportId.String()).Msg("Error generating the data export")
+		s.fail(exportId)
+		return
+	}
+	token, err := utils.GenerateRandomToken(64)
+	if err != nil {
+		log.Error().Err(err).Str("export", exportId.String()).Msg("Error generating the download token")
+		s.fail(exportId)
+		return
+	}
+	//The export is only ready if it wasn't failed for taking too long in the meantime
+	updated, err := s.DB.DataExport.Update().Where(dataexport.IDEQ(exportId), dataexport.StatusEQ(PENDING_STATUS)).
+		SetStatus(READY_STATUS).SetArchive(archive).SetDownloadToken(utils.HashToken(token)).
+		SetExpireDate(time.Now().Add(downloadTokenTTL)).Save(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("export", exportId.String()).Msg("Error saving the data export")
+		s.fail(exportId)
+		return
+	}
+	if updated == 0 {
+		log.Warn().Str("export", exportId.String()).Msg("The data export failed before it was generated")
+		return
+	}
+	exportUser, err := s.DB.User.Get(ctx, userId)
+	if err != nil {
+		log.Error().Err(err).Str("export", exportId.String()).Msg("Error getting the data export user")
+		return
+	}
+	err = s.Mail.Send(ctx, nil, exportUser.Email, exportUser.Locale, mail.DATA_EXPORT_READY_TEMPLATE,
+		mail.TemplateData{Username: exportUser.Username, Url: utils.FrontUrl() + "/export/" + token})
+	if err != nil {
+		log.Error().Err(err).Str("export", exportId.String()).Msg("Error queuing the data export email")
+	}
+}
+
+// fail marks the export being generated as failed. It doesn't use the context of the job, as it may have timed out
+func (s *ExportSvcImpl) fail(exportId uuid.UUID) {
+	err := s.DB.DataExport.Update().Where(dataexport.IDEQ(exportId), dataexport.StatusEQ(PENDING_STATUS)).
+		SetStatus(FAILED_STATUS).Exec(context.Background())
+	if err != nil {
+		log.Error().Err(err).Str("export", exportId.String()).Msg("Error marking the data export as failed")
+	}
+}
+
+// buildArchive creates a ZIP with a JSON file for each kind of user data
+func (s *ExportSvcImpl) buildArchive(ctx context.Context, userId uuid.UUID) ([]byte, error) {
+	exportUser, err := s.DB.User.Query().Where(user.IDEQ(userId)).WithIdentities().Only(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile := Profile{ID: exportUser.ID, Username: exportUser.Username, Email: exportUser.Email,
+		Validated: exportUser.Validated, CreationDate: exportUser.CreationDate, Identities: []Identity{}}
+	for _, identity := range exportUser.Edges.Identities {
+		profile.Identities = append(profile.Identities, Identity{Provider: identity.Provider, Email: identity.Email,
+			CreationDate: identity.CreationDate})
+	}
+
+	userWords, err := s.DB.UserWord.Query().Where(userword.HasUserWith(user.IDEQ(userId)), userword.DeletedAtIsNil()).WithLang().
+		Order(ent.Asc(userword.FieldCreationDate)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exportedWords := []UserWord{}
+	for _, userWord := range userWords {
+		exportedWord := UserWord{ID: userWord.ID, Term: userWord.Term, Definitions: userWord.Definitions,
+			LearningProgress: userWord.LearningProgress, CreationDate: userWord.CreationDate}
+		if userWord.Edges.Lang != nil {
+			exportedWord.Lang = userWord.Edges.Lang.Code
+		}
+		exportedWords = append(exportedWords, exportedWord)
+	}
+
+	quizResults, err := s.DB.QuizResult.Query().Where(quizresult.HasUserWith(user.IDEQ(userId))).
+		Order(ent.Asc(quizresult.FieldCreationDate)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exportedQuizzes := []QuizResult{}
+	for _, quizResult := range quizResults {
+		exportedQuizzes = append(exportedQuizzes, QuizResult{Score: quizResult.Score, NQuestions: quizResult.NQuestions,
+			NCorrect: quizResult.NCorrect, Answers: quizResult.Answers, CreationDate: quizResult.CreationDate})
+	}
+
+	codes, err := s.DB.VerificationCode.Query().Where(verificationcode.HasUserWith(user.IDEQ(userId))).
+		Order(ent.Asc(verificationcode.FieldCreationDate)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exportedCodes := []VerificationCode{}
+	for _, code := range codes {
+		exportedCodes = append(exportedCodes, VerificationCode{Type: code.Type, Used: code.Used,
+			CreationDate: code.CreationDate, ExpireDate: code.ExpireDate})
+	}
+
+	buffer := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buffer)
+	files := map[string]interface{}{
+		"profile.json":            profile,
+		"userwords.json":          exportedWords,
+		"quizzes.json":            exportedQuizzes,
+		"verification_codes.json": exportedCodes,
+	}
+	for name, content := range files {
+		file, err := zipWriter.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = zipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (s *ExportSvcImpl) Get(ctx context.Context, id string) (*ent.DataExport, error) {
+	uuidId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, customerrors.NotFoundError{Resource: "Data export"}
+	}
+	dataExport, err := s.DB.DataExport.Query().Where(dataexport.IDEQ(uuidId)).WithUser().Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.NotFoundError{Resource: "Data export"}
+		}
+		return nil, err
+	}
+	if dataExport.Edges.User.ID != ctx.Value(utils.UserIdKey).(uuid.UUID) {
+		return nil, customerrors.NotAllowedResourceError{}
+	}
+	return dataExport, nil
+}
+
+// Download returns the export with the archive for the token sent by email
+func (s *ExportSvcImpl) Download(ctx context.Context, token string) (*ent.DataExport, error) {
+	if token == "" {
+		return nil, customerrors.NotFoundError{Resource: "Data export"}
+	}
+	dataExport, err := s.DB.DataExport.Query().Where(dataexport.DownloadTokenEQ(utils.HashToken(token)),
+		dataexport.StatusEQ(READY_STATUS)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.NotFoundError{Resource: "Data export"}
+		}
+		return nil, err
+	}
+	if dataExport.ExpireDate == nil || dataExport.ExpireDate.Before(time.Now()) {
+		return nil, customerrors.ExpiredDownloadTokenError{}
+	}
+	return dataExport, nil
+}
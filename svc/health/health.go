@@ -0,0 +1,152 @@
+package health
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/ent/outbox"
+	"vocablo/svc/mail"
+	"vocablo/svc/word"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Statuses of the components and the overall one. A degraded component doesn't make the instance not ready,
+// as every instance shares it and taking them out of the load balancer wouldn't help
+const (
+	UP_STATUS            = "up"
+	DEGRADED_STATUS      = "degraded"
+	DOWN_STATUS          = "down"
+	SHUTTING_DOWN_STATUS = "shutting_down"
+)
+
+// UNREACHABLE_DETAIL is the detail of a component whose check failed
+const UNREACHABLE_DETAIL = "unreachable"
+
+// Names of the checked components
+const (
+	DB_COMPONENT         = "db"
+	MIGRATIONS_COMPONENT = "migrations"
+	OUTBOX_COMPONENT     = "outbox"
+	DICTIONARY_COMPONENT = "dictionary"
+)
+
+const defaultCheckTimeout = 2 * time.Second
+
+type Component struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components"`
+}
+
+// Ready checks if the instance can serve traffic
+func (r *Report) Ready() bool {
+	return r.Status == UP_STATUS || r.Status == DEGRADED_STATUS
+}
+
+type HealthSvc interface {
+	// Readiness checks the dependencies of the instance, and reports it as not ready once it is shutting down
+	Readiness(ctx context.Context) *Report
+	// ShutDown marks the instance as shutting down, so the load balancer stops sending it traffic
+	ShutDown()
+}
+
+type HealthSvcImpl struct {
+	DB   *ent.Client
+	Word word.WordSvc
+	// Migrated reports if the schema migration has finished
+	Migrated     func() bool
+	shuttingDown atomic.Bool
+}
+
+func (s *HealthSvcImpl) ShutDown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *HealthSvcImpl) Readiness(ctx context.Context) *Report {
+	timeout := conf.Get().Health.CheckTimeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	checks := map[string]func(ctx context.Context) Component{
+		DB_COMPONENT:         s.checkDB,
+		MIGRATIONS_COMPONENT: s.checkMigrations,
+		OUTBOX_COMPONENT:     s.checkOutbox,
+		DICTIONARY_COMPONENT: s.checkDictionary,
+	}
+	report := &Report{Status: UP_STATUS, Components: make(map[string]Component, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component := check(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = component
+		}()
+	}
+	wg.Wait()
+	for _, component := range report.Components {
+		if component.Status == DOWN_STATUS {
+			report.Status = DOWN_STATUS
+		} else if component.Status == DEGRADED_STATUS && report.Status == UP_STATUS {
+			report.Status = DEGRADED_STATUS
+		}
+	}
+	if s.shuttingDown.Load() {
+		report.Status = SHUTTING_DOWN_STATUS
+	}
+	return report
+}
+
+// checkDB runs a query, as the client doesn't expose the connection pool to ping it
+func (s *HealthSvcImpl) checkDB(ctx context.Context) Component {
+	_, err := s.DB.Language.Query().Exist(ctx)
+	if err != nil {
+		//The report is public, so the error of the driver is only logged
+		log.Error().Err(err).Msg("Health check of the database failed")
+		return Component{Status: DOWN_STATUS, Detail: UNREACHABLE_DETAIL}
+	}
+	return Component{Status: UP_STATUS}
+}
+
+func (s *HealthSvcImpl) checkMigrations(ctx context.Context) Component {
+	if s.Migrated == nil || !s.Migrated() {
+		return Component{Status: DOWN_STATUS, Detail: "the schema migration hasn't finished"}
+	}
+	return Component{Status: UP_STATUS}
+}
+
+func (s *HealthSvcImpl) checkOutbox(ctx context.Context) Component {
+	backlog, err := s.DB.Outbox.Query().Where(outbox.StatusIn(mail.PENDING_STATUS, mail.SENDING_STATUS)).Count(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Health check of the outbox failed")
+		return Component{Status: DOWN_STATUS, Detail: UNREACHABLE_DETAIL}
+	}
+	detail := strconv.Itoa(backlog) + " emails pending"
+	maxBacklog := conf.Get().Health.MaxOutboxBacklog
+	if maxBacklog > 0 && backlog > maxBacklog {
+		return Component{Status: DEGRADED_STATUS, Detail: detail}
+	}
+	return Component{Status: UP_STATUS, Detail: detail}
+}
+
+func (s *HealthSvcImpl) checkDictionary(ctx context.Context) Component {
+	state := s.Word.DictionaryState()
+	if state != word.CIRCUIT_CLOSED {
+		return Component{Status: DEGRADED_STATUS, Detail: "circuit " + state}
+	}
+	return Component{Status: UP_STATUS, Detail: "circuit " + state}
+}
@@ -0,0 +1,5 @@
+package language
+
+type CreateForm struct {
+	Code string `json:"code" binding:"required"`
+}
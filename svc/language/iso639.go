@@ -0,0 +1,213 @@
+package language
+
+const (
+	LTR_DIRECTION = "ltr"
+	RTL_DIRECTION = "rtl"
+)
+
+type LanguageInfo struct {
+	Name       string
+	NativeName string
+	Direction  string
+}
+
+func ltr(name string, nativeName string) LanguageInfo {
+	return LanguageInfo{Name: name, NativeName: nativeName, Direction: LTR_DIRECTION}
+}
+
+func rtl(name string, nativeName string) LanguageInfo {
+	return LanguageInfo{Name: name, NativeName: nativeName, Direction: RTL_DIRECTION}
+}
+
+// Lookup returns the metadata of an ISO 639-1 code
+func Lookup(code string) (LanguageInfo, bool) {
+	info, ok := iso639[code]
+	return info, ok
+}
+
+// iso639 has the metadata of every ISO 639-1 language
+var iso639 = map[string]LanguageInfo{
+	"aa": ltr("Afar", "Afaraf"),
+	"ab": ltr("Abkhaz", "аҧсуа бызшәа"),
+	"ae": ltr("Avestan", "avesta"),
+	"af": ltr("Afrikaans", "Afrikaans"),
+	"ak": ltr("Akan", "Akan"),
+	"am": ltr("Amharic", "አማርኛ"),
+	"an": ltr("Aragonese", "aragonés"),
+	"ar": rtl("Arabic", "العربية"),
+	"as": ltr("Assamese", "অসমীয়া"),
+	"av": ltr("Avaric", "авар мацӀ"),
+	"ay": ltr("Aymara", "aymar aru"),
+	"az": ltr("Azerbaijani", "azərbaycan dili"),
+	"ba": ltr("Bashkir", "башҡорт теле"),
+	"be": ltr("Belarusian", "беларуская мова"),
+	"bg": ltr("Bulgarian", "български език"),
+	"bi": ltr("Bislama", "Bislama"),
+	"bm": ltr("Bambara", "bamanankan"),
+	"bn": ltr("Bengali", "বাংলা"),
+	"bo": ltr("Tibetan", "བོད་ཡིག"),
+	"br": ltr("Breton", "brezhoneg"),
+	"bs": ltr("Bosnian", "bosanski jezik"),
+	"ca": ltr("Catalan", "català"),
+	"ce": ltr("Chechen", "нохчийн мотт"),
+	"ch": ltr("Chamorro", "Chamoru"),
+	"co": ltr("Corsican", "corsu"),
+	"cr": ltr("Cree", "ᓀᐦᐃᔭᐍᐏᐣ"),
+	"cs": ltr("Czech", "čeština"),
+	"cu": ltr("Old Church Slavonic", "ѩзыкъ словѣньскъ"),
+	"cv": ltr("Chuvash", "чӑваш чӗлхи"),
+	"cy": ltr("Welsh", "Cymraeg"),
+	"da": ltr("Danish", "dansk"),
+	"de": ltr("German", "Deutsch"),
+	"dv": rtl("Divehi", "ދިވެހި"),
+	"dz": ltr("Dzongkha", "རྫོང་ཁ"),
+	"ee": ltr("Ewe", "Eʋegbe"),
+	"el": ltr("Greek", "Ελληνικά"),
+	"en": ltr("English", "English"),
+	"eo": ltr("Esperanto", "Esperanto"),
+	"es": ltr("Spanish", "español"),
+	"et": ltr("Estonian", "eesti"),
+	"eu": ltr("Basque", "euskara"),
+	"fa": rtl("Persian", "فارسی"),
+	"ff": ltr("Fula", "Fulfulde"),
+	"fi": ltr("Finnish", "suomi"),
+	"fj": ltr("Fijian", "vosa Vakaviti"),
+	"fo": ltr("Faroese", "føroyskt"),
+	"fr": ltr("French", "français"),
+	"fy": ltr("Western Frisian", "Frysk"),
+	"ga": ltr("Irish", "Gaeilge"),
+	"gd": ltr("Scottish Gaelic", "Gàidhlig"),
+	"gl": ltr("Galician", "galego"),
+	"gn": ltr("Guaraní", "Avañe'ẽ"),
+	"gu": ltr("Gujarati", "ગુજરાતી"),
+	"gv": ltr("Manx", "Gaelg"),
+	"ha": ltr("Hausa", "Hausa"),
+	"he": rtl("Hebrew", "עברית"),
+	"hi": ltr("Hindi", "हिन्दी"),
+	"ho": ltr("Hiri Motu", "Hiri Motu"),
+	"hr": ltr("Croatian", "hrvatski jezik"),
+	"ht": ltr("Haitian", "Kreyòl ayisyen"),
+	"hu": ltr("Hungarian", "magyar"),
+	"hy": ltr("Armenian", "Հայերեն"),
+	"hz": ltr("Herero", "Otjiherero"),
+	"ia": ltr("Interlingua", "Interlingua"),
+	"id": ltr("Indonesian", "Bahasa Indonesia"),
+	"ie": ltr("Interlingue", "Interlingue"),
+	"ig": ltr("Igbo", "Asụsụ Igbo"),
+	"ii": ltr("Nuosu", "ꆈꌠ꒿ Nuosuhxop"),
+	"ik": ltr("Inupiaq", "Iñupiaq"),
+	"io": ltr("Ido", "Ido"),
+	"is": ltr("Icelandic", "Íslenska"),
+	"it": ltr("Italian", "italiano"),
+	"iu": ltr("Inuktitut", "ᐃᓄᒃᑎᑐᑦ"),
+	"ja": ltr("Japanese", "日本語"),
+	"jv": ltr("Javanese", "basa Jawa"),
+	"ka": ltr("Georgian", "ქართული"),
+	"kg": ltr("Kongo", "Kikongo"),
+	"ki": ltr("Kikuyu", "Gĩkũyũ"),
+	"kj": ltr("Kwanyama", "Kuanyama"),
+	"kk": ltr("Kazakh", "қазақ тілі"),
+	"kl": ltr("Kalaallisut", "kalaallisut"),
+	"km": ltr("Khmer", "ខ្មែរ"),
+	"kn": ltr("Kannada", "ಕನ್ನಡ"),
+	"ko": ltr("Korean", "한국어"),
+	"kr": ltr("Kanuri", "Kanuri"),
+	"ks": rtl("Kashmiri", "كٲشُر"),
+	"ku": ltr("Kurdish", "Kurdî"),
+	"kv": ltr("Komi", "коми кыв"),
+	"kw": ltr("Cornish", "Kernewek"),
+	"ky": ltr("Kyrgyz", "Кыргызча"),
+	"la": ltr("Latin", "latine"),
+	"lb": ltr("Luxembourgish", "Lëtzebuergesch"),
+	"lg": ltr("Ganda", "Luganda"),
+	"li": ltr("Limburgish", "Limburgs"),
+	"ln": ltr("Lingala", "Lingála"),
+	"lo": ltr("Lao", "ພາສາລາວ"),
+	"lt": ltr("Lithuanian", "lietuvių kalba"),
+	"lu": ltr("Luba-Katanga", "Kiluba"),
+	"lv": ltr("Latvian", "latviešu valoda"),
+	"mg": ltr("Malagasy", "fiteny malagasy"),
+	"mh": ltr("Marshallese", "Kajin M̧ajeļ"),
+	"mi": ltr("Māori", "te reo Māori"),
+	"mk": ltr("Macedonian", "македонски јазик"),
+	"ml": ltr("Malayalam", "മലയാളം"),
+	"mn": ltr("Mongolian", "Монгол хэл"),
+	"mr": ltr("Marathi", "मराठी"),
+	"ms": ltr("Malay", "Bahasa Melayu"),
+	"mt": ltr("Maltese", "Malti"),
+	"my": ltr("Burmese", "ဗမာစာ"),
+	"na": ltr("Nauru", "Dorerin Naoero"),
+	"nb": ltr("Norwegian Bokmål", "Norsk bokmål"),
+	"nd": ltr("Northern Ndebele", "isiNdebele"),
+	"ne": ltr("Nepali", "नेपाली"),
+	"ng": ltr("Ndonga", "Owambo"),
+	"nl": ltr("Dutch", "Nederlands"),
+	"nn": ltr("Norwegian Nynorsk", "Norsk nynorsk"),
+	"no": ltr("Norwegian", "Norsk"),
+	"nr": ltr("Southern Ndebele", "isiNdebele"),
+	"nv": ltr("Navajo", "Diné bizaad"),
+	"ny": ltr("Chichewa", "chiCheŵa"),
+	"oc": ltr("Occitan", "occitan"),
+	"oj": ltr("Ojibwe", "ᐊᓂᔑᓈᐯᒧᐎᓐ"),
+	"om": ltr("Oromo", "Afaan Oromoo"),
+	"or": ltr("Oriya", "ଓଡ଼ିଆ"),
+	"os": ltr("Ossetian", "ирон æвзаг"),
+	"pa": ltr("Panjabi", "ਪੰਜਾਬੀ"),
+	"pi": ltr("Pāli", "पाऴि"),
+	"pl": ltr("Polish", "polski"),
+	"ps": rtl("Pashto", "پښتو"),
+	"pt": ltr("Portuguese", "português"),
+	"qu": ltr("Quechua", "Runa Simi"),
+	"rm": ltr("Romansh", "rumantsch grischun"),
+	"rn": ltr("Kirundi", "Ikirundi"),
+	"ro": ltr("Romanian", "română"),
+	"ru": ltr("Russian", "русский"),
+	"rw": ltr("Kinyarwanda", "Ikinyarwanda"),
+	"sa": ltr("Sanskrit", "संस्कृतम्"),
+	"sc": ltr("Sardinian", "sardu"),
+	"sd": rtl("Sindhi", "سنڌي"),
+	"se": ltr("Northern Sami", "Davvisámegiella"),
+	"sg": ltr("Sango", "yângâ tî sängö"),
+	"si": ltr("Sinhala", "සිංහල"),
+	"sk": ltr("Slovak", "slovenčina"),
+	"sl": ltr("Slovenian", "slovenščina"),
+	"sm": ltr("Samoan", "gagana fa'a Samoa"),
+	"sn": ltr("Shona", "chiShona"),
+	"so": ltr("Somali", "Soomaaliga"),
+	"sq": ltr("Albanian", "Shqip"),
+	"sr": ltr("Serbian", "српски језик"),
+	"ss": ltr("Swati", "SiSwati"),
+	"st": ltr("Southern Sotho", "Sesotho"),
+	"su": ltr("Sundanese", "Basa Sunda"),
+	"sv": ltr("Swedish", "svenska"),
+	"sw": ltr("Swahili", "Kiswahili"),
+	"ta": ltr("Tamil", "தமிழ்"),
+	"te": ltr("Telugu", "తెలుగు"),
+	"tg": ltr("Tajik", "тоҷикӣ"),
+	"th": ltr("Thai", "ไทย"),
+	"ti": ltr("Tigrinya", "ትግርኛ"),
+	"tk": ltr("Turkmen", "Türkmençe"),
+	"tl": ltr("Tagalog", "Wikang Tagalog"),
+	"tn": ltr("Tswana", "Setswana"),
+	"to": ltr("Tonga", "faka Tonga"),
+	"tr": ltr("Turkish", "Türkçe"),
+	"ts": ltr("Tsonga", "Xitsonga"),
+	"tt": ltr("Tatar", "татар теле"),
+	"tw": ltr("Twi", "Twi"),
+	"ty": ltr("Tahitian", "Reo Tahiti"),
+	"ug": rtl("Uyghur", "ئۇيغۇرچە"),
+	"uk": ltr("Ukrainian", "українська"),
+	"ur": rtl("Urdu", "اردو"),
+	"uz": ltr("Uzbek", "Oʻzbek"),
+	"ve": ltr("Venda", "Tshivenḓa"),
+	"vi": ltr("Vietnamese", "Tiếng Việt"),
+	"vo": ltr("Volapük", "Volapük"),
+	"wa": ltr("Walloon", "walon"),
+	"wo": ltr("Wolof", "Wollof"),
+	"xh": ltr("Xhosa", "isiXhosa"),
+	"yi": rtl("Yiddish", "ייִדיש"),
+	"yo": ltr("Yoruba", "Yorùbá"),
+	"za": ltr("Zhuang", "Saɯ cueŋƅ"),
+	"zh": ltr("Chinese", "中文"),
+	"zu": ltr("Zulu", "isiZulu"),
+}
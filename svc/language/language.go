@@ -0,0 +1,91 @@
+package language
+
+import (
+	"context"
+	"strings"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/language"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type LanguageSvc interface {
+	Create(ctx context.Context, form CreateForm) (*ent.Language, error)
+	List(ctx context.Context) ([]*ent.Language, error)
+	Delete(ctx context.Context, id string) error
+	Seed(ctx context.Context, codes []string) error
+}
+
+type LanguageSvcImpl struct {
+	DB *ent.Client
+}
+
+func (s *LanguageSvcImpl) Create(ctx context.Context, form CreateForm) (*ent.Language, error) {
+	if form.Code == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	code := strings.ToLower(form.Code)
+	info, ok := Lookup(code)
+	if !ok {
+		return nil, customerrors.InvalidLanguageCodeError{Code: form.Code}
+	}
+	alreadyExist, err := s.DB.Language.Query().Where(language.CodeEQ(code)).Exist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyExist {
+		return nil, customerrors.LanguageAlreadyExistsError{}
+	}
+	return s.DB.Language.Create().SetCode(code).SetName(info.Name).SetNativeName(info.NativeName).
+		SetDirection(info.Direction).Save(ctx)
+}
+
+func (s *LanguageSvcImpl) List(ctx context.Context) ([]*ent.Language, error) {
+	return s.DB.Language.Query().Order(ent.Asc(language.FieldCode)).All(ctx)
+}
+
+// Delete removes the language and, in cascade, all the words and user words in it
+func (s *LanguageSvcImpl) Delete(ctx context.Context, id string) error {
+	uuidId, err := uuid.Parse(id)
+	if err != nil {
+		return customerrors.NotFoundError{Resource: "Language"}
+	}
+	err = s.DB.Language.DeleteOneID(uuidId).Exec(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return customerrors.NotFoundError{Resource: "Language"}
+		}
+		return err
+	}
+	return nil
+}
+
+// Seed makes sure the given languages exist with their metadata up to date. It can be run on every startup,
+// the languages already created are only updated
+func (s *LanguageSvcImpl) Seed(ctx context.Context, codes []string) error {
+	for _, code := range codes {
+		code = strings.ToLower(code)
+		info, ok := Lookup(code)
+		if !ok {
+			return customerrors.InvalidLanguageCodeError{Code: code}
+		}
+		existingLanguage, err := s.DB.Language.Query().Where(language.CodeEQ(code)).Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return err
+		}
+		if existingLanguage == nil {
+			err = s.DB.Language.Create().SetCode(code).SetName(info.Name).SetNativeName(info.NativeName).
+				SetDirection(info.Direction).Exec(ctx)
+		} else {
+			err = s.DB.Language.UpdateOne(existingLanguage).SetName(info.Name).SetNativeName(info.NativeName).
+				SetDirection(info.Direction).Exec(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	log.Info().Int("languages", len(codes)).Msg("Languages seeded")
+	return nil
+}
@@ -0,0 +1,7 @@
+package mail
+
+type OutboxSearchForm struct {
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+}
@@ -1,29 +1,100 @@
 package mail
 
 import (
-	"net/smtp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/utils"
 )
 
-type MailSvc interface {
-	SendMail(to string, subject string, message string) error
+// Message is an email ready to be sent, with the plain text and HTML alternatives of the body
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	// One-click unsubscribe link (RFC 8058), for the emails sent without being requested
+	ListUnsubscribe string `json:"listUnsubscribe,omitempty"`
 }
 
-type MailSvcImpl struct{}
+const (
+	SMTP_DRIVER = "smtp"
+	FILE_DRIVER = "file"
+	NOOP_DRIVER = "noop"
+)
+
+// Sender delivers the already rendered messages
+type Sender interface {
+	Send(message *Message) error
+}
 
-func (*MailSvcImpl) SendMail(to string, subject string, message string) error {
-	conf := conf.Get()
+// NewSender returns the sender of the configured driver
+func NewSender(mailConf conf.MailConf) (Sender, error) {
+	switch mailConf.Driver {
+	case SMTP_DRIVER, "":
+		return &SmtpSender{}, nil
+	case FILE_DRIVER:
+		return NewMaildirSender(conf.ResolvePath(mailConf.FileDir))
+	case NOOP_DRIVER:
+		return &NoopSender{}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %s", mailConf.Driver)
+	}
+}
 
-	auth := smtp.PlainAuth("", conf.Mail.User, conf.Mail.Pass, conf.Mail.SmtpHost)
-	toArray := []string{to}
-	msg := []byte("From: " + conf.Mail.User + "\r\n" +
-		"To: " + to + "\r\n" +
-		"Subject: " + subject + "\r\n\r\n" +
-		message + "\r\n")
+type MailSvc interface {
+	// Send renders the template in the locale of the recipient and queues it in the outbox. With a transaction,
+	// the email is only sent once it is committed
+	Send(ctx context.Context, tx *ent.Tx, to string, locale string, name string, data TemplateData) error
+	Render(name string, locale string, data TemplateData) (*Message, error)
+	// Preview renders the template with sample data
+	Preview(name string, locale string) (*Message, error)
+	Templates() []TemplateInfo
+	// Deliver sends the queued emails that are due and returns how many were sent
+	Deliver(ctx context.Context) (int, error)
+	ScheduleDelivery(ctx context.Context, interval time.Duration)
+	// Wait blocks until the scheduled delivery stops, once its context is done
+	Wait()
+	SearchOutbox(ctx context.Context, form OutboxSearchForm) (*utils.Page[*ent.Outbox], error)
+}
 
-	err := smtp.SendMail(conf.Mail.SmtpHost+":"+conf.Mail.SmtpPort, auth, conf.Mail.User, toArray, msg)
+type MailSvcImpl struct {
+	DB            *ent.Client
+	Sender        Sender
+	defaultLocale string
+	// Templates by locale and name
+	templates map[string]map[string]mailTemplate
+	// Wakes up the delivery when an email is queued
+	queued  chan struct{}
+	workers sync.WaitGroup
+}
+
+func NewMailSvc(client *ent.Client, sender Sender, templatesDir string, defaultLocale string) (*MailSvcImpl, error) {
+	templates, err := loadTemplates(templatesDir, defaultLocale)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return &MailSvcImpl{DB: client, Sender: sender, defaultLocale: defaultLocale, templates: templates,
+		queued: make(chan struct{}, 1)}, nil
+}
+
+// messageDomain returns the domain used in the Message-ID, the one of the sender address
+func messageDomain(from string) string {
+	at := strings.LastIndex(from, "@")
+	if at == -1 || at == len(from)-1 {
+		return "localhost"
+	}
+	return from[at+1:]
+}
+
+func randomId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Bytes builds the RFC 5322 message as multipart/alternative, with the plain text part first so the clients
+// prefer the HTML one. The headers with non ASCII characters are encoded as RFC 2047 words
+func (m *Message) Bytes(fromName string, fromAddress string, domain string) ([]byte, error) {
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(fromAddress, "\r\n") {
+		return nil, errors.New("invalid mail address")
+	}
+	if strings.ContainsAny(m.ListUnsubscribe, "\r\n<>") {
+		return nil, errors.New("invalid unsubscribe link")
+	}
+	to, err := netmail.ParseAddress(m.To)
+	if err != nil {
+		return nil, err
+	}
+	from := netmail.Address{Name: fromName, Address: fromAddress}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", randomId(), domain)
+	if m.ListUnsubscribe != "" {
+		//With the Post header the clients unsubscribe with a POST to the link, not by opening it
+		fmt.Fprintf(&buf, "List-Unsubscribe: <%s>\r\n", m.ListUnsubscribe)
+		fmt.Fprintf(&buf, "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+
+	err = writePart(writer, "text/plain; charset=utf-8", m.Text)
+	if err != nil {
+		return nil, err
+	}
+	if m.HTML != "" {
+		err = writePart(writer, "text/html; charset=utf-8", m.HTML)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writePart(writer *multipart.Writer, contentType string, body string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(part)
+	_, err = encoder.Write([]byte(body))
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
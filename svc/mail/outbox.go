@@ -0,0 +1,231 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/ent/outbox"
+	"vocablo/utils"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	PENDING_STATUS = "pending"
+	SENDING_STATUS = "sending"
+	SENT_STATUS    = "sent"
+	DEAD_STATUS    = "dead"
+)
+
+const deliveryBatchSize = 50
+
+// sendingLease is how long a worker owns an email while sending it. If it crashes, the email is retried after it
+const sendingLease = 5 * time.Minute
+
+const (
+	defaultMaxAttempts = 8
+	defaultMinBackoff  = 30 * time.Second
+	defaultMaxBackoff  = time.Hour
+	defaultRetention   = 7 * 24 * time.Hour
+)
+
+func outboxConf() conf.OutboxConf {
+	outboxConf := conf.Get().Mail.Outbox
+	if outboxConf.MaxAttempts <= 0 {
+		outboxConf.MaxAttempts = defaultMaxAttempts
+	}
+	if outboxConf.MinBackoff <= 0 {
+		outboxConf.MinBackoff = defaultMinBackoff
+	}
+	if outboxConf.MaxBackoff < outboxConf.MinBackoff {
+		outboxConf.MaxBackoff = max(defaultMaxBackoff, outboxConf.MinBackoff)
+	}
+	if outboxConf.Retention <= 0 {
+		outboxConf.Retention = defaultRetention
+	}
+	return outboxConf
+}
+
+// backoff returns the wait before the next attempt, doubling it after every failed one
+func backoff(outboxConf conf.OutboxConf, attempts int) time.Duration {
+	wait := outboxConf.MinBackoff
+	for i := 1; i < attempts && wait < outboxConf.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, outboxConf.MaxBackoff)
+}
+
+func (s *MailSvcImpl) Send(ctx context.Context, tx *ent.Tx, to string, locale string, name string, data TemplateData) error {
+	if to == "" {
+		return errors.New("no mail recipient")
+	}
+	message, err := s.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+	outboxClient := s.DB.Outbox
+	if tx != nil {
+		outboxClient = tx.Outbox
+	}
+	err = outboxClient.Create().SetTo(to).SetTemplate(name).SetSubject(message.Subject).SetText(message.Text).
+		SetHTML(message.HTML).SetListUnsubscribe(message.ListUnsubscribe).SetStatus(PENDING_STATUS).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		s.wakeUp()
+		return nil
+	}
+	tx.OnCommit(func(next ent.Committer) ent.Committer {
+		return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+			err := next.Commit(ctx, tx)
+			if err == nil {
+				s.wakeUp()
+			}
+			return err
+		})
+	})
+	return nil
+}
+
+func (s *MailSvcImpl) wakeUp() {
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+}
+
+func (s *MailSvcImpl) Deliver(ctx context.Context) (int, error) {
+	outboxConf := outboxConf()
+	now := time.Now()
+	//The sending ones are those whose worker didn't finish before its lease expired
+	due, err := s.DB.Outbox.Query().Where(outbox.StatusIn(PENDING_STATUS, SENDING_STATUS), outbox.NextAttemptDateLTE(now)).
+		Order(outbox.ByNextAttemptDate()).Limit(deliveryBatchSize).All(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, queued := range due {
+		//The email is claimed with a conditional update, so two workers never send it at the same time
+		claimed, err := s.DB.Outbox.Update().Where(outbox.IDEQ(queued.ID), outbox.StatusIn(PENDING_STATUS, SENDING_STATUS),
+			outbox.NextAttemptDateLTE(now)).SetStatus(SENDING_STATUS).SetNextAttemptDate(now.Add(sendingLease)).Save(ctx)
+		if err != nil {
+			return sent, err
+		}
+		if claimed == 0 {
+			continue
+		}
+		attempts := queued.Attempts + 1
+		err = s.Sender.Send(&Message{To: queued.To, Subject: queued.Subject, Text: queued.Text, HTML: queued.HTML,
+			ListUnsubscribe: queued.ListUnsubscribe})
+		if err == nil {
+			sent++
+			//The body may have codes and links, so it isn't kept once it is delivered
+			err = s.DB.Outbox.UpdateOneID(queued.ID).SetStatus(SENT_STATUS).SetAttempts(attempts).SetSentDate(time.Now()).
+				SetText("").SetHTML("").SetListUnsubscribe("").Exec(ctx)
+			if err != nil {
+				log.Error().Err(err).Str("outbox", queued.ID.String()).Msg("Error marking the email as sent")
+			}
+			continue
+		}
+		update := s.DB.Outbox.UpdateOneID(queued.ID).SetAttempts(attempts).SetLastError(err.Error())
+		if attempts >= outboxConf.MaxAttempts {
+			log.Error().Err(err).Str("outbox", queued.ID.String()).Str("template", queued.Template).Msg("Email moved to dead letters")
+			update.SetStatus(DEAD_STATUS).SetText("").SetHTML("").SetListUnsubscribe("")
+		} else {
+			log.Warn().Err(err).Str("outbox", queued.ID.String()).Int("attempts", attempts).Msg("Error sending email, it will be retried")
+			update.SetStatus(PENDING_STATUS).SetNextAttemptDate(time.Now().Add(backoff(outboxConf, attempts)))
+		}
+		err = update.Exec(ctx)
+		if err != nil {
+			log.Error().Err(err).Str("outbox", queued.ID.String()).Msg("Error saving the email attempt")
+		}
+	}
+	return sent, nil
+}
+
+// purgeSent deletes the sent emails older than the retention
+func (s *MailSvcImpl) purgeSent(ctx context.Context) (int, error) {
+	return s.DB.Outbox.Delete().Where(outbox.StatusEQ(SENT_STATUS), outbox.SentDateLT(time.Now().Add(-outboxConf().Retention))).Exec(ctx)
+}
+
+// ScheduleDelivery delivers the queued emails every interval, and as soon as one is queued, until the context is done
+func (s *MailSvcImpl) ScheduleDelivery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		//A delivery already started is finished on shutdown, so no email is left claimed
+		jobCtx := context.WithoutCancel(ctx)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := s.purgeSent(jobCtx)
+				if err != nil {
+					log.Error().Err(err).Msg("Error purging the sent emails")
+				} else if purged > 0 {
+					log.Info().Int("purged", purged).Msg("Sent emails purged")
+				}
+			case <-s.queued:
+			}
+			_, err := s.Deliver(jobCtx)
+			if err != nil {
+				log.Error().Err(err).Msg("Error delivering the queued emails")
+			}
+		}
+	}()
+}
+
+func (s *MailSvcImpl) Wait() {
+	s.workers.Wait()
+}
+
+// SearchOutbox returns the queued emails, optionally filtered by status, the newest first
+func (s *MailSvcImpl) SearchOutbox(ctx context.Context, form OutboxSearchForm) (*utils.Page[*ent.Outbox], error) {
+	if form.Page <= 0 {
+		form.Page = 0
+	}
+	if form.PageSize <= 0 {
+		form.PageSize = 10
+	}
+	query := s.DB.Outbox.Query()
+	if form.Status != "" {
+		query.Where(outbox.StatusEQ(form.Status))
+	}
+	total, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := utils.Page[*ent.Outbox]{PageNumber: form.Page, NElements: total}
+	if total > (form.Page+1)*form.PageSize {
+		page.HasNext = true
+	}
+	//The bodies are never listed, as they may have codes and links
+	messages, err := query.Offset(form.Page * form.PageSize).Limit(form.PageSize).
+		Order(outbox.ByCreationDate(sql.OrderDesc())).Select(listedOutboxColumns()...).OutboxQuery.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page.Content = messages
+	return &page, nil
+}
+
+// listedOutboxColumns are all the columns of the outbox but the bodies and the unsubscribe link
+func listedOutboxColumns() []string {
+	columns := []string{}
+	for _, column := range outbox.Columns {
+		if column != outbox.FieldText && column != outbox.FieldHTML && column != outbox.FieldListUnsubscribe {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"time"
+	"vocablo/conf"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultSmtpTimeout = 30 * time.Second
+
+type SmtpSender struct{}
+
+// Send delivers the message like smtp.SendMail, but with a deadline on the connection, so a server that stops
+// answering doesn't block the delivery worker
+func (*SmtpSender) Send(message *Message) error {
+	conf := conf.Get()
+
+	msg, err := message.Bytes(conf.Mail.FromName, conf.Mail.User, messageDomain(conf.Mail.User))
+	if err != nil {
+		return err
+	}
+	timeout := conf.Mail.SmtpTimeout
+	if timeout <= 0 {
+		timeout = defaultSmtpTimeout
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.Dial("tcp", net.JoinHostPort(conf.Mail.SmtpHost, conf.Mail.SmtpPort))
+	if err != nil {
+		return err
+	}
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, conf.Mail.SmtpHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: conf.Mail.SmtpHost})
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Auth(smtp.PlainAuth("", conf.Mail.User, conf.Mail.Pass, conf.Mail.SmtpHost))
+	if err != nil {
+		return err
+	}
+	err = client.Mail(conf.Mail.User)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(message.To)
+	if err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// MaildirSender writes the messages to a maildir, so they can be read with any mail client during development
+type MaildirSender struct {
+	Dir string
+}
+
+func NewMaildirSender(dir string) (*MaildirSender, error) {
+	for _, subdir := range []string{"tmp", "new", "cur"} {
+		err := os.MkdirAll(filepath.Join(dir, subdir), 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &MaildirSender{Dir: dir}, nil
+}
+
+func (s *MaildirSender) Send(message *Message) error {
+	conf := conf.Get()
+	msg, err := message.Bytes(conf.Mail.FromName, conf.Mail.User, messageDomain(conf.Mail.User))
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	//The message is written in tmp and then moved to new, so the readers never see it half written
+	name := fmt.Sprintf("%d.%s.%s", time.Now().UnixNano(), randomId(), hostname)
+	tmpPath := filepath.Join(s.Dir, "tmp", name)
+	err = os.WriteFile(tmpPath, msg, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(s.Dir, "new", name))
+}
+
+// NoopSender discards the messages
+type NoopSender struct{}
+
+func (*NoopSender) Send(message *Message) error {
+	log.Debug().Str("to", message.To).Str("subject", message.Subject).Msg("Discarding email")
+	return nil
+}
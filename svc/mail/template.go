@@ -0,0 +1,200 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"vocablo/customerrors"
+	"vocablo/utils"
+)
+
+// Names of the templates, the verification code ones match the code types
+const (
+	VALIDATE_ACCOUNT_TEMPLATE  = "validate_account"
+	RESET_PASSWORD_TEMPLATE    = "reset_password"
+	CHANGE_EMAIL_TEMPLATE      = "change_email"
+	PASSWORD_CHANGED_TEMPLATE  = "password_changed"
+	EMAIL_CHANGED_TEMPLATE     = "email_changed"
+	DATA_EXPORT_READY_TEMPLATE = "data_export_ready"
+	MAGIC_LINK_TEMPLATE        = "magic_link"
+	STUDY_REMINDER_TEMPLATE    = "study_reminder"
+	WEEKLY_DIGEST_TEMPLATE     = "weekly_digest"
+)
+
+// requiredTemplates must exist at least in the default locale
+var requiredTemplates = []string{VALIDATE_ACCOUNT_TEMPLATE, RESET_PASSWORD_TEMPLATE, CHANGE_EMAIL_TEMPLATE,
+	PASSWORD_CHANGED_TEMPLATE, EMAIL_CHANGED_TEMPLATE, DATA_EXPORT_READY_TEMPLATE, MAGIC_LINK_TEMPLATE,
+	STUDY_REMINDER_TEMPLATE, WEEKLY_DIGEST_TEMPLATE}
+
+const layoutFile = "layout.html"
+
+// templateFuncs are the functions available in both kinds of templates
+var templateFuncs = map[string]any{
+	"join": strings.Join,
+}
+
+// TemplateData has the values available in the templates. FrontUrl is always filled when rendering
+type TemplateData struct {
+	Username string
+	Code     string
+	Url      string
+	NewEmail string
+	FrontUrl string
+	// Link to stop receiving the kind of email, for the ones sent without being requested
+	UnsubscribeUrl string
+	// Link the email clients POST to, to unsubscribe with one click. It goes in the headers, not in the body
+	OneClickUnsubscribeUrl string
+	DueWords               int
+	Streak                 int
+	Digest                 *DigestData
+}
+
+// DigestData is the summary of the week of the weekly digest
+type DigestData struct {
+	WordsAdded   int
+	WordsLearned int
+	// Some of the words learned, the list is capped
+	LearnedWords []string
+	Quizzes      int
+	// Percentage of correct answers in the quizzes of the week
+	Accuracy int
+	// Words of the week with the most wrong answers
+	HardestWords []string
+	// Words not learned yet suggested to practise next
+	FocusWords []string
+	// Totals of the whole vocabulary, as in the user progress
+	TotalWords   int
+	LearnedTotal int
+}
+
+type TemplateInfo struct {
+	Name    string   `json:"name"`
+	Locales []string `json:"locales"`
+}
+
+// mailTemplate is the pair of templates of a message type in a locale. The text one defines the subject
+// in a "subject" block, and the HTML one the "content" block of the layout
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// loadTemplates reads the templates of the directory, which has a shared layout.html and a folder by locale
+// with a name.txt and name.html pair by message type
+func loadTemplates(dir string, defaultLocale string) (map[string]map[string]mailTemplate, error) {
+	templates := map[string]map[string]mailTemplate{}
+	layout, err := htmltemplate.New(layoutFile).Funcs(templateFuncs).ParseFiles(filepath.Join(dir, layoutFile))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		textFiles, err := filepath.Glob(filepath.Join(dir, locale, "*.txt"))
+		if err != nil {
+			return nil, err
+		}
+		templates[locale] = map[string]mailTemplate{}
+		for _, textFile := range textFiles {
+			name := strings.TrimSuffix(filepath.Base(textFile), ".txt")
+			text, err := texttemplate.New(filepath.Base(textFile)).Funcs(templateFuncs).ParseFiles(textFile)
+			if err != nil {
+				return nil, err
+			}
+			if text.Lookup("subject") == nil {
+				return nil, fmt.Errorf("mail template %s/%s has no subject", locale, name)
+			}
+			htmlLayout, err := layout.Clone()
+			if err != nil {
+				return nil, err
+			}
+			html, err := htmlLayout.ParseFiles(filepath.Join(dir, locale, name+".html"))
+			if err != nil {
+				return nil, err
+			}
+			templates[locale][name] = mailTemplate{text: text, html: html}
+		}
+	}
+	for _, name := range requiredTemplates {
+		if _, ok := templates[defaultLocale][name]; !ok {
+			return nil, fmt.Errorf("mail template %s not found for the default locale %s", name, defaultLocale)
+		}
+	}
+	return templates, nil
+}
+
+// lookup returns the template in the requested locale, then in its base language and then in the default one
+func (s *MailSvcImpl) lookup(name string, locale string) (mailTemplate, bool) {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	base, _, _ := strings.Cut(locale, "-")
+	for _, candidate := range []string{locale, base, s.defaultLocale} {
+		if tmpl, ok := s.templates[candidate][name]; ok {
+			return tmpl, true
+		}
+	}
+	return mailTemplate{}, false
+}
+
+func (s *MailSvcImpl) Render(name string, locale string, data TemplateData) (*Message, error) {
+	tmpl, ok := s.lookup(name, locale)
+	if !ok {
+		return nil, customerrors.NotFoundError{Resource: "Mail template: " + name}
+	}
+	data.FrontUrl = utils.FrontUrl()
+	var subject, text, html bytes.Buffer
+	err := tmpl.text.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return nil, err
+	}
+	err = tmpl.text.Execute(&text, data)
+	if err != nil {
+		return nil, err
+	}
+	message := &Message{Subject: strings.Join(strings.Fields(subject.String()), " "), Text: strings.TrimSpace(text.String())}
+	err = tmpl.html.ExecuteTemplate(&html, layoutFile, struct {
+		TemplateData
+		Subject string
+	}{data, message.Subject})
+	if err != nil {
+		return nil, err
+	}
+	message.HTML = html.String()
+	message.ListUnsubscribe = data.OneClickUnsubscribeUrl
+	return message, nil
+}
+
+func (s *MailSvcImpl) Preview(name string, locale string) (*Message, error) {
+	return s.Render(name, locale, TemplateData{Username: "jane", Code: "123456", Url: utils.FrontUrl() + "/preview/sample-token",
+		NewEmail: "jane.new@example.com", UnsubscribeUrl: utils.FrontUrl() + "/unsubscribe/sample-token",
+		OneClickUnsubscribeUrl: utils.ApiUrl() + "/api/public/unsubscribe/sample-token", DueWords: 12, Streak: 5,
+		Digest: &DigestData{WordsAdded: 8, WordsLearned: 2, LearnedWords: []string{"serendipity", "ephemeral"}, Quizzes: 6, Accuracy: 78,
+			HardestWords: []string{"ubiquitous", "quintessential"}, FocusWords: []string{"ubiquitous", "quintessential", "mellifluous"},
+			TotalWords: 120, LearnedTotal: 45}})
+}
+
+func (s *MailSvcImpl) Templates() []TemplateInfo {
+	locales := map[string][]string{}
+	for locale, templates := range s.templates {
+		for name := range templates {
+			locales[name] = append(locales[name], locale)
+		}
+	}
+	templates := make([]TemplateInfo, 0, len(locales))
+	for name, nameLocales := range locales {
+		sort.Strings(nameLocales)
+		templates = append(templates, TemplateInfo{Name: name, Locales: nameLocales})
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
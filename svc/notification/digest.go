@@ -0,0 +1,254 @@
+package notification
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"vocablo/conf"
+	"vocablo/ent"
+	"vocablo/ent/quizresult"
+	"vocablo/ent/user"
+	"vocablo/ent/userword"
+	"vocablo/svc/mail"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const digestPeriod = 7 * 24 * time.Hour
+
+// Maximum number of words listed in every section of the digest
+const digestListSize = 5
+
+const maxLearnedWords = 10
+
+// digestWeekday returns the configured day of the digest, Monday when it is not valid
+func digestWeekday() time.Weekday {
+	configured := strings.ToLower(conf.Get().Notifications.DigestWeekday)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.ToLower(weekday.String()) == configured {
+			return weekday
+		}
+	}
+	return time.Monday
+}
+
+// digestWeekStart returns the start of the last digest weekday in the timezone of the user, which is when
+// the digest of the current week becomes due
+func digestWeekStart(digestUser *ent.User, now time.Time) time.Time {
+	loc, err := time.LoadLocation(digestUser.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := localDay(now, loc)
+	daysSince := (int(today.Weekday()) - int(digestWeekday()) + 7) % 7
+	return today.AddDate(0, 0, -daysSince)
+}
+
+func (s *NotificationSvcImpl) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	return s.sendBatched(ctx, now, user.DigestEnabledEQ(true), s.digest, "Error sending the weekly digest")
+}
+
+func (s *NotificationSvcImpl) SendDigest(ctx context.Context, userId uuid.UUID, now time.Time) error {
+	digestUser, err := s.DB.User.Get(ctx, userId)
+	if err != nil {
+		return err
+	}
+	data, err := s.digestData(ctx, digestUser.ID, now)
+	if err != nil {
+		return err
+	}
+	return s.sendDigest(ctx, digestUser, data)
+}
+
+// digest sends the digest to the user if it is due this week and there was some activity to summarise
+func (s *NotificationSvcImpl) digest(ctx context.Context, digestUser *ent.User, now time.Time) (bool, error) {
+	weekStart := digestWeekStart(digestUser, now)
+	if digestUser.LastDigestDate != nil && !digestUser.LastDigestDate.Before(weekStart) {
+		return false, nil
+	}
+	//The week is claimed with a conditional update, so the digest is only sent once even with several instances
+	claimed, err := s.DB.User.Update().Where(user.IDEQ(digestUser.ID),
+		user.Or(user.LastDigestDateIsNil(), user.LastDigestDateLT(weekStart))).SetLastDigestDate(now).Save(ctx)
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+	data, err := s.digestData(ctx, digestUser.ID, now)
+	if err != nil {
+		return false, err
+	}
+	if data.WordsAdded == 0 && data.WordsLearned == 0 && data.Quizzes == 0 {
+		return false, nil
+	}
+	err = s.sendDigest(ctx, digestUser, data)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *NotificationSvcImpl) sendDigest(ctx context.Context, digestUser *ent.User, data *mail.DigestData) error {
+	unsubscribeUrl, oneClickUrl, err := unsubscribeUrls(digestUser.ID, DIGEST_LIST)
+	if err != nil {
+		return err
+	}
+	return s.Mail.Send(ctx, nil, digestUser.Email, digestUser.Locale, mail.WEEKLY_DIGEST_TEMPLATE, mail.TemplateData{
+		Username: digestUser.Username, Url: utils.FrontUrl(), UnsubscribeUrl: unsubscribeUrl, OneClickUnsubscribeUrl: oneClickUrl,
+		Digest: data})
+}
+
+// wordStats are the answers of a word in the quizzes of the week
+type wordStats struct {
+	term   string
+	misses int
+	total  int
+}
+
+// digestData summarises the activity of the user in the week before now
+func (s *NotificationSvcImpl) digestData(ctx context.Context, userId uuid.UUID, now time.Time) (*mail.DigestData, error) {
+	since := now.Add(-digestPeriod)
+	ofUser := userword.And(userword.HasUserWith(user.IDEQ(userId)), userword.DeletedAtIsNil())
+	data := &mail.DigestData{}
+	var err error
+	data.TotalWords, err = s.DB.UserWord.Query().Where(ofUser).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data.LearnedTotal, err = s.DB.UserWord.Query().Where(ofUser, userword.LearningProgressGTE(100)).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data.WordsAdded, err = s.DB.UserWord.Query().Where(ofUser, userword.CreationDateGT(since), userword.CreationDateLTE(now)).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	learned, err := s.DB.UserWord.Query().Where(ofUser, userword.LearnedDateGT(since), userword.LearnedDateLTE(now)).
+		Order(ent.Asc(userword.FieldLearnedDate)).Select(userword.FieldTerm).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data.WordsLearned = len(learned)
+	for i := 0; i < len(learned) && i < maxLearnedWords; i++ {
+		data.LearnedWords = append(data.LearnedWords, learned[i].Term)
+	}
+
+	quizzes, err := s.DB.QuizResult.Query().Where(quizresult.HasUserWith(user.IDEQ(userId)),
+		quizresult.CreationDateGT(since), quizresult.CreationDateLTE(now)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data.Quizzes = len(quizzes)
+	nQuestions, nCorrect := 0, 0
+	stats := map[uuid.UUID]*wordStats{}
+	for _, quiz := range quizzes {
+		nQuestions += quiz.NQuestions
+		nCorrect += quiz.NCorrect
+		for _, answer := range quiz.Answers {
+			wordStat, ok := stats[answer.UserWordID]
+			if !ok {
+				wordStat = &wordStats{term: answer.Term}
+				stats[answer.UserWordID] = wordStat
+			}
+			wordStat.total++
+			if !answer.Correct {
+				wordStat.misses++
+			}
+		}
+	}
+	if nQuestions > 0 {
+		data.Accuracy = nCorrect * 100 / nQuestions
+	}
+
+	//The hardest words are the most failed ones, and the failure rate breaks the ties
+	hardest := make([]uuid.UUID, 0, len(stats))
+	for id, wordStat := range stats {
+		if wordStat.misses > 0 {
+			hardest = append(hardest, id)
+		}
+	}
+	sort.Slice(hardest, func(i, j int) bool {
+		a, b := stats[hardest[i]], stats[hardest[j]]
+		if a.misses != b.misses {
+			return a.misses > b.misses
+		}
+		if a.misses*b.total != b.misses*a.total {
+			return a.misses*b.total > b.misses*a.total
+		}
+		return a.term < b.term
+	})
+	if len(hardest) > digestListSize {
+		hardest = hardest[:digestListSize]
+	}
+	for _, id := range hardest {
+		data.HardestWords = append(data.HardestWords, stats[id].term)
+	}
+
+	data.FocusWords, err = s.focusWords(ctx, userId, hardest)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// focusWords suggests the hardest words not learned yet, completed with the ones with the least progress
+func (s *NotificationSvcImpl) focusWords(ctx context.Context, userId uuid.UUID, hardest []uuid.UUID) ([]string, error) {
+	ofUser := userword.And(userword.HasUserWith(user.IDEQ(userId)), userword.DeletedAtIsNil())
+	unlearnedHardest, err := s.DB.UserWord.Query().Where(ofUser, userword.IDIn(hardest...), userword.LearningProgressLT(100)).
+		Select(userword.FieldTerm).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unlearned := map[uuid.UUID]string{}
+	for _, word := range unlearnedHardest {
+		unlearned[word.ID] = word.Term
+	}
+	focus := make([]string, 0, digestListSize)
+	//The hardest keep their order
+	for _, id := range hardest {
+		if term, ok := unlearned[id]; ok {
+			focus = append(focus, term)
+		}
+	}
+	if len(focus) >= digestListSize {
+		return focus, nil
+	}
+	leastProgress, err := s.DB.UserWord.Query().Where(ofUser, userword.LearningProgressLT(100), userword.IDNotIn(hardest...)).
+		Order(ent.Asc(userword.FieldLearningProgress), ent.Asc(userword.FieldCreationDate)).
+		Limit(digestListSize - len(focus)).Select(userword.FieldTerm).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, word := range leastProgress {
+		focus = append(focus, word.Term)
+	}
+	return focus, nil
+}
+
+// ScheduleDigests checks the due weekly digests every interval until the context is done
+func (s *NotificationSvcImpl) ScheduleDigests(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				//A batch already started is finished on shutdown
+				sent, err := s.SendDigests(context.WithoutCancel(ctx), now)
+				if err != nil {
+					log.Error().Err(err).Msg("Error sending the weekly digests")
+				} else if sent > 0 {
+					log.Info().Int("sent", sent).Msg("Weekly digests sent")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,12 @@
+package notification
+
+type PreferencesForm struct {
+	RemindersEnabled *bool   `json:"remindersEnabled"`
+	ReminderTime     *string `json:"reminderTime"`
+	Timezone         *string `json:"timezone"`
+	DigestEnabled    *bool   `json:"digestEnabled"`
+}
+
+type UnsubscribeForm struct {
+	Token string `json:"token" binding:"required"`
+}
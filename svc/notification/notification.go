@@ -0,0 +1,174 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"time"
+	"vocablo/conf"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/predicate"
+	"vocablo/ent/user"
+	"vocablo/svc/mail"
+	"vocablo/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	// The timezones are embedded, so they don't depend on the ones installed in the image
+	_ "time/tzdata"
+)
+
+// Kinds of emails that can be unsubscribed from
+const (
+	REMINDERS_LIST = "reminders"
+	DIGEST_LIST    = "digest"
+)
+
+const reminderTimeLayout = "15:04"
+
+const batchSize = 100
+
+const defaultUnsubscribeTTL = 90 * 24 * time.Hour
+
+type Preferences struct {
+	RemindersEnabled bool   `json:"remindersEnabled"`
+	ReminderTime     string `json:"reminderTime"`
+	Timezone         string `json:"timezone"`
+	DigestEnabled    bool   `json:"digestEnabled"`
+}
+
+type NotificationSvc interface {
+	GetPreferences(ctx context.Context) (*Preferences, error)
+	UpdatePreferences(ctx context.Context, form PreferencesForm) (*Preferences, error)
+	// Unsubscribe disables the kind of emails of a signed unsubscribe link
+	Unsubscribe(ctx context.Context, form UnsubscribeForm) error
+	// SendReminders emails the users whose reminder time has passed today and returns how many were sent
+	SendReminders(ctx context.Context, now time.Time) (int, error)
+	ScheduleReminders(ctx context.Context, interval time.Duration)
+	// SendDigests emails the weekly digest to the users that opted in and haven't received the one of this week
+	// yet, if they had some activity, and returns how many were sent
+	SendDigests(ctx context.Context, now time.Time) (int, error)
+	// SendDigest emails the digest of the week before now to the user, even if it is not due
+	SendDigest(ctx context.Context, userId uuid.UUID, now time.Time) error
+	ScheduleDigests(ctx context.Context, interval time.Duration)
+	// Wait blocks until the scheduled reminders and digests stop, once their context is done
+	Wait()
+}
+
+type NotificationSvcImpl struct {
+	DB      *ent.Client
+	Mail    mail.MailSvc
+	workers sync.WaitGroup
+}
+
+func preferences(preferencesUser *ent.User) *Preferences {
+	return &Preferences{RemindersEnabled: preferencesUser.RemindersEnabled, ReminderTime: preferencesUser.ReminderTime,
+		Timezone: preferencesUser.Timezone, DigestEnabled: preferencesUser.DigestEnabled}
+}
+
+func (s *NotificationSvcImpl) GetPreferences(ctx context.Context) (*Preferences, error) {
+	loggedUser, err := s.DB.User.Get(ctx, ctx.Value(utils.UserIdKey).(uuid.UUID))
+	if err != nil {
+		return nil, err
+	}
+	return preferences(loggedUser), nil
+}
+
+func (s *NotificationSvcImpl) UpdatePreferences(ctx context.Context, form PreferencesForm) (*Preferences, error) {
+	update := s.DB.User.UpdateOneID(ctx.Value(utils.UserIdKey).(uuid.UUID))
+	if form.RemindersEnabled != nil {
+		update.SetRemindersEnabled(*form.RemindersEnabled)
+	}
+	if form.DigestEnabled != nil {
+		update.SetDigestEnabled(*form.DigestEnabled)
+	}
+	if form.ReminderTime != nil {
+		_, err := time.Parse(reminderTimeLayout, *form.ReminderTime)
+		if err != nil {
+			return nil, customerrors.InvalidReminderTimeError{}
+		}
+		update.SetReminderTime(*form.ReminderTime)
+	}
+	if form.Timezone != nil {
+		_, err := time.LoadLocation(*form.Timezone)
+		if err != nil || *form.Timezone == "" || *form.Timezone == "Local" {
+			return nil, customerrors.InvalidTimezoneError{}
+		}
+		update.SetTimezone(*form.Timezone)
+	}
+	updatedUser, err := update.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return preferences(updatedUser), nil
+}
+
+func (s *NotificationSvcImpl) Unsubscribe(ctx context.Context, form UnsubscribeForm) error {
+	claims, err := utils.ValidateUnsubscribeToken(form.Token)
+	if err != nil {
+		return customerrors.InvalidUnsubscribeTokenError{}
+	}
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return customerrors.InvalidUnsubscribeTokenError{}
+	}
+	update := s.DB.User.Update().Where(user.IDEQ(userId))
+	switch claims.List {
+	case REMINDERS_LIST:
+		update.SetRemindersEnabled(false)
+	case DIGEST_LIST:
+		update.SetDigestEnabled(false)
+	default:
+		return customerrors.InvalidUnsubscribeTokenError{}
+	}
+	//A link of a deleted account is not an error, there is nothing left to unsubscribe from
+	_, err = update.Save(ctx)
+	return err
+}
+
+// sendBatched calls send with the active users matching the filter, in batches to bound the memory, and returns how
+// many emails were sent. The error of a user is logged so it doesn't stop the rest
+func (s *NotificationSvcImpl) sendBatched(ctx context.Context, now time.Time, filter predicate.User,
+	send func(ctx context.Context, recipient *ent.User, now time.Time) (bool, error), errorMsg string) (int, error) {
+	sent := 0
+	lastId := uuid.Nil
+	for {
+		users, err := s.DB.User.Query().Where(filter, user.ValidatedEQ(true), user.DisabledEQ(false), user.IDGT(lastId)).
+			Order(ent.Asc(user.FieldID)).Limit(batchSize).All(ctx)
+		if err != nil {
+			return sent, err
+		}
+		for _, recipient := range users {
+			ok, err := send(ctx, recipient, now)
+			if err != nil {
+				log.Error().Err(err).Str("user", recipient.ID.String()).Msg(errorMsg)
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(users) < batchSize {
+			return sent, nil
+		}
+		lastId = users[len(users)-1].ID
+	}
+}
+
+func (s *NotificationSvcImpl) Wait() {
+	s.workers.Wait()
+}
+
+// unsubscribeUrls returns the link of the web app that unsubscribes the user from the kind of emails, and the one
+// of the API the email clients POST to when the user unsubscribes with one click, with the same token
+func unsubscribeUrls(userId uuid.UUID, list string) (string, string, error) {
+	ttl := conf.Get().Notifications.UnsubscribeTTL
+	if ttl <= 0 {
+		ttl = defaultUnsubscribeTTL
+	}
+	token, err := utils.GenerateUnsubscribeJWT(userId.String(), list, ttl)
+	if err != nil {
+		return "", "", err
+	}
+	return utils.FrontUrl() + "/unsubscribe/" + token, utils.ApiUrl() + "/api/public/unsubscribe/" + token, nil
+}
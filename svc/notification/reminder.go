@@ -0,0 +1,143 @@
+package notification
+
+import (
+	"context"
+	"time"
+	"vocablo/ent"
+	"vocablo/ent/quizresult"
+	"vocablo/ent/user"
+	"vocablo/ent/userword"
+	"vocablo/svc/mail"
+	"vocablo/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultReminderTime = "18:00"
+
+// The quizzes older than this don't count for the streak, to bound the query
+const maxStreakDays = 366
+
+// localDay returns the start of the day of the time in the location
+func localDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// reminderDue checks if the reminder time of the user has passed today, in their timezone, without
+// having been reminded yet
+func reminderDue(reminderUser *ent.User, now time.Time) (bool, *time.Location) {
+	loc, err := time.LoadLocation(reminderUser.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	reminderTime, err := time.Parse(reminderTimeLayout, reminderUser.ReminderTime)
+	if err != nil {
+		reminderTime, _ = time.Parse(reminderTimeLayout, defaultReminderTime)
+	}
+	today := localDay(now, loc)
+	remindAt := today.Add(time.Duration(reminderTime.Hour())*time.Hour + time.Duration(reminderTime.Minute())*time.Minute)
+	if now.Before(remindAt) {
+		return false, loc
+	}
+	if reminderUser.LastReminderDate != nil && !reminderUser.LastReminderDate.Before(today) {
+		return false, loc
+	}
+	return true, loc
+}
+
+// streak returns the number of consecutive days with a quiz up to today, or up to yesterday if there
+// is none today yet, and if there is one today
+func streak(quizDates []time.Time, now time.Time, loc *time.Location) (int, bool) {
+	days := map[time.Time]bool{}
+	for _, quizDate := range quizDates {
+		days[localDay(quizDate, loc)] = true
+	}
+	today := localDay(now, loc)
+	practisedToday := days[today]
+	day := today
+	if !practisedToday {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for days[day] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak, practisedToday
+}
+
+func (s *NotificationSvcImpl) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	return s.sendBatched(ctx, now, user.RemindersEnabledEQ(true), s.remind, "Error sending the study reminder")
+}
+
+// remind sends the reminder to the user if it is due and there is something to practise
+func (s *NotificationSvcImpl) remind(ctx context.Context, reminderUser *ent.User, now time.Time) (bool, error) {
+	due, loc := reminderDue(reminderUser, now)
+	if !due {
+		return false, nil
+	}
+	//The day is claimed with a conditional update, so the user is only evaluated once a day even with several instances
+	today := localDay(now, loc)
+	claimed, err := s.DB.User.Update().Where(user.IDEQ(reminderUser.ID),
+		user.Or(user.LastReminderDateIsNil(), user.LastReminderDateLT(today))).SetLastReminderDate(now).Save(ctx)
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+	dueWords, err := s.DB.UserWord.Query().Where(userword.HasUserWith(user.IDEQ(reminderUser.ID)),
+		userword.LearningProgressLT(100), userword.DeletedAtIsNil()).Count(ctx)
+	if err != nil {
+		return false, err
+	}
+	quizzes, err := s.DB.QuizResult.Query().Where(quizresult.HasUserWith(user.IDEQ(reminderUser.ID)),
+		quizresult.CreationDateGT(now.AddDate(0, 0, -maxStreakDays))).Select(quizresult.FieldCreationDate).All(ctx)
+	if err != nil {
+		return false, err
+	}
+	quizDates := make([]time.Time, len(quizzes))
+	for i, quiz := range quizzes {
+		quizDates[i] = quiz.CreationDate
+	}
+	streak, practisedToday := streak(quizDates, now, loc)
+	if practisedToday || (dueWords == 0 && streak == 0) {
+		return false, nil
+	}
+	unsubscribeUrl, oneClickUrl, err := unsubscribeUrls(reminderUser.ID, REMINDERS_LIST)
+	if err != nil {
+		return false, err
+	}
+	err = s.Mail.Send(ctx, nil, reminderUser.Email, reminderUser.Locale, mail.STUDY_REMINDER_TEMPLATE, mail.TemplateData{
+		Username: reminderUser.Username, Url: utils.FrontUrl(), UnsubscribeUrl: unsubscribeUrl, OneClickUnsubscribeUrl: oneClickUrl,
+		DueWords: dueWords, Streak: streak})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ScheduleReminders checks the due reminders every interval until the context is done
+func (s *NotificationSvcImpl) ScheduleReminders(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				//A batch already started is finished on shutdown
+				sent, err := s.SendReminders(context.WithoutCancel(ctx), now)
+				if err != nil {
+					log.Error().Err(err).Msg("Error sending the study reminders")
+				} else if sent > 0 {
+					log.Info().Int("sent", sent).Msg("Study reminders sent")
+				}
+			}
+		}
+	}()
+}
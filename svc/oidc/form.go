@@ -0,0 +1,8 @@
+package oidc
+
+type CallbackForm struct {
+	Provider   string `json:"-"`
+	StateToken string `json:"-"`
+	Code       string `json:"code" binding:"required"`
+	State      string `json:"state" binding:"required"`
+}
@@ -0,0 +1,223 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"sync"
+	"vocablo/conf"
+	"vocablo/customerrors"
+	"vocablo/ent"
+	"vocablo/ent/identity"
+	"vocablo/ent/user"
+	"vocablo/metrics"
+	"vocablo/svc/audit"
+	"vocablo/svc/auth"
+	"vocablo/utils"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+)
+
+type OIDCSvc interface {
+	Providers() []string
+	Start(ctx context.Context, providerName string) (*StartResult, error)
+	Callback(ctx context.Context, form CallbackForm) (*auth.LoginResult, error)
+}
+
+type StartResult struct {
+	AuthUrl string
+	// Signed token with the state, nonce and PKCE verifier, that has to be sent back in the callback
+	StateToken string
+}
+
+type OIDCSvcImpl struct {
+	DB        *ent.Client
+	Audit     audit.AuditSvc
+	mu        sync.Mutex
+	providers map[string]provider
+}
+
+func (s *OIDCSvcImpl) getProvider(name string) (provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.providers[name]; ok {
+		return p, nil
+	}
+	for _, providerConf := range conf.Get().OIDC.Providers {
+		if providerConf.Name != name {
+			continue
+		}
+		p, err := newProvider(providerConf)
+		if err != nil {
+			return nil, err
+		}
+		if s.providers == nil {
+			s.providers = map[string]provider{}
+		}
+		s.providers[name] = p
+		return p, nil
+	}
+	return nil, customerrors.UnknownOIDCProviderError{}
+}
+
+func (s *OIDCSvcImpl) Providers() []string {
+	names := []string{}
+	for _, providerConf := range conf.Get().OIDC.Providers {
+		names = append(names, providerConf.Name)
+	}
+	return names
+}
+
+func (s *OIDCSvcImpl) Start(ctx context.Context, providerName string) (*StartResult, error) {
+	provider, err := s.getProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	authUrl, err := provider.authCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	stateToken, err := utils.GenerateOIDCStateJWT(providerName, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return &StartResult{AuthUrl: authUrl, StateToken: stateToken}, nil
+}
+
+func (s *OIDCSvcImpl) Callback(ctx context.Context, form CallbackForm) (*auth.LoginResult, error) {
+	if form.Code == "" || form.State == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	stateClaims, err := utils.ValidateOIDCStateToken(form.StateToken)
+	if err != nil || stateClaims.Provider != form.Provider ||
+		subtle.ConstantTimeCompare([]byte(stateClaims.State), []byte(form.State)) != 1 {
+		return nil, customerrors.InvalidOIDCStateError{}
+	}
+	provider, err := s.getProvider(form.Provider)
+	if err != nil {
+		return nil, err
+	}
+	externalId, err := provider.exchange(ctx, form.Code, stateClaims.Verifier, stateClaims.Nonce)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", form.Provider).Msg("OIDC code exchange failed")
+		return nil, customerrors.OIDCAuthenticationError{Reason: err.Error()}
+	}
+	loginUser, err := s.findOrCreateUser(ctx, form.Provider, externalId)
+	if err != nil {
+		return nil, err
+	}
+	if loginUser.Disabled {
+		s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_FAILURE_EVENT, UserId: &loginUser.ID, Detail: "disabled account"})
+		return nil, customerrors.DisabledAccountError{}
+	}
+	s.Audit.Record(ctx, audit.Event{Type: utils.LOGIN_SUCCESS_EVENT, UserId: &loginUser.ID, Detail: form.Provider})
+	return auth.GenerateLoginResult(loginUser)
+}
+
+// findOrCreateUser returns the user linked to the external identity. If there is none, the identity is linked
+// to the user with the same (verified) email, or to a new user otherwise. A user that never validated its email
+// is taken over, as anyone could have registered it with an email they don't own
+func (s *OIDCSvcImpl) findOrCreateUser(ctx context.Context, providerName string, externalId *externalIdentity) (*ent.User, error) {
+	linkedIdentity, err := s.DB.Identity.Query().Where(identity.ProviderEQ(providerName),
+		identity.SubjectEQ(externalId.Subject)).WithUser().Only(ctx)
+	if err == nil {
+		return linkedIdentity.Edges.User, nil
+	}
+	if !ent.IsNotFound(err) {
+		return nil, err
+	}
+	if externalId.Email == "" || !externalId.EmailVerified {
+		return nil, customerrors.NotVerifiedOIDCEmailError{}
+	}
+
+	clientTx, err := s.DB.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	linkedUser, err := clientTx.User.Query().Where(user.EmailEQ(externalId.Email)).Only(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		clientTx.Rollback()
+		return nil, err
+	}
+	created := linkedUser == nil
+	if created {
+		linkedUser, err = s.createUser(ctx, clientTx, externalId)
+	} else if !linkedUser.Validated {
+		linkedUser, err = s.takeOverUser(ctx, clientTx, linkedUser)
+	}
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	_, err = clientTx.Identity.Create().SetProvider(providerName).SetSubject(externalId.Subject).
+		SetEmail(externalId.Email).SetUser(linkedUser).Save(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	err = clientTx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		metrics.SignUps.WithLabelValues(metrics.OIDC_METHOD).Inc()
+	}
+	return linkedUser, nil
+}
+
+func (s *OIDCSvcImpl) createUser(ctx context.Context, clientTx *ent.Tx, externalId *externalIdentity) (*ent.User, error) {
+	username, err := s.availableUsername(ctx, clientTx, externalId)
+	if err != nil {
+		return nil, err
+	}
+	//OIDC users don't have a password, so we store a random one they can only change with the forgotten password flow
+	password, err := utils.RandomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+	return clientTx.User.Create().SetUsername(username).SetEmail(externalId.Email).
+		SetPassword(password).SetValidated(true).Save(ctx)
+}
+
+// takeOverUser gives the account of the unvalidated user to the owner of the email verified by the provider. The
+// password set when registering it is replaced and its sessions are closed, so whoever registered it loses access
+func (s *OIDCSvcImpl) takeOverUser(ctx context.Context, clientTx *ent.Tx, unvalidatedUser *ent.User) (*ent.User, error) {
+	password, err := utils.RandomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+	return clientTx.User.UpdateOne(unvalidatedUser).SetPassword(password).AddSessionVersion(1).
+		SetValidated(true).Save(ctx)
+}
+
+func (s *OIDCSvcImpl) availableUsername(ctx context.Context, clientTx *ent.Tx, externalId *externalIdentity) (string, error) {
+	baseUsername := externalId.PreferredUsername
+	if baseUsername == "" {
+		baseUsername = strings.Split(externalId.Email, "@")[0]
+	}
+	username := baseUsername
+	for {
+		exists, err := clientTx.User.Query().Where(user.UsernameEQ(username)).Exist(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return username, nil
+		}
+		suffix, err := utils.GenerateRandomToken(6)
+		if err != nil {
+			return "", err
+		}
+		username = baseUsername + "-" + suffix
+	}
+}
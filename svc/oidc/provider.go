@@ -0,0 +1,214 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"vocablo/conf"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const (
+	OIDC_PROVIDER_TYPE   = "oidc"
+	GITHUB_PROVIDER_TYPE = "github"
+)
+
+var defaultOIDCScopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+var defaultGithubScopes = []string{"read:user", "user:email"}
+
+const defaultGithubApiUrl = "https://api.github.com"
+
+// externalIdentity is the normalized user information returned by any provider
+type externalIdentity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+type provider interface {
+	authCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error)
+	exchange(ctx context.Context, code string, verifier string, nonce string) (*externalIdentity, error)
+}
+
+func newProvider(providerConf conf.OIDCProviderConf) (provider, error) {
+	switch providerConf.Type {
+	case OIDC_PROVIDER_TYPE, "":
+		return &oidcProvider{conf: providerConf}, nil
+	case GITHUB_PROVIDER_TYPE:
+		return &githubProvider{conf: providerConf}, nil
+	}
+	return nil, fmt.Errorf("unsupported provider type %s", providerConf.Type)
+}
+
+// oidcProvider works with any issuer that publishes a discovery document (Google, Keycloak...)
+type oidcProvider struct {
+	conf     conf.OIDCProviderConf
+	mu       sync.Mutex
+	provider *gooidc.Provider
+}
+
+// The discovery document is fetched on first use, so an unreachable issuer doesn't prevent the app from starting
+func (p *oidcProvider) discover(ctx context.Context) (*gooidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, nil
+	}
+	provider, err := gooidc.NewProvider(ctx, p.conf.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	p.provider = provider
+	return provider, nil
+}
+
+func (p *oidcProvider) oauthConfig(provider *gooidc.Provider) *oauth2.Config {
+	scopes := p.conf.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOIDCScopes
+	}
+	return &oauth2.Config{
+		ClientID:     p.conf.ClientId,
+		ClientSecret: p.conf.ClientSecret,
+		RedirectURL:  p.conf.RedirectUrl,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+}
+
+func (p *oidcProvider) authCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.oauthConfig(provider).AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), gooidc.Nonce(nonce)), nil
+}
+
+func (p *oidcProvider) exchange(ctx context.Context, code string, verifier string, nonce string) (*externalIdentity, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.oauthConfig(provider).Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("no id_token in token response")
+	}
+	idToken, err := provider.Verifier(&gooidc.Config{ClientID: p.conf.ClientId}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, err
+	}
+	return &externalIdentity{Subject: idToken.Subject, Email: claims.Email, EmailVerified: claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername}, nil
+}
+
+// githubProvider uses plain OAuth2 plus the GitHub REST API, as GitHub doesn't issue id tokens
+type githubProvider struct {
+	conf conf.OIDCProviderConf
+}
+
+type githubUser struct {
+	Id    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func (p *githubProvider) oauthConfig() *oauth2.Config {
+	endpoint := github.Endpoint
+	if p.conf.AuthUrl != "" {
+		endpoint.AuthURL = p.conf.AuthUrl
+	}
+	if p.conf.TokenUrl != "" {
+		endpoint.TokenURL = p.conf.TokenUrl
+	}
+	scopes := p.conf.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultGithubScopes
+	}
+	return &oauth2.Config{
+		ClientID:     p.conf.ClientId,
+		ClientSecret: p.conf.ClientSecret,
+		RedirectURL:  p.conf.RedirectUrl,
+		Endpoint:     endpoint,
+		Scopes:       scopes,
+	}
+}
+
+func (p *githubProvider) apiUrl() string {
+	if p.conf.ApiUrl != "" {
+		return p.conf.ApiUrl
+	}
+	return defaultGithubApiUrl
+}
+
+func (p *githubProvider) authCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	return p.oauthConfig().AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *githubProvider) exchange(ctx context.Context, code string, verifier string, nonce string) (*externalIdentity, error) {
+	oauthConfig := p.oauthConfig()
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	client := oauthConfig.Client(ctx, token)
+
+	var user githubUser
+	err = getJson(client, p.apiUrl()+"/user", &user)
+	if err != nil {
+		return nil, err
+	}
+	var emails []githubEmail
+	err = getJson(client, p.apiUrl()+"/user/emails", &emails)
+	if err != nil {
+		return nil, err
+	}
+	identity := &externalIdentity{Subject: strconv.FormatInt(user.Id, 10), PreferredUsername: user.Login}
+	for _, email := range emails {
+		if email.Verified && (email.Primary || identity.Email == "") {
+			identity.Email = email.Email
+			identity.EmailVerified = true
+		}
+	}
+	return identity, nil
+}
+
+func getJson(client *http.Client, url string, target interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
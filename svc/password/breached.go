@@ -0,0 +1,120 @@
+package password
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lineChunk is how much of the file is read at once while looking for the end of a line. It fits a whole line of
+// the list, so a lookup usually reads once per step
+const lineChunk = 128
+
+// BreachedList is an offline copy of a breached password list in the format of Pwned Passwords: the
+// uppercase SHA-1 of each password, optionally followed by ":" and the number of times it was seen.
+// The file must be ordered by hash, as the "ordered by hash" downloads of Pwned Passwords are, so a
+// lookup is a binary search on disk and the list is never loaded in memory. Comments, the lines starting
+// with "#", can only be at the beginning
+type BreachedList struct {
+	file *os.File
+	size int64
+}
+
+// LoadBreachedList opens the list. The file is kept open to be searched
+func LoadBreachedList(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &BreachedList{file: file, size: info.Size()}, nil
+}
+
+// Contains checks if the password is in the list. A nil list contains nothing
+func (l *BreachedList) Contains(password string) bool {
+	if l == nil {
+		return false
+	}
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	found, err := l.search(hash)
+	if err != nil {
+		log.Error().Err(err).Str("path", l.file.Name()).Msg("Error searching the breached password list")
+		return false
+	}
+	return found
+}
+
+// search looks for the hash among the lines that start between lo and hi, halving the range with the first line
+// that starts after its middle
+func (l *BreachedList) search(hash string) (bool, error) {
+	lo, hi := int64(0), l.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start := mid
+		if mid > lo {
+			//The line that contains the byte before the middle ends where the next one starts
+			_, end, err := l.readLine(mid - 1)
+			if err != nil {
+				return false, err
+			}
+			start = end
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		line, end, err := l.readLine(start)
+		if err != nil {
+			return false, err
+		}
+		lineHash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		lineHash = strings.ToUpper(lineHash)
+		switch {
+		case lineHash == hash:
+			return true, nil
+		case lineHash < hash:
+			lo = end
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// readLine returns the rest of the line from the offset, and where the next one starts
+func (l *BreachedList) readLine(offset int64) (string, int64, error) {
+	var line []byte
+	buf := make([]byte, lineChunk)
+	for {
+		n, err := l.file.ReadAt(buf, offset+int64(len(line)))
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			line = append(line, buf[:i]...)
+			return string(line), offset + int64(len(line)) + 1, nil
+		}
+		line = append(line, buf[:n]...)
+		if err == io.EOF {
+			return string(line), offset + int64(len(line)), nil
+		}
+		if err != nil {
+			return "", 0, err
+		}
+	}
+}
+
+// Size returns the size of the file of the list
+func (l *BreachedList) Size() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.size
+}
@@ -0,0 +1,64 @@
+package password
+
+import (
+	"strings"
+	"unicode"
+	"vocablo/conf"
+	"vocablo/customerrors"
+)
+
+type PasswordSvc interface {
+	Check(password string, username string, email string) error
+}
+
+type PasswordSvcImpl struct {
+	Breached *BreachedList
+}
+
+// Check validates a new password against the configured policy. It returns a WeakPasswordError with
+// every rule that failed, so the client can show them all at once
+func (s *PasswordSvcImpl) Check(password string, username string, email string) error {
+	policy := conf.Get().PasswordPolicy
+	var rules []string
+	if len([]rune(password)) < policy.MinLength {
+		rules = append(rules, customerrors.PASSWORD_TOO_SHORT)
+	}
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if policy.RequireLower && !hasLower {
+		rules = append(rules, customerrors.PASSWORD_NO_LOWERCASE)
+	}
+	if policy.RequireUpper && !hasUpper {
+		rules = append(rules, customerrors.PASSWORD_NO_UPPERCASE)
+	}
+	if policy.RequireDigit && !hasDigit {
+		rules = append(rules, customerrors.PASSWORD_NO_DIGIT)
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		rules = append(rules, customerrors.PASSWORD_NO_SYMBOL)
+	}
+	if username != "" && strings.EqualFold(password, username) {
+		rules = append(rules, customerrors.PASSWORD_EQUALS_USERNAME)
+	}
+	if email != "" && strings.EqualFold(password, email) {
+		rules = append(rules, customerrors.PASSWORD_EQUALS_EMAIL)
+	}
+	if s.Breached.Contains(password) {
+		rules = append(rules, customerrors.PASSWORD_BREACHED)
+	}
+	if len(rules) > 0 {
+		return customerrors.WeakPasswordError{Rules: rules}
+	}
+	return nil
+}
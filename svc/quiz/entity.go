@@ -1,6 +1,10 @@
 package quiz
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Quiz struct {
 	Questions []QuizQuestion `json:"questions"`
@@ -14,3 +18,11 @@ type QuizQuestion struct {
 	CorrectOptionPos int       `json:"correctOptionPos"`
 	AnswerPos        *int      `json:"answerPos"`
 }
+
+// OfflineAnswer is a quiz answered without connection, with the ID the client gave to its result and the date it
+// was answered
+type OfflineAnswer struct {
+	ID         uuid.UUID      `json:"id" binding:"required"`
+	AnsweredAt time.Time      `json:"answeredAt"`
+	Questions  []QuizQuestion `json:"questions" binding:"required"`
+}
@@ -5,34 +5,47 @@ import (
 	"math"
 	"math/rand"
 	"slices"
+	"time"
 	"vocablo/customerrors"
 	"vocablo/ent"
+	"vocablo/ent/quizresult"
 	"vocablo/ent/user"
 	"vocablo/ent/userword"
+	"vocablo/metrics"
+	"vocablo/schema"
+	userwordsvc "vocablo/svc/userword"
+	"vocablo/tracing"
 	"vocablo/utils"
 
 	"entgo.io/ent/dialect/sql"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// maxOfflineAge is how long before being pushed a quiz can have been answered offline
+const maxOfflineAge = 30 * 24 * time.Hour
+
 type QuizSvc interface {
 	Create(ctx context.Context, form CreateForm) (*Quiz, error)
 	Answer(ctx context.Context, filledQuiz Quiz) (int, error)
+	AnswerOffline(ctx context.Context, offlineAnswer OfflineAnswer) (int, error)
 }
 
 type QuizSvcImpl struct {
 	DB *ent.Client
 }
 
-func (s *QuizSvcImpl) Create(ctx context.Context, form CreateForm) (*Quiz, error) {
+func (s *QuizSvcImpl) Create(ctx context.Context, form CreateForm) (quiz *Quiz, err error) {
 	nQuestions := 10
 	if form.NQuestions > 0 {
 		nQuestions = form.NQuestions
 	}
+	ctx, span := tracing.Start(ctx, "QuizSvc.Create", attribute.Int("quiz.questions", nQuestions))
+	defer tracing.End(span, &err)
 
 	//We collect double the number of questions to have more options to put in the quiz (avoiding the already learned)
 	userWords, err := s.DB.UserWord.Query().Limit(nQuestions * 2).
-		Where(userword.And(userword.LearningProgressLT(100),
+		Where(userword.And(userword.LearningProgressLT(100), userword.DeletedAtIsNil(),
 			userword.HasUserWith(user.IDEQ(ctx.Value(utils.UserIdKey).(uuid.UUID))))).Order(sql.OrderByRand()).All(ctx)
 
 	//If the user has less than 4 words, we return an error (we need at least 4 words to create a quiz, 1 correct and 3 incorrect)
@@ -91,30 +104,124 @@ func (s *QuizSvcImpl) Create(ctx context.Context, form CreateForm) (*Quiz, error
 		return nil, err
 	}
 
+	metrics.QuizzesCreated.Inc()
 	return &Quiz{Questions: questions}, nil
 }
 
-func (s *QuizSvcImpl) Answer(ctx context.Context, filledQuiz Quiz) (int, error) {
+func (s *QuizSvcImpl) Answer(ctx context.Context, filledQuiz Quiz) (score int, err error) {
+	ctx, span := tracing.Start(ctx, "QuizSvc.Answer", attribute.Int("quiz.questions", len(filledQuiz.Questions)))
+	defer tracing.End(span, &err)
+	return s.answer(ctx, uuid.New(), time.Now(), filledQuiz.Questions)
+}
+
+// AnswerOffline answers a quiz the user answered without connection, keeping its result with the ID and date of
+// the client. An answer already kept isn't applied again, its score is returned instead, so the client can retry
+func (s *QuizSvcImpl) AnswerOffline(ctx context.Context, offlineAnswer OfflineAnswer) (score int, err error) {
+	ctx, span := tracing.Start(ctx, "QuizSvc.AnswerOffline", attribute.Int("quiz.questions", len(offlineAnswer.Questions)))
+	defer tracing.End(span, &err)
+	score, stored, err := s.storedScore(ctx, offlineAnswer.ID)
+	if err != nil || stored {
+		return score, err
+	}
+	//The clock of the device can't set the answer in the future, nor long before it could be answered
+	answeredAt := offlineAnswer.AnsweredAt
+	if answeredAt.IsZero() || answeredAt.After(time.Now()) {
+		answeredAt = time.Now()
+	}
+	if oldest := time.Now().Add(-maxOfflineAge); answeredAt.Before(oldest) {
+		answeredAt = oldest
+	}
+	score, err = s.answer(ctx, offlineAnswer.ID, answeredAt, offlineAnswer.Questions)
+	if ent.IsConstraintError(err) {
+		//The same answer pushed at the same time by another request was stored first
+		var storedErr error
+		score, stored, storedErr = s.storedScore(ctx, offlineAnswer.ID)
+		if storedErr != nil || stored {
+			return score, storedErr
+		}
+	}
+	return score, err
+}
+
+// storedScore returns the score of the quiz result with the ID, if it was already stored for the user
+func (s *QuizSvcImpl) storedScore(ctx context.Context, id uuid.UUID) (int, bool, error) {
+	storedResult, err := s.DB.QuizResult.Query().Where(quizresult.ID(id)).WithUser().Only(ctx)
+	if ent.IsNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if storedResult.Edges.User.ID != ctx.Value(utils.UserIdKey).(uuid.UUID) {
+		return 0, false, customerrors.NotAllowedResourceError{}
+	}
+	return storedResult.Score, true, nil
+}
+
+// answer applies the answers of a quiz to the progress of the words, keeping its result with the ID and date given
+func (s *QuizSvcImpl) answer(ctx context.Context, resultId uuid.UUID, answeredAt time.Time, questions []QuizQuestion) (int, error) {
+	//The answers can only change the progress of the words of the user
+	userId := ctx.Value(utils.UserIdKey).(uuid.UUID)
+	userWordIds := make([]uuid.UUID, 0, len(questions))
+	for _, question := range questions {
+		if !slices.Contains(userWordIds, question.UserWordID) {
+			userWordIds = append(userWordIds, question.UserWordID)
+		}
+	}
+	nOwned, err := s.DB.UserWord.Query().Where(userword.IDIn(userWordIds...), userword.HasUserWith(user.ID(userId)),
+		userword.DeletedAtIsNil()).Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if nOwned != len(userWordIds) {
+		return 0, customerrors.NotAllowedResourceError{}
+	}
 	totalScore := 0.0
-	questionValue := 100.0 / float64(len(filledQuiz.Questions))
+	questionValue := 100.0 / float64(len(questions))
 	clientTx, err := s.DB.Tx(ctx)
 	if err != nil {
 		return 0, err
 	}
-	for _, question := range filledQuiz.Questions {
+	//The progress changes are synced to the other devices of the user with the version of the answer
+	version, err := userwordsvc.NextVersion(ctx, clientTx, userId)
+	if err != nil {
+		clientTx.Rollback()
+		return 0, err
+	}
+	answers := make([]schema.QuizAnswer, 0, len(questions))
+	nCorrect := 0
+	for _, question := range questions {
 		//If the answer is correct, we add the value of the question to the total score and we add 10 to the learning progress of the word
-		if question.AnswerPos != nil && question.Options[*question.AnswerPos] == question.Options[question.CorrectOptionPos] {
+		correct := question.AnswerPos != nil && question.Options[*question.AnswerPos] == question.Options[question.CorrectOptionPos]
+		if correct {
 			totalScore += questionValue
-			err = clientTx.UserWord.UpdateOneID(question.UserWordID).AddLearningProgress(10).Exec(ctx)
+			nCorrect++
+			err = clientTx.UserWord.UpdateOneID(question.UserWordID).AddLearningProgress(10).SetVersion(version).Exec(ctx)
+			if err != nil {
+				clientTx.Rollback()
+				return 0, err
+			}
+			//The first time the word is learned is kept for the weekly digest
+			err = clientTx.UserWord.Update().Where(userword.ID(question.UserWordID), userword.LearningProgressGTE(100),
+				userword.LearnedDateIsNil()).SetLearnedDate(answeredAt).Exec(ctx)
 			if err != nil {
 				clientTx.Rollback()
 				return 0, err
 			}
 		}
+		answers = append(answers, schema.QuizAnswer{UserWordID: question.UserWordID, Term: question.Question, Correct: correct})
+	}
+	//We keep the result for the quiz history
+	err = clientTx.QuizResult.Create().SetID(resultId).SetCreationDate(answeredAt).SetScore(int(totalScore)).
+		SetNQuestions(len(questions)).SetNCorrect(nCorrect).SetAnswers(answers).SetUserID(userId).Exec(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return 0, err
 	}
 	err = clientTx.Commit()
 	if err != nil {
 		return 0, err
 	}
+	metrics.QuizzesAnswered.Inc()
 	return int(totalScore), nil
 }
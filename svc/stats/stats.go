@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"context"
+	"time"
+	"vocablo/ent"
+	"vocablo/ent/quizresult"
+	"vocablo/ent/user"
+	"vocablo/ent/userword"
+	"vocablo/utils"
+)
+
+type Stats struct {
+	Users           int `json:"users"`
+	ValidatedUsers  int `json:"validatedUsers"`
+	DisabledUsers   int `json:"disabledUsers"`
+	Admins          int `json:"admins"`
+	NewUsersLastDay int `json:"newUsersLastDay"`
+	UserWords       int `json:"userWords"`
+	Words           int `json:"words"`
+	Languages       int `json:"languages"`
+	Quizzes         int `json:"quizzes"`
+	QuizzesLastDay  int `json:"quizzesLastDay"`
+}
+
+type StatsSvc interface {
+	Get(ctx context.Context) (*Stats, error)
+}
+
+type StatsSvcImpl struct {
+	DB *ent.Client
+}
+
+func (s *StatsSvcImpl) Get(ctx context.Context) (*Stats, error) {
+	lastDay := time.Now().Add(-24 * time.Hour)
+	var stats Stats
+	var err error
+	if stats.Users, err = s.DB.User.Query().Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.ValidatedUsers, err = s.DB.User.Query().Where(user.ValidatedEQ(true)).Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.DisabledUsers, err = s.DB.User.Query().Where(user.DisabledEQ(true)).Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Admins, err = s.DB.User.Query().Where(user.RoleEQ(utils.ADMIN_ROLE)).Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.NewUsersLastDay, err = s.DB.User.Query().Where(user.CreationDateGT(lastDay)).Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.UserWords, err = s.DB.UserWord.Query().Where(userword.DeletedAtIsNil()).Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Words, err = s.DB.Word.Query().Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Languages, err = s.DB.Language.Query().Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Quizzes, err = s.DB.QuizResult.Query().Count(ctx); err != nil {
+		return nil, err
+	}
+	if stats.QuizzesLastDay, err = s.DB.QuizResult.Query().Where(quizresult.CreationDateGT(lastDay)).Count(ctx); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -1,14 +1,30 @@
 package svc
 
 import (
+	"errors"
+	"fmt"
+	"time"
+	"vocablo/conf"
+	"vocablo/db"
 	"vocablo/ent"
+	"vocablo/svc/audit"
 	"vocablo/svc/auth"
+	"vocablo/svc/deltasync"
+	"vocablo/svc/export"
+	"vocablo/svc/health"
+	"vocablo/svc/language"
 	"vocablo/svc/mail"
+	"vocablo/svc/notification"
+	"vocablo/svc/oidc"
+	"vocablo/svc/password"
 	"vocablo/svc/quiz"
+	"vocablo/svc/stats"
 	"vocablo/svc/user"
 	"vocablo/svc/userword"
 	"vocablo/svc/verificationcode"
 	"vocablo/svc/word"
+
+	"github.com/rs/zerolog/log"
 )
 
 type Service struct {
@@ -18,6 +34,16 @@ type Service struct {
 	UserWord         userword.UserWordSvc
 	Word             word.WordSvc
 	Quiz             quiz.QuizSvc
+	OIDC             oidc.OIDCSvc
+	Export           export.ExportSvc
+	Language         language.LanguageSvc
+	Stats            stats.StatsSvc
+	Audit            audit.AuditSvc
+	Password         password.PasswordSvc
+	Mail             mail.MailSvc
+	Notification     notification.NotificationSvc
+	Health           health.HealthSvc
+	Sync             deltasync.SyncSvc
 }
 
 var svc Service
@@ -26,13 +52,87 @@ func Get() *Service {
 	return &svc
 }
 
-func Setup(client *ent.Client, mailSvc mail.MailSvc) {
+// Setup creates the services. The sender delivers the emails once rendered with the configured templates
+func Setup(client *ent.Client, sender mail.Sender) error {
+	mailConf := conf.Get().Mail
+	mailSvc, err := mail.NewMailSvc(client, sender, conf.ResolvePath(mailConf.TemplatesDir), mailConf.DefaultLocale)
+	if err != nil {
+		return err
+	}
+	dictionaryConf := conf.Get().Dictionary
+	auditSvc := &audit.AuditSvcImpl{DB: client}
+	breachedList, err := loadBreachedList()
+	if err != nil {
+		return err
+	}
+	passwordSvc := &password.PasswordSvcImpl{Breached: breachedList}
+	verificationCodeSvc := &verificationcode.VerificationCodeSvcImpl{DB: client, Mail: mailSvc, Audit: auditSvc, Password: passwordSvc}
+	quizSvc := &quiz.QuizSvcImpl{DB: client}
+	wordSvc := &word.WordSvcImpl{DB: client, Breaker: word.NewCircuitBreaker(dictionaryConf.FailureThreshold, dictionaryConf.OpenDuration)}
 	svc = Service{
-		User:             &user.UserSvcImpl{DB: client},
-		Auth:             &auth.AuthSvcImpl{DB: client, VerificationCodeSvc: &verificationcode.VerificationCodeSvcImpl{DB: client, Mail: mailSvc}},
-		VerificationCode: &verificationcode.VerificationCodeSvcImpl{DB: client, Mail: mailSvc},
+		User:             &user.UserSvcImpl{DB: client, Audit: auditSvc},
+		Auth:             &auth.AuthSvcImpl{DB: client, VerificationCodeSvc: verificationCodeSvc, Mail: mailSvc, Audit: auditSvc, Password: passwordSvc},
+		VerificationCode: verificationCodeSvc,
 		UserWord:         &userword.UserWordSvcImpl{DB: client},
-		Word:             &word.WordSvcImpl{DB: client},
-		Quiz:             &quiz.QuizSvcImpl{DB: client},
+		Word:             wordSvc,
+		Quiz:             quizSvc,
+		OIDC:             &oidc.OIDCSvcImpl{DB: client, Audit: auditSvc},
+		Export:           &export.ExportSvcImpl{DB: client, Mail: mailSvc},
+		Language:         &language.LanguageSvcImpl{DB: client},
+		Stats:            &stats.StatsSvcImpl{DB: client},
+		Audit:            auditSvc,
+		Password:         passwordSvc,
+		Mail:             mailSvc,
+		Notification:     &notification.NotificationSvcImpl{DB: client, Mail: mailSvc},
+		Health:           &health.HealthSvcImpl{DB: client, Word: wordSvc, Migrated: db.Migrated},
+		Sync:             &deltasync.SyncSvcImpl{DB: client, Quiz: quizSvc},
+	}
+	return nil
+}
+
+// Wait blocks until the background workers, whose context must be done, and the running jobs of the services
+// finish. It returns false if they didn't before the timeout, which waits without limit when it is not positive
+func Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		svc.Mail.Wait()
+		svc.Notification.Wait()
+		svc.VerificationCode.Wait()
+		svc.Export.Wait()
+		svc.Sync.Wait()
+		close(done)
+	}()
+	if timeout <= 0 {
+		<-done
+		return true
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// loadBreachedList opens the configured breached password list. Without it, the rest of the password
+// policy is still applied, unless the list is required
+func loadBreachedList() (*password.BreachedList, error) {
+	policy := conf.Get().PasswordPolicy
+	path := conf.ResolvePath(policy.BreachedListFile)
+	if path == "" {
+		if policy.RequireBreachedList {
+			return nil, errors.New("the breached password list is required but no file is configured")
+		}
+		return nil, nil
+	}
+	list, err := password.LoadBreachedList(path)
+	if err != nil {
+		if policy.RequireBreachedList {
+			return nil, fmt.Errorf("error opening the breached password list: %w", err)
+		}
+		log.Warn().Err(err).Str("path", path).Msg("Error opening the breached password list")
+		return nil, nil
 	}
+	log.Info().Int64("bytes", list.Size()).Msg("Breached password list opened")
+	return list, nil
 }
@@ -9,12 +9,17 @@ type CreateForm struct {
 }
 
 type UpdateForm struct {
-	Id       uuid.UUID `json:"id" binding:"required"`
-	Password *string   `json:"password"`
+	Id        uuid.UUID `json:"id" binding:"required"`
+	Validated *bool     `json:"validated"`
+	Disabled  *bool     `json:"disabled"`
+	Role      *string   `json:"role"`
 }
 
 type SearchForm struct {
-	Name     *string `json:"name"`
-	Page     int     `json:"page"`
-	PageSize int     `json:"pageSize"`
+	Name      *string `json:"name"`
+	Role      *string `json:"role"`
+	Validated *bool   `json:"validated"`
+	Disabled  *bool   `json:"disabled"`
+	Page      int     `json:"page"`
+	PageSize  int     `json:"pageSize"`
 }
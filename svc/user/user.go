@@ -2,66 +2,128 @@ package user
 
 import (
 	"context"
+	"vocablo/customerrors"
 	"vocablo/ent"
 	"vocablo/ent/user"
+	"vocablo/svc/audit"
+	"vocablo/utils"
 
+	"entgo.io/ent/dialect/sql"
 	"github.com/google/uuid"
 )
 
 type UserSvc interface {
 	Create(ctx context.Context, form CreateForm) (*ent.User, error)
-	// Search(ctx context.Context, form SearchForm) (*utils.Page, error)
+	Update(ctx context.Context, form UpdateForm) (*ent.User, error)
+	Search(ctx context.Context, form SearchForm) (*utils.Page[*ent.User], error)
 	Get(ctx context.Context, userId uuid.UUID) (*ent.User, error)
 	GetByUsername(ctx context.Context, username string) (*ent.User, error)
 	Delete(ctx context.Context, userId uuid.UUID) error
 }
 
 type UserSvcImpl struct {
-	DB *ent.Client
+	DB    *ent.Client
+	Audit audit.AuditSvc
 }
 
 func (s *UserSvcImpl) Create(ctx context.Context, form CreateForm) (*ent.User, error) {
 	return s.DB.User.Create().SetUsername(form.Username).SetPassword(form.Password).SetEmail(form.Email).Save(ctx)
 }
 
+// Update is used by the admins to manage the accounts
+func (s *UserSvcImpl) Update(ctx context.Context, form UpdateForm) (*ent.User, error) {
+	_, err := s.DB.User.Get(ctx, form.Id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.NotFoundError{Resource: "User"}
+		}
+		return nil, err
+	}
+	//An admin can't lock himself out
+	if form.Id == ctx.Value(utils.UserIdKey).(uuid.UUID) && ((form.Disabled != nil && *form.Disabled) ||
+		(form.Role != nil && *form.Role != utils.ADMIN_ROLE)) {
+		return nil, customerrors.NotAllowedResourceError{}
+	}
+	updateBuilder := s.DB.User.UpdateOneID(form.Id)
+	if form.Validated != nil {
+		updateBuilder.SetValidated(*form.Validated)
+	}
+	if form.Disabled != nil {
+		updateBuilder.SetDisabled(*form.Disabled)
+		if *form.Disabled {
+			//The sessions of a disabled user are revoked
+			updateBuilder.AddSessionVersion(1)
+		}
+	}
+	if form.Role != nil {
+		if *form.Role != utils.USER_ROLE && *form.Role != utils.ADMIN_ROLE {
+			return nil, customerrors.InvalidRoleError{}
+		}
+		updateBuilder.SetRole(*form.Role)
+	}
+	updatedUser, err := updateBuilder.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if form.Disabled != nil && *form.Disabled {
+		s.Audit.Record(ctx, audit.Event{Type: utils.TOKEN_REVOCATION_EVENT, UserId: &updatedUser.ID, Detail: "account disabled by an admin"})
+	}
+	return updatedUser, nil
+}
+
 func (s *UserSvcImpl) Get(ctx context.Context, userId uuid.UUID) (*ent.User, error) {
-	return s.DB.User.Get(ctx, userId)
+	foundUser, err := s.DB.User.Get(ctx, userId)
+	if ent.IsNotFound(err) {
+		return nil, customerrors.NotFoundError{Resource: "User"}
+	}
+	return foundUser, err
 }
 
 func (s *UserSvcImpl) GetByUsername(ctx context.Context, username string) (*ent.User, error) {
 	return s.DB.User.Query().Where(user.UsernameEQ(username)).Only(ctx)
 }
 
-// func (s *UserSvcImpl) Search(ctx context.Context, form SearchForm) (*utils.Page, error) {
-// 	query := s.DB.User.Query()
-// 	var conditions []predicate.User
-
-// 	offset := 0
-// 	limit := 1000
-
-// 	if form.PageSize > 0 {
-// 		offset = form.PageSize * form.Page
-// 		limit = form.PageSize
-// 	}
-// 	if form.Name != nil {
-// 		conditions = append(conditions, user.UsernameContains(*form.Name))
-// 	}
-
-// 	totalRows, err := query.Where(user.And(conditions...)).Count(ctx)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	content, err := query.Where(user.And(conditions...)).Offset(offset).Limit(limit).All(ctx)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	totalPages := int(math.Ceil(float64(totalRows) / float64(form.PageSize)))
-// 	page := utils.Page{Content: content, TotalRows: totalRows, TotalPages: totalPages}
-// 	return &page, err
-// }
+func (s *UserSvcImpl) Search(ctx context.Context, form SearchForm) (*utils.Page[*ent.User], error) {
+	if form.Page <= 0 {
+		form.Page = 0
+	}
+	if form.PageSize <= 0 {
+		form.PageSize = 10
+	}
+	query := s.DB.User.Query()
+	if form.Name != nil && *form.Name != "" {
+		query = query.Where(user.Or(user.UsernameContainsFold(*form.Name), user.EmailContainsFold(*form.Name)))
+	}
+	if form.Role != nil {
+		query = query.Where(user.RoleEQ(*form.Role))
+	}
+	if form.Validated != nil {
+		query = query.Where(user.ValidatedEQ(*form.Validated))
+	}
+	if form.Disabled != nil {
+		query = query.Where(user.DisabledEQ(*form.Disabled))
+	}
+	total, err := query.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := utils.Page[*ent.User]{PageNumber: form.Page, NElements: total}
+	if total > (form.Page+1)*form.PageSize {
+		page.HasNext = true
+	}
+	users, err := query.Offset(form.Page * form.PageSize).Limit(form.PageSize).
+		Order(user.ByCreationDate(sql.OrderDesc())).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page.Content = users
+	return &page, nil
+}
 
 func (s *UserSvcImpl) Delete(ctx context.Context, userId uuid.UUID) error {
 	err := s.DB.User.DeleteOneID(userId).Exec(ctx)
+	if ent.IsNotFound(err) {
+		return customerrors.NotFoundError{Resource: "User"}
+	}
 	return err
 }
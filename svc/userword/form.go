@@ -12,6 +12,19 @@ type UpdateForm struct {
 	ID          string               `json:"id" binding:"required"`
 	Term        *string              `json:"term" `
 	Definitions *[]schema.Definition `json:"definitions" `
+	// Versions are the ones the word can be in to be updated, from the If-Match header. They are required, an
+	// update without them is rejected
+	Versions []int `json:"-"`
+}
+
+// UpdateHeader are the preconditions of an update, the ETag of the version of the word that was changed
+type UpdateHeader struct {
+	IfMatch string `header:"If-Match" binding:"required"`
+}
+
+// GetHeader has the ETag of the version of the word the client has, to not send it again if it didn't change
+type GetHeader struct {
+	IfNoneMatch string `header:"If-None-Match"`
 }
 
 type SearchForm struct {
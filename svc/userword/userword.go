@@ -3,11 +3,14 @@ package userword
 import (
 	"context"
 	"fmt"
+	"time"
 	"vocablo/customerrors"
 	"vocablo/ent"
 	"vocablo/ent/language"
 	"vocablo/ent/user"
 	"vocablo/ent/userword"
+	"vocablo/schema"
+	"vocablo/tracing"
 	"vocablo/utils"
 
 	"entgo.io/ent/dialect/sql"
@@ -19,6 +22,8 @@ type UserWordSvc interface {
 	Update(ctx context.Context, form UpdateForm) (*ent.UserWord, error)
 	Get(ctx context.Context, id string) (*ent.UserWord, error)
 	Search(ctx context.Context, form SearchForm) (*utils.Page[*ent.UserWord], error)
+	Query(ctx context.Context, form SearchForm) *ent.UserWordQuery
+	Progress(ctx context.Context) (*schema.UserWordProgress, error)
 	Delete(ctx context.Context, id string) error
 }
 
@@ -32,14 +37,32 @@ func (s *UserWordSvcImpl) Create(ctx context.Context, form CreateForm) (*ent.Use
 	}
 	lang, err := s.DB.Language.Query().Where(language.CodeEQ(form.Lang)).Only(ctx)
 	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.LanguageNotFoundError{Code: form.Lang}
+		}
 		return nil, err
 	}
 	userID := ctx.Value(utils.UserIdKey).(uuid.UUID)
-	userWord, err := s.DB.UserWord.Create().SetTerm(form.Term).SetDefinitions(form.Definitions).SetLangID(lang.ID).SetUserID(userID).Save(ctx)
+	clientTx, err := s.DB.Tx(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return userWord, nil
+	version, err := NextVersion(ctx, clientTx, userID)
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	userWord, err := clientTx.UserWord.Create().SetTerm(form.Term).SetDefinitions(form.Definitions).SetLangID(lang.ID).
+		SetUserID(userID).SetCreationVersion(version).SetVersion(version).Save(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	err = clientTx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return userWord.Unwrap(), nil
 }
 
 func (s *UserWordSvcImpl) Update(ctx context.Context, form UpdateForm) (*ent.UserWord, error) {
@@ -51,8 +74,11 @@ func (s *UserWordSvcImpl) Update(ctx context.Context, form UpdateForm) (*ent.Use
 	if err != nil {
 		return nil, err
 	}
-	userWord, err := s.DB.UserWord.Query().Where(userword.ID(uuidId)).WithUser().Only(ctx)
+	userWord, err := s.DB.UserWord.Query().Where(userword.ID(uuidId), userword.DeletedAtIsNil()).WithUser().Only(ctx)
 	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.NotFoundError{Resource: "Word"}
+		}
 		return nil, err
 	}
 	userIdKey := ctx.Value(utils.UserIdKey)
@@ -61,26 +87,61 @@ func (s *UserWordSvcImpl) Update(ctx context.Context, form UpdateForm) (*ent.Use
 		return nil, customerrors.NotAllowedResourceError{}
 	}
 
-	updateBuilder := s.DB.UserWord.UpdateOneID(uuidId)
+	if form.Term != nil && (*form.Term) == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	if form.Definitions != nil && len(*form.Definitions) == 0 {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	//An update without the versions it applies to could overwrite a concurrent change
+	if form.Versions == nil {
+		return nil, customerrors.PreconditionRequiredError{}
+	}
 
+	clientTx, err := s.DB.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := NextVersion(ctx, clientTx, userWord.Edges.User.ID)
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	//The version is checked when updating, so a concurrent change can't be overwritten
+	updateBuilder := clientTx.UserWord.UpdateOneID(uuidId).SetVersion(version).
+		Where(userword.VersionIn(form.Versions...), userword.DeletedAtIsNil())
 	if form.Term != nil {
-		if (*form.Term) == "" {
-			return nil, customerrors.EmptyFormFieldsError{}
-		}
 		updateBuilder.SetTerm(*form.Term)
 	}
 	if form.Definitions != nil {
-		if len(*form.Definitions) == 0 {
-			return nil, customerrors.EmptyFormFieldsError{}
-		}
 		updateBuilder.SetDefinitions(*form.Definitions)
 	}
 
 	updatedUserWord, err := updateBuilder.Save(ctx)
 	if err != nil {
+		clientTx.Rollback()
+		if ent.IsNotFound(err) {
+			return nil, s.staleVersion(ctx, uuidId)
+		}
 		return nil, err
 	}
-	return updatedUserWord, nil
+	err = clientTx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return updatedUserWord.Unwrap(), nil
+}
+
+// staleVersion returns the error of an update of an old version of the word, with the current one
+func (s *UserWordSvcImpl) staleVersion(ctx context.Context, id uuid.UUID) error {
+	current, err := s.DB.UserWord.Query().Where(userword.ID(id), userword.DeletedAtIsNil()).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return customerrors.NotFoundError{Resource: "Word"}
+		}
+		return err
+	}
+	return customerrors.StaleVersionError{Version: current.Version, Current: current}
 }
 
 func (s *UserWordSvcImpl) Get(ctx context.Context, id string) (*ent.UserWord, error) {
@@ -88,10 +149,10 @@ func (s *UserWordSvcImpl) Get(ctx context.Context, id string) (*ent.UserWord, er
 	if err != nil {
 		return nil, err
 	}
-	userWord, err := s.DB.UserWord.Query().Where(userword.ID(uuidId)).WithUser().Only(ctx)
+	userWord, err := s.DB.UserWord.Query().Where(userword.ID(uuidId), userword.DeletedAtIsNil()).WithUser().Only(ctx)
 	if err != nil {
 		if _, ok := err.(*ent.NotFoundError); ok {
-			return nil, customerrors.NotFoundError{}
+			return nil, customerrors.NotFoundError{Resource: "Word"}
 		}
 		return nil, err
 	}
@@ -102,28 +163,16 @@ func (s *UserWordSvcImpl) Get(ctx context.Context, id string) (*ent.UserWord, er
 	return userWord, nil
 }
 
-func (s *UserWordSvcImpl) Search(ctx context.Context, form SearchForm) (*utils.Page[*ent.UserWord], error) {
+func (s *UserWordSvcImpl) Search(ctx context.Context, form SearchForm) (result *utils.Page[*ent.UserWord], err error) {
+	ctx, span := tracing.Start(ctx, "UserWordSvc.Search")
+	defer tracing.End(span, &err)
 	if form.Page <= 0 {
 		form.Page = 0
 	}
 	if form.PageSize <= 0 {
 		form.PageSize = 10
 	}
-	//we only allow the user to search his own words
-	query := s.DB.UserWord.Query().Where(userword.HasUserWith(user.IDEQ(ctx.Value(utils.UserIdKey).(uuid.UUID))))
-	if form.Term != nil && *form.Term != "" {
-		query = query.Where(userword.TermContainsFold(*form.Term))
-	}
-	if form.Lang != nil {
-		query = query.Where(userword.HasLangWith(language.CodeEqualFold(*form.Lang)))
-	}
-	if form.Learned != nil {
-		if *form.Learned {
-			query = query.Where(userword.LearningProgressGTE(100))
-		} else {
-			query = query.Where(userword.LearningProgressLT(100))
-		}
-	}
+	query := s.Query(ctx, form)
 	total, err := query.Count(ctx)
 	if err != nil {
 		return nil, err
@@ -164,21 +213,81 @@ func (s *UserWordSvcImpl) Search(ctx context.Context, form SearchForm) (*utils.P
 	return &page, nil
 }
 
+// Query returns the query of the words of the user of the context that match the filters of the search. The
+// pagination and order of the form are left to the caller
+func (s *UserWordSvcImpl) Query(ctx context.Context, form SearchForm) *ent.UserWordQuery {
+	//we only allow the user to search his own words
+	query := s.DB.UserWord.Query().Where(userword.HasUserWith(user.IDEQ(ctx.Value(utils.UserIdKey).(uuid.UUID))),
+		userword.DeletedAtIsNil())
+	if form.Term != nil && *form.Term != "" {
+		query = query.Where(userword.TermContainsFold(*form.Term))
+	}
+	if form.Lang != nil {
+		query = query.Where(userword.HasLangWith(language.CodeEqualFold(*form.Lang)))
+	}
+	if form.Learned != nil {
+		if *form.Learned {
+			query = query.Where(userword.LearningProgressGTE(100))
+		} else {
+			query = query.Where(userword.LearningProgressLT(100))
+		}
+	}
+	return query
+}
+
+// Progress counts the learned and not learned words of the user of the context
+func (s *UserWordSvcImpl) Progress(ctx context.Context) (*schema.UserWordProgress, error) {
+	total, err := s.Query(ctx, SearchForm{}).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	learned, err := s.Query(ctx, SearchForm{Learned: utils.GetBoolPointer(true)}).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.UserWordProgress{TotalWords: total, LearnedWords: learned, UnlearnedWords: total - learned}, nil
+}
+
+// Delete keeps the word as a tombstone, for the other devices of the user to delete it when they sync
 func (s *UserWordSvcImpl) Delete(ctx context.Context, id string) error {
 	uuidId, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
-	userWord, err := s.DB.UserWord.Query().Where(userword.ID(uuidId)).WithUser().Only(ctx)
+	userWord, err := s.DB.UserWord.Query().Where(userword.ID(uuidId), userword.DeletedAtIsNil()).WithUser().Only(ctx)
 	if err != nil {
+		if ent.IsNotFound(err) {
+			return customerrors.NotFoundError{Resource: "Word"}
+		}
 		return err
 	}
 	if userWord.Edges.User.ID != ctx.Value(utils.UserIdKey).(uuid.UUID) {
 		return customerrors.NotAllowedResourceError{}
 	}
-	err = s.DB.UserWord.DeleteOneID(uuidId).Exec(ctx)
+	clientTx, err := s.DB.Tx(ctx)
 	if err != nil {
 		return err
 	}
-	return nil
+	version, err := NextVersion(ctx, clientTx, userWord.Edges.User.ID)
+	if err != nil {
+		clientTx.Rollback()
+		return err
+	}
+	err = clientTx.UserWord.UpdateOneID(uuidId).SetDeletedAt(time.Now()).SetVersion(version).Exec(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return err
+	}
+	return clientTx.Commit()
+}
+
+// NextVersion numbers a change of the words of a user in the transaction that makes it, incrementing their sync
+// version. The row of the user stays locked until the transaction ends, so the changes are committed in the order
+// of their versions and a sync never skips one
+func NextVersion(ctx context.Context, clientTx *ent.Tx, userId uuid.UUID) (int, error) {
+	syncUser, err := clientTx.User.UpdateOneID(userId).AddSyncVersion(1).Save(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return syncUser.SyncVersion, nil
 }
@@ -3,6 +3,8 @@ package verificationcode
 type CreateForm struct {
 	Type     string `json:"type" binding:"required"`
 	Username string `json:"username" binding:"required"`
+	// Only for email change codes, the new address where the code is sent
+	NewEmail string `json:"newEmail"`
 }
 
 type UseForm struct {
@@ -11,3 +13,14 @@ type UseForm struct {
 	Code     string `json:"code" binding:"required"`
 	NewPass  string `json:"newPass"`
 }
+
+type MagicLinkForm struct {
+	Email string `json:"email" binding:"required"`
+	// Random value stored in the browser requesting the link
+	Nonce string `json:"-"`
+}
+
+type UseMagicLinkForm struct {
+	Token string `json:"token" binding:"required"`
+	Nonce string `json:"-"`
+}
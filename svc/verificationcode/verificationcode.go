@@ -2,8 +2,8 @@ package verificationcode
 
 import (
 	"context"
-	"fmt"
-	"math/rand"
+	"crypto/subtle"
+	"sync"
 	"time"
 	"vocablo/conf"
 	"vocablo/customerrors"
@@ -11,33 +11,63 @@ import (
 	"vocablo/ent/predicate"
 	"vocablo/ent/user"
 	"vocablo/ent/verificationcode"
+	"vocablo/svc/audit"
 	"vocablo/svc/mail"
+	"vocablo/svc/password"
 	"vocablo/utils"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type VerificationCodeSvc interface {
 	Create(ctx context.Context, form CreateForm, transaction *ent.Tx) error
 	UseCode(ctx context.Context, form UseForm) error
+	CreateMagicLink(ctx context.Context, form MagicLinkForm) error
+	UseMagicLink(ctx context.Context, form UseMagicLinkForm) (*ent.User, error)
 	Get(ctx context.Context, verificationCodeId uuid.UUID) (*ent.VerificationCode, error)
 	Delete(ctx context.Context, verificationCodeId uuid.UUID) error
+	Purge(ctx context.Context) (int, error)
+	SchedulePurge(ctx context.Context, interval time.Duration)
+	// Wait blocks until the scheduled purge stops, once its context is done
+	Wait()
 }
 
 type VerificationCodeSvcImpl struct {
-	DB   *ent.Client
-	Mail mail.MailSvc
+	DB       *ent.Client
+	Mail     mail.MailSvc
+	Audit    audit.AuditSvc
+	Password password.PasswordSvc
+	workers  sync.WaitGroup
+}
+
+const defaultCodeLength = 6
+const defaultCodeTTL = 15 * time.Minute
+
+// codeConf returns the length and time to live configured for the code type
+func codeConf(codeType string) (int, time.Duration) {
+	length, ttl := defaultCodeLength, defaultCodeTTL
+	typeConf, ok := conf.Get().VerificationCodes.Types[codeType]
+	if ok && typeConf.Length > 0 {
+		length = typeConf.Length
+	}
+	if ok && typeConf.TTL > 0 {
+		ttl = typeConf.TTL
+	}
+	return length, ttl
 }
 
 func (s *VerificationCodeSvcImpl) Create(ctx context.Context, form CreateForm, transaction *ent.Tx) error {
-	expireDate := time.Now().Add(time.Minute * 15)
-	code := rand.Intn((1000000))
-	codeStr := fmt.Sprintf("%06d", code)
+	length, ttl := codeConf(form.Type)
+	expireDate := time.Now().Add(ttl)
+	codeStr, err := utils.GenerateNumericCode(length)
+	if err != nil {
+		return err
+	}
 	// If transaction is not nil, it means that the transaction is being managed by another function, and we should not commit or rollback it
 	var clientTx *ent.Tx
 	var externalTx bool
-	var err error
 
 	if transaction != nil {
 		clientTx = transaction
@@ -49,7 +79,7 @@ func (s *VerificationCodeSvcImpl) Create(ctx context.Context, form CreateForm, t
 		}
 	}
 
-	user, err := clientTx.User.Query().Where(user.UsernameEQ(form.Username)).First(ctx)
+	codeUser, err := clientTx.User.Query().Where(user.UsernameEQ(form.Username)).First(ctx)
 	if err != nil {
 		//If the user is not found, we should rollback the transaction and return no error to avoid username enumeration
 		if !externalTx {
@@ -58,8 +88,9 @@ func (s *VerificationCodeSvcImpl) Create(ctx context.Context, form CreateForm, t
 		return nil
 	}
 
-	verificationCode, err := clientTx.VerificationCode.Create().SetCode(codeStr).
-		SetUserID(user.ID).SetType(form.Type).SetExpireDate(expireDate).Save(ctx)
+	//Only the last code of each type can be used, so the previous unused ones are invalidated
+	_, err = clientTx.VerificationCode.Delete().Where(verificationcode.HasUserWith(user.IDEQ(codeUser.ID)),
+		verificationcode.TypeEQ(form.Type), verificationcode.UsedEQ(false)).Exec(ctx)
 	if err != nil {
 		if !externalTx {
 			clientTx.Rollback()
@@ -67,18 +98,25 @@ func (s *VerificationCodeSvcImpl) Create(ctx context.Context, form CreateForm, t
 		return err
 	}
 
-	var mailSubject, mailBody string
-	if form.Type == utils.VALIDATION_TYPE {
-		mailSubject = conf.Get().Mail.Texts.ValidateEmail.Subject
-		mailBody = conf.Get().Mail.Texts.ValidateEmail.Body
+	//The code is only stored hashed, the plain one is only sent by email
+	verificationCodeCreate := clientTx.VerificationCode.Create().SetCode(utils.HashCode(codeStr)).
+		SetUserID(codeUser.ID).SetType(form.Type).SetExpireDate(expireDate)
+	mailTo := codeUser.Email
+	if form.Type == utils.EMAIL_TYPE {
+		//The code is sent to the new address, to check that it belongs to the user
+		verificationCodeCreate.SetNewEmail(form.NewEmail)
+		mailTo = form.NewEmail
 	}
-	if form.Type == utils.RESET_TYPE {
-		mailSubject = conf.Get().Mail.Texts.ValidateEmail.Subject
-		mailBody = conf.Get().Mail.Texts.ValidateEmail.Body
-
+	_, err = verificationCodeCreate.Save(ctx)
+	if err != nil {
+		if !externalTx {
+			clientTx.Rollback()
+		}
+		return err
 	}
-	err = s.Mail.SendMail(user.Email, mailSubject, fmt.Sprintf(mailBody, verificationCode.Code))
 
+	//The mail templates of the codes are named after their type
+	err = s.Mail.Send(ctx, clientTx, mailTo, codeUser.Locale, form.Type, mail.TemplateData{Username: codeUser.Username, Code: codeStr, NewEmail: form.NewEmail})
 	if err != nil {
 		if !externalTx {
 			clientTx.Rollback()
@@ -94,7 +132,7 @@ func (s *VerificationCodeSvcImpl) Create(ctx context.Context, form CreateForm, t
 func (s *VerificationCodeSvcImpl) Get(ctx context.Context, verificationCodeId uuid.UUID) (*ent.VerificationCode, error) {
 	verifCode, err := s.DB.VerificationCode.Get(ctx, verificationCodeId)
 	if err != nil {
-		return nil, &customerrors.NotFoundError{Resource: "Verification code: " + verificationCodeId.String()}
+		return nil, customerrors.NotFoundError{Resource: "Verification code: " + verificationCodeId.String()}
 	}
 	return verifCode, nil
 }
@@ -112,15 +150,18 @@ func (s *VerificationCodeSvcImpl) validateAccount(clientTx *ent.Tx, ctx context.
 }
 
 func (s *VerificationCodeSvcImpl) resetPassword(clientTx *ent.Tx, ctx context.Context, username string, newPass string) error {
-	userId, err := clientTx.User.Query().Where(user.UsernameEQ(username)).FirstID(ctx)
+	resetUser, err := clientTx.User.Query().Where(user.UsernameEQ(username)).First(ctx)
 	if err != nil {
 		return customerrors.NotFoundError{Resource: "User: " + username}
 	}
+	if err := s.Password.Check(newPass, resetUser.Username, resetUser.Email); err != nil {
+		return err
+	}
 	bytesPass, err := bcrypt.GenerateFromPassword([]byte(newPass), 14)
 	if err != nil {
 		return err
 	}
-	_, err = clientTx.User.UpdateOneID(userId).SetPassword(string(bytesPass[:])).Save(ctx)
+	_, err = clientTx.User.UpdateOne(resetUser).SetPassword(string(bytesPass[:])).AddSessionVersion(1).Save(ctx)
 	if err != nil {
 		return err
 	}
@@ -128,10 +169,30 @@ func (s *VerificationCodeSvcImpl) resetPassword(clientTx *ent.Tx, ctx context.Co
 
 }
 
+// changeEmail replaces the user email with the one stored in the code and returns the user as it was before
+func (s *VerificationCodeSvcImpl) changeEmail(clientTx *ent.Tx, ctx context.Context, username string, newEmail string) (*ent.User, error) {
+	changedUser, err := clientTx.User.Query().Where(user.UsernameEQ(username)).First(ctx)
+	if err != nil {
+		return nil, customerrors.NotFoundError{Resource: "User: " + username}
+	}
+	alreadyExistMail, err := clientTx.User.Query().Where(user.EmailEQ(newEmail)).Exist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyExistMail {
+		return nil, customerrors.EmailAlreadyInUseError{}
+	}
+	_, err = clientTx.User.UpdateOne(changedUser).SetEmail(newEmail).AddSessionVersion(1).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return changedUser, nil
+}
+
 func (s *VerificationCodeSvcImpl) UseCode(ctx context.Context, form UseForm) error {
 	clientTx, err := s.DB.Tx(ctx)
 	if err != nil {
-		utils.InternalError(err)
+		return err
 	}
 	query := clientTx.VerificationCode.Query()
 	var conditions []predicate.VerificationCode
@@ -142,20 +203,36 @@ func (s *VerificationCodeSvcImpl) UseCode(ctx context.Context, form UseForm) err
 	verificationCode, err := query.Where(verificationcode.And(conditions...)).Order(ent.Desc(verificationcode.FieldCreationDate)).First(ctx)
 	if err != nil {
 		clientTx.Rollback()
-		return customerrors.NotFoundError{Resource: "Verification code for user: " + form.Username}
+		//A user without codes is answered as a wrong code, to not reveal which users exist
+		if ent.IsNotFound(err) {
+			return customerrors.IncorrectValidationCodeError{}
+		}
+		return err
 	}
-	if verificationCode.Code == form.Code && verificationCode.Used {
+	codeMatches := utils.CompareCode(verificationCode.Code, form.Code)
+	if codeMatches && verificationCode.Used {
 		clientTx.Rollback()
 		return customerrors.AlreadyUsedValidationCodeError{}
 	}
-	if verificationCode.Code == form.Code && verificationCode.ExpireDate.Before(time.Now()) {
+	if codeMatches && verificationCode.ExpireDate.Before(time.Now()) {
 		clientTx.Rollback()
 		return customerrors.ExpiredValidationCodeError{}
 	}
-	if form.Code != verificationCode.Code {
+	if !codeMatches {
 		clientTx.Rollback()
 		return customerrors.IncorrectValidationCodeError{}
 	}
+	//The code is spent before applying it, and the update only succeeds once, even with concurrent requests
+	updated, err := clientTx.VerificationCode.Update().Where(verificationcode.IDEQ(verificationCode.ID),
+		verificationcode.UsedEQ(false)).SetUsed(true).Save(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return err
+	}
+	if updated == 0 {
+		clientTx.Rollback()
+		return customerrors.AlreadyUsedValidationCodeError{}
+	}
 	if form.Type == utils.VALIDATION_TYPE {
 		err = s.validateAccount(clientTx, ctx, form.Username)
 		if err != nil {
@@ -170,19 +247,154 @@ func (s *VerificationCodeSvcImpl) UseCode(ctx context.Context, form UseForm) err
 			return err
 		}
 	}
-	_, err = clientTx.VerificationCode.UpdateOneID(verificationCode.ID).SetUsed(true).Save(ctx)
+	var previousUser *ent.User
+	if form.Type == utils.EMAIL_TYPE {
+		previousUser, err = s.changeEmail(clientTx, ctx, form.Username, verificationCode.NewEmail)
+		if err != nil {
+			clientTx.Rollback()
+			return err
+		}
+	}
+	err = clientTx.Commit()
 	if err != nil {
-		clientTx.Rollback()
 		return err
 	}
-	clientTx.Commit()
+	switch form.Type {
+	case utils.VALIDATION_TYPE:
+		s.Audit.Record(ctx, audit.Event{Type: utils.VALIDATION_EVENT, Username: form.Username})
+	case utils.RESET_TYPE:
+		s.Audit.Record(ctx, audit.Event{Type: utils.PASSWORD_RESET_EVENT, Username: form.Username})
+		s.Audit.Record(ctx, audit.Event{Type: utils.TOKEN_REVOCATION_EVENT, Username: form.Username, Detail: "password reset"})
+	case utils.EMAIL_TYPE:
+		s.Audit.Record(ctx, audit.Event{Type: utils.EMAIL_CHANGE_EVENT, Username: form.Username})
+		s.Audit.Record(ctx, audit.Event{Type: utils.TOKEN_REVOCATION_EVENT, Username: form.Username, Detail: "email change"})
+	}
+	if form.Type == utils.EMAIL_TYPE {
+		//We warn the previous address, in case the change was not made by the owner
+		err = s.Mail.Send(ctx, nil, previousUser.Email, previousUser.Locale, mail.EMAIL_CHANGED_TEMPLATE,
+			mail.TemplateData{Username: previousUser.Username, NewEmail: verificationCode.NewEmail})
+		if err != nil {
+			log.Warn().Err(err).Msg("Error queuing the email change notification")
+		}
+	}
 	return nil
 }
 
+// CreateMagicLink emails a single-use login link to the user with that email. As with the other codes,
+// no error is returned when the user doesn't exist to avoid email enumeration
+func (s *VerificationCodeSvcImpl) CreateMagicLink(ctx context.Context, form MagicLinkForm) error {
+	if form.Email == "" || form.Nonce == "" {
+		return customerrors.EmptyFormFieldsError{}
+	}
+	linkUser, err := s.DB.User.Query().Where(user.EmailEQ(form.Email)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if linkUser.Disabled {
+		return nil
+	}
+	_, ttl := codeConf(utils.MAGIC_LINK_TYPE)
+	codeId := uuid.New()
+	token, err := utils.GenerateMagicLinkJWT(codeId.String(), linkUser.Username, utils.HashToken(form.Nonce), ttl)
+	if err != nil {
+		return err
+	}
+	clientTx, err := s.DB.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = clientTx.VerificationCode.Delete().Where(verificationcode.HasUserWith(user.IDEQ(linkUser.ID)),
+		verificationcode.TypeEQ(utils.MAGIC_LINK_TYPE), verificationcode.UsedEQ(false)).Exec(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return err
+	}
+	err = clientTx.VerificationCode.Create().SetID(codeId).SetCode(utils.HashToken(token)).SetUserID(linkUser.ID).
+		SetType(utils.MAGIC_LINK_TYPE).SetExpireDate(time.Now().Add(ttl)).Exec(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return err
+	}
+	err = s.Mail.Send(ctx, clientTx, linkUser.Email, linkUser.Locale, mail.MAGIC_LINK_TEMPLATE,
+		mail.TemplateData{Username: linkUser.Username, Url: utils.FrontUrl() + "/magic-link/" + token})
+	if err != nil {
+		clientTx.Rollback()
+		return err
+	}
+	return clientTx.Commit()
+}
+
+// UseMagicLink checks the login link token against the nonce of the browser and marks it as used, returning
+// the user to log in
+func (s *VerificationCodeSvcImpl) UseMagicLink(ctx context.Context, form UseMagicLinkForm) (*ent.User, error) {
+	claims, err := utils.ValidateMagicLinkToken(form.Token)
+	if err != nil || form.Nonce == "" ||
+		subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(utils.HashToken(form.Nonce))) != 1 {
+		return nil, customerrors.InvalidMagicLinkError{}
+	}
+	codeId, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, customerrors.InvalidMagicLinkError{}
+	}
+	clientTx, err := s.DB.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	magicLink, err := clientTx.VerificationCode.Query().Where(verificationcode.IDEQ(codeId),
+		verificationcode.TypeEQ(utils.MAGIC_LINK_TYPE)).WithUser().Only(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		if ent.IsNotFound(err) {
+			return nil, customerrors.InvalidMagicLinkError{}
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(magicLink.Code), []byte(utils.HashToken(form.Token))) != 1 ||
+		magicLink.ExpireDate.Before(time.Now()) {
+		clientTx.Rollback()
+		return nil, customerrors.InvalidMagicLinkError{}
+	}
+	//The update only succeeds once, even with concurrent requests
+	updated, err := clientTx.VerificationCode.Update().Where(verificationcode.IDEQ(codeId), verificationcode.UsedEQ(false)).
+		SetUsed(true).Save(ctx)
+	if err != nil {
+		clientTx.Rollback()
+		return nil, err
+	}
+	if updated == 0 {
+		clientTx.Rollback()
+		return nil, customerrors.AlreadyUsedValidationCodeError{}
+	}
+	linkUser := magicLink.Edges.User
+	if !linkUser.Validated {
+		//Opening the link proves the user owns the email, but not that they registered the account, so whoever did
+		//loses its password and sessions
+		password, err := utils.RandomPasswordHash()
+		if err != nil {
+			clientTx.Rollback()
+			return nil, err
+		}
+		linkUser, err = clientTx.User.UpdateOne(linkUser).SetPassword(password).AddSessionVersion(1).
+			SetValidated(true).Save(ctx)
+		if err != nil {
+			clientTx.Rollback()
+			return nil, err
+		}
+	}
+	err = clientTx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return linkUser, nil
+}
+
 func (s *VerificationCodeSvcImpl) Delete(ctx context.Context, verificationCodeId uuid.UUID) error {
 	verificationCode := s.DB.VerificationCode.Query().Where(verificationcode.IDEQ(verificationCodeId)).FirstX(ctx)
 	if verificationCode == nil {
-		return &customerrors.NotFoundError{Resource: "Verification code: " + verificationCodeId.String()}
+		return customerrors.NotFoundError{Resource: "Verification code: " + verificationCodeId.String()}
 	}
 	err := s.DB.VerificationCode.DeleteOneID(verificationCodeId).Exec(ctx)
 	if err != nil {
@@ -190,3 +402,40 @@ func (s *VerificationCodeSvcImpl) Delete(ctx context.Context, verificationCodeId
 	}
 	return nil
 }
+
+// Purge deletes the codes that can't be used anymore, returning how many were deleted
+func (s *VerificationCodeSvcImpl) Purge(ctx context.Context) (int, error) {
+	return s.DB.VerificationCode.Delete().Where(verificationcode.Or(verificationcode.UsedEQ(true),
+		verificationcode.ExpireDateLT(time.Now()))).Exec(ctx)
+}
+
+// SchedulePurge runs Purge every interval until the context is cancelled
+func (s *VerificationCodeSvcImpl) SchedulePurge(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				//A purge already started is finished on shutdown
+				purged, err := s.Purge(context.WithoutCancel(ctx))
+				if err != nil {
+					log.Error().Err(err).Msg("Error purging the verification codes")
+					continue
+				}
+				log.Info().Int("purged", purged).Msg("Verification codes purged")
+			}
+		}
+	}()
+}
+
+func (s *VerificationCodeSvcImpl) Wait() {
+	s.workers.Wait()
+}
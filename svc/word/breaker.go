@@ -0,0 +1,86 @@
+package word
+
+import (
+	"sync"
+	"time"
+)
+
+// States of the circuit breaker
+const (
+	CIRCUIT_CLOSED    = "closed"
+	CIRCUIT_OPEN      = "open"
+	CIRCUIT_HALF_OPEN = "half_open"
+)
+
+// CircuitBreaker stops calling a failing dependency. After threshold consecutive failures it opens, and once
+// openDuration has passed a single call is let through to probe it: a success closes it again and a failure
+// keeps it open for another openDuration
+type CircuitBreaker struct {
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	failures     int
+	openedAt     time.Time
+	probing      bool
+}
+
+func NewCircuitBreaker(threshold int, openDuration time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if openDuration <= 0 {
+		openDuration = 30 * time.Second
+	}
+	return &CircuitBreaker{threshold: threshold, openDuration: openDuration}
+}
+
+func (b *CircuitBreaker) state() string {
+	if b.failures < b.threshold {
+		return CIRCUIT_CLOSED
+	}
+	if time.Since(b.openedAt) < b.openDuration {
+		return CIRCUIT_OPEN
+	}
+	return CIRCUIT_HALF_OPEN
+}
+
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state()
+}
+
+// Allow checks if the dependency can be called, which must be followed by a Success or a Failure
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state() {
+	case CIRCUIT_CLOSED:
+		return true
+	case CIRCUIT_HALF_OPEN:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
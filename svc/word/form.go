@@ -12,3 +12,9 @@ type SearchForm struct {
 	Term string `json:"term"`
 	Lang string `json:"lang"`
 }
+
+type UpdateForm struct {
+	ID          string               `json:"id" binding:"required"`
+	Term        *string              `json:"term"`
+	Definitions *[]schema.Definition `json:"definitions"`
+}
@@ -4,32 +4,53 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 	"vocablo/apischema"
+	"vocablo/conf"
 	"vocablo/customerrors"
 	"vocablo/ent"
 	"vocablo/ent/language"
 	"vocablo/ent/word"
+	"vocablo/metrics"
+	"vocablo/tracing"
 	"vocablo/utils"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type WordSvc interface {
 	Create(ctx context.Context, form CreateForm) (*ent.Word, error)
 	CreateBulk(ctx context.Context, forms []CreateForm) []*ent.Word
 	Search(ctx context.Context, lang string, term string) (result *utils.Page[*ent.Word], err error)
+	Update(ctx context.Context, form UpdateForm) (*ent.Word, error)
+	Delete(ctx context.Context, id string) error
+	// DictionaryState returns the state of the circuit breaker of the external dictionary
+	DictionaryState() string
 }
 
 type WordSvcImpl struct {
 	DB *ent.Client
+	// Breaker guards the calls to the external dictionary
+	Breaker *CircuitBreaker
 }
 
+const defaultDictionaryUrl = "https://api.dictionaryapi.dev/api/v2/entries"
+const defaultDictionaryTimeout = 5 * time.Second
+
 func (s *WordSvcImpl) Create(ctx context.Context, form CreateForm) (*ent.Word, error) {
 	if form.Term == "" || form.Lang == "" || form.Definitions == nil || len(form.Definitions) == 0 {
 		return nil, customerrors.EmptyFormFieldsError{}
 	}
 	lang, err := s.DB.Language.Query().Where(language.CodeEQ(form.Lang)).Only(ctx)
 	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.LanguageNotFoundError{Code: form.Lang}
+		}
 		return nil, err
 	}
 	word, err := s.DB.Word.Create().SetTerm(form.Term).SetLangID(lang.ID).Save(ctx)
@@ -66,6 +87,8 @@ func (s *WordSvcImpl) CreateBulk(ctx context.Context, forms []CreateForm) []*ent
 }
 
 func (s *WordSvcImpl) Search(ctx context.Context, lang string, term string) (result *utils.Page[*ent.Word], err error) {
+	ctx, span := tracing.Start(ctx, "WordSvc.Search", attribute.String("word.lang", lang))
+	defer tracing.End(span, &err)
 	if term == "" || lang == "" {
 		return nil, customerrors.EmptyFormFieldsError{}
 	}
@@ -77,7 +100,11 @@ func (s *WordSvcImpl) Search(ctx context.Context, lang string, term string) (res
 		log.Error().Msg(err.Error())
 		return nil, err
 	}
-	if len(words) == 0 {
+	span.SetAttributes(attribute.Bool("word.cached", len(words) > 0))
+	if len(words) > 0 {
+		metrics.DictionaryLookups.WithLabelValues(metrics.HIT_RESULT).Inc()
+	} else {
+		metrics.DictionaryLookups.WithLabelValues(metrics.MISS_RESULT).Inc()
 		log.Debug().Msg("Word not found in the database, searching in the API")
 		words, err = s.searchInApi(ctx, term, lang)
 		if err != nil {
@@ -89,15 +116,46 @@ func (s *WordSvcImpl) Search(ctx context.Context, lang string, term string) (res
 	return &page, nil
 }
 
-func (s *WordSvcImpl) searchInApi(ctx context.Context, term string, lang string) ([]*ent.Word, error) {
+func (s *WordSvcImpl) searchInApi(ctx context.Context, term string, lang string) (createdWords []*ent.Word, err error) {
 	if term == "" || lang == "" {
 		return nil, customerrors.EmptyFormFieldsError{}
 	}
-	resp, err := http.Get("https://api.dictionaryapi.dev/api/v2/entries/en/" + term)
+	ctx, span := tracing.Start(ctx, "WordSvc.searchInApi")
+	defer tracing.End(span, &err)
+	if !s.Breaker.Allow() {
+		return nil, customerrors.DictionaryUnavailableError{}
+	}
+	dictionaryConf := conf.Get().Dictionary
+	apiUrl := dictionaryConf.Url
+	if apiUrl == "" {
+		apiUrl = defaultDictionaryUrl
+	}
+	timeout := dictionaryConf.Timeout
+	if timeout <= 0 {
+		timeout = defaultDictionaryTimeout
+	}
+	//The transport propagates the trace context to the dictionary and traces the call
+	client := &http.Client{Timeout: timeout, Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl+"/en/"+url.PathEscape(term), nil)
 	if err != nil {
-		log.Error().Msg(err.Error())
 		return nil, err
 	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		metrics.DictionaryRequestDuration.WithLabelValues(metrics.ERROR_STATUS).Observe(time.Since(start).Seconds())
+		log.Error().Msg(err.Error())
+		s.Breaker.Failure()
+		return nil, customerrors.DictionaryUnavailableError{}
+	}
+	defer resp.Body.Close()
+	metrics.DictionaryRequestDuration.WithLabelValues(strconv.Itoa(resp.StatusCode)).Observe(time.Since(start).Seconds())
+	//The unknown words are answered with a 404, only the server errors count as failures
+	if resp.StatusCode >= 500 {
+		s.Breaker.Failure()
+		return nil, customerrors.DictionaryUnavailableError{}
+	}
+	s.Breaker.Success()
 	if resp.StatusCode != 200 {
 		return []*ent.Word{}, nil
 	}
@@ -107,6 +165,57 @@ func (s *WordSvcImpl) searchInApi(ctx context.Context, term string, lang string)
 		return nil, err
 	}
 	forms := ConvertApiResponseToWordForms(respObj)
-	createdWords := s.CreateBulk(ctx, forms)
+	createdWords = s.CreateBulk(ctx, forms)
 	return createdWords, nil
 }
+
+// Update is used by the admins to fix the dictionary entries
+func (s *WordSvcImpl) Update(ctx context.Context, form UpdateForm) (*ent.Word, error) {
+	if form.ID == "" {
+		return nil, customerrors.EmptyFormFieldsError{}
+	}
+	uuidId, err := uuid.Parse(form.ID)
+	if err != nil {
+		return nil, customerrors.NotFoundError{Resource: "Word"}
+	}
+	updateBuilder := s.DB.Word.UpdateOneID(uuidId)
+	if form.Term != nil {
+		if (*form.Term) == "" {
+			return nil, customerrors.EmptyFormFieldsError{}
+		}
+		updateBuilder.SetTerm(*form.Term)
+	}
+	if form.Definitions != nil {
+		if len(*form.Definitions) == 0 {
+			return nil, customerrors.EmptyFormFieldsError{}
+		}
+		updateBuilder.SetDefinitions(*form.Definitions)
+	}
+	updatedWord, err := updateBuilder.Save(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, customerrors.NotFoundError{Resource: "Word"}
+		}
+		return nil, err
+	}
+	return updatedWord, nil
+}
+
+func (s *WordSvcImpl) Delete(ctx context.Context, id string) error {
+	uuidId, err := uuid.Parse(id)
+	if err != nil {
+		return customerrors.NotFoundError{Resource: "Word"}
+	}
+	err = s.DB.Word.DeleteOneID(uuidId).Exec(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return customerrors.NotFoundError{Resource: "Word"}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *WordSvcImpl) DictionaryState() string {
+	return s.Breaker.State()
+}
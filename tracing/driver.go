@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"vocablo/metrics"
+
+	"entgo.io/ent/dialect"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Driver creates a span for every statement run by ent, in and out of transactions. The statements without a
+// span in the context, like the migrations, aren't traced to avoid filling the traces with roots
+type Driver struct {
+	dialect.Driver
+}
+
+func NewDriver(driver dialect.Driver) *Driver {
+	return &Driver{Driver: driver}
+}
+
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+	return traceQuery(ctx, d.Dialect(), query, func(ctx context.Context) error {
+		return d.Driver.Exec(ctx, query, args, v)
+	})
+}
+
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+	return traceQuery(ctx, d.Dialect(), query, func(ctx context.Context) error {
+		return d.Driver.Query(ctx, query, args, v)
+	})
+}
+
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, dialect: d.Dialect()}, nil
+}
+
+// BeginTx is used by ent when the transaction has options, if the driver supports them
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	beginner, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return d.Tx(ctx)
+	}
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, dialect: d.Dialect()}, nil
+}
+
+type Tx struct {
+	dialect.Tx
+	dialect string
+}
+
+func (t *Tx) Exec(ctx context.Context, query string, args, v any) error {
+	return traceQuery(ctx, t.dialect, query, func(ctx context.Context) error {
+		return t.Tx.Exec(ctx, query, args, v)
+	})
+}
+
+func (t *Tx) Query(ctx context.Context, query string, args, v any) error {
+	return traceQuery(ctx, t.dialect, query, func(ctx context.Context) error {
+		return t.Tx.Query(ctx, query, args, v)
+	})
+}
+
+// traceQuery runs the statement in a child span. Only the query is recorded, never its arguments, as they can
+// hold personal data
+func traceQuery(ctx context.Context, system string, query string, run func(ctx context.Context) error) error {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return run(ctx)
+	}
+	operation := metrics.StatementKind(query)
+	ctx, span := Start(ctx, "db."+operation)
+	span.SetAttributes(semconv.DBSystemKey.String(system), semconv.DBOperationName(operation), semconv.DBQueryText(query))
+	err := run(ctx)
+	End(span, &err)
+	return err
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"vocablo/conf"
+
+	"github.com/rs/zerolog/log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters of the spans
+const (
+	NONE_EXPORTER   = "none"
+	STDOUT_EXPORTER = "stdout"
+	OTLP_EXPORTER   = "otlp"
+)
+
+const tracerName = "vocablo"
+
+const defaultServiceName = "vocablo"
+
+// Setup configures the global tracer provider with the configured exporter, the W3C propagation of the trace
+// context and the trace IDs in the logs. It returns the function that flushes the pending spans on shutdown
+func Setup(ctx context.Context, tracingConf conf.TracingConf) (func(context.Context) error, error) {
+	log.Logger = log.Logger.Hook(LogHook{})
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch tracingConf.Exporter {
+	case "", NONE_EXPORTER:
+		return func(context.Context) error { return nil }, nil
+	case STDOUT_EXPORTER:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case OTLP_EXPORTER:
+		options := []otlptracehttp.Option{}
+		if tracingConf.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(tracingConf.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, errors.New("unknown tracing exporter: " + tracingConf.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	serviceName := tracingConf.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	sampleRatio := tracingConf.SampleRatio
+	if sampleRatio <= 0 || sampleRatio > 1 {
+		sampleRatio = 1
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start creates a child span of the one in the context, or a root one if there is none
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records the error, if any, and ends the span. It takes the address of the error so it can be deferred
+// with the named result of the traced function
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
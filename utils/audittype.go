@@ -0,0 +1,13 @@
+package utils
+
+const (
+	LOGIN_SUCCESS_EVENT    = "login_success"
+	LOGIN_FAILURE_EVENT    = "login_failure"
+	SIGN_UP_EVENT          = "sign_up"
+	VALIDATION_EVENT       = "account_validation"
+	PASSWORD_RESET_EVENT   = "password_reset"
+	PASSWORD_CHANGE_EVENT  = "password_change"
+	EMAIL_CHANGE_EVENT     = "email_change"
+	ACCOUNT_DELETION_EVENT = "account_deletion"
+	TOKEN_REVOCATION_EVENT = "token_revocation"
+)
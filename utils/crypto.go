@@ -1,8 +1,14 @@
 package utils
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
+	"fmt"
+	"math/big"
+	"vocablo/conf"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -29,3 +35,47 @@ func CompareHash(hash string, pass string) bool {
 	err := bcrypt.CompareHashAndPassword(byteHash, bytePass)
 	return err == nil
 }
+
+// RandomPasswordHash returns the hash of a random password, for the accounts whose password can only be set
+// with the forgotten password flow
+func RandomPasswordHash() (string, error) {
+	randomPass, err := GenerateRandomToken(64)
+	if err != nil {
+		return "", err
+	}
+	bytesPass, err := bcrypt.GenerateFromPassword([]byte(randomPass), 14)
+	if err != nil {
+		return "", err
+	}
+	return string(bytesPass[:]), nil
+}
+
+// HashToken returns the SHA-256 of a random token. Unlike passwords, tokens have enough entropy to not need
+// a slow hash, and a deterministic one allows searching them
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateNumericCode returns a random code of the given number of digits
+func GenerateNumericCode(length int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	code, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", length, code), nil
+}
+
+// HashCode returns the HMAC-SHA256 of a short code. The short codes have too little entropy for a plain
+// hash, so it is keyed with the server secret to prevent brute forcing them from a database dump
+func HashCode(code string) string {
+	mac := hmac.New(sha256.New, []byte(conf.Get().JwtKey))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CompareCode checks in constant time if the code matches the hash
+func CompareCode(hash string, code string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashCode(code))) == 1
+}
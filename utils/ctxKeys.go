@@ -3,7 +3,11 @@ package utils
 type CtxKey string
 
 const (
-	UserIdKey CtxKey = "userID"
-	CsrfKey   CtxKey = "csrf"
-	JwtKey    CtxKey = "jwtToken"
+	UserIdKey    CtxKey = "userID"
+	UserRoleKey  CtxKey = "userRole"
+	CsrfKey      CtxKey = "csrf"
+	JwtKey       CtxKey = "jwtToken"
+	RequestIdKey CtxKey = "requestID"
+	ClientIpKey  CtxKey = "clientIP"
+	UserAgentKey CtxKey = "userAgent"
 )
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"vocablo/customerrors"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errorMapping is how an error is answered. Without a message the one of the error is used, so the errors
+// whose text may have internal details must set it
+type errorMapping struct {
+	status  int
+	code    string
+	message string
+	data    interface{}
+}
+
+// mapError translates the errors the clients can act on. The rest are unexpected
+func mapError(err error) (errorMapping, bool) {
+	switch e := err.(type) {
+	case customerrors.EmptyFormFieldsError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.EMPTY_FORM_FIELDS}, true
+	case customerrors.InvalidCredentialsError:
+		return errorMapping{status: http.StatusUnauthorized, code: customerrors.INVALID_CREDENTIALS}, true
+	case customerrors.IncorrectCurrentPasswordError:
+		return errorMapping{status: http.StatusForbidden, code: customerrors.INVALID_CREDENTIALS}, true
+	case customerrors.NotValidatedAccountError:
+		return errorMapping{status: http.StatusForbidden, code: customerrors.NOT_VALIDATED_ACCOUNT}, true
+	case customerrors.DisabledAccountError:
+		return errorMapping{status: http.StatusForbidden, code: customerrors.DISABLED_ACCOUNT}, true
+	case customerrors.UserAlreadyValidatedError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.ALREADY_VALIDATED_ACCOUNT}, true
+	case customerrors.UsernameAlreadyInUseError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.USERNAME_ALREADY_IN_USE}, true
+	case customerrors.EmailAlreadyInUseError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.EMAIL_ALREADY_IN_USE}, true
+	case customerrors.WeakPasswordError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.WEAK_PASSWORD,
+			message: "The password doesn't meet the password policy", data: e.Rules}, true
+	case customerrors.AlreadyUsedValidationCodeError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.ALREADY_USED_VALIDATION_CODE}, true
+	case customerrors.ExpiredValidationCodeError:
+		return errorMapping{status: http.StatusGone, code: customerrors.EXPIRED_VALIDATION_CODE}, true
+	case customerrors.IncorrectValidationCodeError:
+		return errorMapping{status: http.StatusUnauthorized, code: customerrors.INCORRECT_VALIDATION_CODE}, true
+	case customerrors.InvalidMagicLinkError:
+		return errorMapping{status: http.StatusUnauthorized, code: customerrors.INVALID_MAGIC_LINK}, true
+	case customerrors.UnknownOIDCProviderError:
+		return errorMapping{status: http.StatusNotFound, code: customerrors.UNKNOWN_OIDC_PROVIDER}, true
+	case customerrors.InvalidOIDCStateError:
+		return errorMapping{status: http.StatusUnauthorized, code: customerrors.INVALID_OIDC_STATE}, true
+	case customerrors.OIDCAuthenticationError:
+		//The reason comes from the provider and is only logged
+		return errorMapping{status: http.StatusUnauthorized, code: customerrors.OIDC_AUTHENTICATION_FAILED,
+			message: "Authentication with the identity provider failed"}, true
+	case customerrors.NotVerifiedOIDCEmailError:
+		return errorMapping{status: http.StatusForbidden, code: customerrors.NOT_VERIFIED_OIDC_EMAIL}, true
+	case customerrors.NotAllowedResourceError:
+		return errorMapping{status: http.StatusForbidden, code: customerrors.NOT_ALLOWED_RESOURCE}, true
+	case customerrors.NotFoundError:
+		return errorMapping{status: http.StatusNotFound, code: customerrors.NOT_FOUND}, true
+	case customerrors.NotEnoughWordsForQuizError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.NOT_ENOUGH_WORDS_FOR_QUIZ}, true
+	case customerrors.ExpiredDownloadTokenError:
+		return errorMapping{status: http.StatusGone, code: customerrors.EXPIRED_DOWNLOAD_TOKEN}, true
+	case customerrors.InvalidRoleError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.INVALID_ROLE}, true
+	case customerrors.AdminAlreadyExistsError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.ADMIN_ALREADY_EXISTS}, true
+	case customerrors.LanguageAlreadyExistsError:
+		return errorMapping{status: http.StatusConflict, code: customerrors.LANGUAGE_ALREADY_EXISTS}, true
+	case customerrors.InvalidLanguageCodeError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.INVALID_LANGUAGE_CODE}, true
+	case customerrors.LanguageNotFoundError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.LANGUAGE_NOT_FOUND}, true
+	case customerrors.InvalidTimezoneError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.INVALID_TIMEZONE}, true
+	case customerrors.InvalidReminderTimeError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.INVALID_REMINDER_TIME}, true
+	case customerrors.InvalidUnsubscribeTokenError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.INVALID_UNSUBSCRIBE_TOKEN}, true
+	case customerrors.DictionaryUnavailableError:
+		return errorMapping{status: http.StatusServiceUnavailable, code: customerrors.DICTIONARY_UNAVAILABLE}, true
+	case customerrors.InvalidSyncCursorError:
+		return errorMapping{status: http.StatusBadRequest, code: customerrors.INVALID_SYNC_CURSOR}, true
+	case customerrors.PreconditionRequiredError:
+		return errorMapping{status: http.StatusPreconditionRequired, code: customerrors.PRECONDITION_REQUIRED}, true
+	case customerrors.StaleVersionError:
+		return errorMapping{status: http.StatusPreconditionFailed, code: customerrors.STALE_VERSION, data: e.Current}, true
+	default:
+		return errorMapping{}, false
+	}
+}
+
+// ErrorCode returns the code an error is answered with, if it is not unexpected
+func ErrorCode(err error) (string, bool) {
+	mapping, ok := mapError(err)
+	return mapping.code, ok
+}
+
+// Error returns the response of an error of a service. The unexpected ones are logged, and answered with a
+// generic message with the request ID, to not leak their details while letting the logs be found from it
+func Error(ctx context.Context, err error) HttpResponse {
+	requestId, _ := ctx.Value(RequestIdKey).(string)
+	mapping, ok := mapError(err)
+	if !ok {
+		log.Error().Ctx(ctx).Err(err).Str("request_id", requestId).Msg("Unexpected error")
+		return HttpResponse{Status: http.StatusInternalServerError, Body: ResponseBody{
+			ErrorMessage: GetStringPointer("Internal server error, request ID: " + requestId),
+			ErrorCode:    GetStringPointer(customerrors.INTERNAL_ERROR), RequestId: requestId}}
+	}
+	message := mapping.message
+	if message == "" {
+		message = err.Error()
+	}
+	return HttpResponse{Status: mapping.status, Body: ResponseBody{Data: mapping.data, ErrorMessage: &message,
+		ErrorCode: &mapping.code, RequestId: requestId}}
+}
+
+// InvalidRequest returns the response of a request whose body or parameters can't be bound
+func InvalidRequest(ctx context.Context, err error) HttpResponse {
+	requestId, _ := ctx.Value(RequestIdKey).(string)
+	return HttpResponse{Status: http.StatusBadRequest, Body: ResponseBody{ErrorMessage: GetStringPointer(err.Error()),
+		ErrorCode: GetStringPointer(customerrors.INVALID_REQUEST), RequestId: requestId}}
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag is the entity tag of a version of a resource
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatchVersions returns the versions of the tags of an If-Match header. It returns nil for *, as it doesn't name
+// a version. The weak tags and the ones of other resources never match, so they are returned as -1
+func IfMatchVersions(header string) []int {
+	if strings.TrimSpace(header) == "*" {
+		return nil
+	}
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		version, err := strconv.Atoi(strings.Trim(strings.TrimSpace(tag), `"`))
+		if err != nil || !strings.HasPrefix(strings.TrimSpace(tag), `"`) {
+			version = -1
+		}
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+// IfNoneMatch checks if an If-None-Match header matches the tag, comparing the weak tags as the strong ones
+func IfNoneMatch(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "vocablo/conf"
+
 func GetStringPointer(s string) *string {
 	return &s
 }
@@ -11,3 +13,24 @@ func GetIntPointer(i int) *int {
 func GetBoolPointer(b bool) *bool {
 	return &b
 }
+
+// FrontUrl returns the url of the web app for the current environment, to build the links sent by email
+func FrontUrl() string {
+	if conf.Get().Env == "prod" {
+		return conf.Get().Prod.FrontUrl
+	}
+	return conf.Get().Dev.FrontUrl
+}
+
+// ApiUrl returns the public url of the API for the current environment, to build the links sent by email that
+// are handled by the API
+func ApiUrl() string {
+	envConf := conf.Get().Dev
+	if conf.Get().Env == "prod" {
+		envConf = conf.Get().Prod
+	}
+	if envConf.ApiUrl == "" {
+		return envConf.FrontUrl
+	}
+	return envConf.ApiUrl
+}
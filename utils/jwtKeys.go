@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"vocablo/conf"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+type jwtKey struct {
+	id         string
+	method     jwt.SigningMethod
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+}
+
+type jwtKeySet struct {
+	active *jwtKey
+	keys   map[string]*jwtKey
+}
+
+// JWK is the public part of a signing key, as published in the JWKS endpoint
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	keySetMu sync.RWMutex
+	keySet   *jwtKeySet
+)
+
+// SetupJwtKeys loads the signing keys from the configuration. Without keys, the tokens are signed with
+// HS256 and the JwtKey secret
+func SetupJwtKeys() error {
+	jwtConf := conf.Get().Jwt
+	newKeySet := &jwtKeySet{keys: map[string]*jwtKey{}}
+	for _, keyConf := range jwtConf.Keys {
+		key, err := loadJwtKey(keyConf)
+		if err != nil {
+			return fmt.Errorf("error loading the JWT key %s: %w", keyConf.Id, err)
+		}
+		newKeySet.keys[key.id] = key
+	}
+	if len(newKeySet.keys) > 0 {
+		activeKey, ok := newKeySet.keys[jwtConf.ActiveKey]
+		if !ok || activeKey.privateKey == nil {
+			return fmt.Errorf("the active JWT key %s doesn't exist or has no private key", jwtConf.ActiveKey)
+		}
+		newKeySet.active = activeKey
+	}
+	keySetMu.Lock()
+	defer keySetMu.Unlock()
+	keySet = newKeySet
+	return nil
+}
+
+// loadJwtKey reads a PKCS#8 RSA or Ed25519 private key, or only the PKIX public key for the keys that
+// are kept to verify the tokens signed before a rotation
+func loadJwtKey(keyConf conf.JwtKeyConf) (*jwtKey, error) {
+	if keyConf.Id == "" {
+		return nil, errors.New("the key has no id")
+	}
+	key := &jwtKey{id: keyConf.Id}
+	if keyConf.PrivateKeyFile != "" {
+		block, err := readPem(keyConf.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+		}
+		key.privateKey = privateKey
+		key.publicKey = privateKey.(interface{ Public() crypto.PublicKey }).Public()
+	} else if keyConf.PublicKeyFile != "" {
+		block, err := readPem(keyConf.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New("the key has no private or public key file")
+	}
+	switch key.publicKey.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+	return key, nil
+}
+
+func readPem(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM data found in " + path)
+	}
+	return block, nil
+}
+
+func getKeySet() *jwtKeySet {
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+	return keySet
+}
+
+// signToken signs the claims with the active key, adding its id in the kid header
+func signToken(claims jwt.Claims) (string, error) {
+	currentKeySet := getKeySet()
+	if currentKeySet == nil || currentKeySet.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(conf.Get().JwtKey))
+	}
+	token := jwt.NewWithClaims(currentKeySet.active.method, claims)
+	token.Header["kid"] = currentKeySet.active.id
+	return token.SignedString(currentKeySet.active.privateKey)
+}
+
+// parseToken verifies the token with the key of its kid. The tokens without kid are the ones signed
+// with the HS256 secret, which are only accepted while there is no active key, unless configured otherwise
+func parseToken(signedToken string, claims jwt.Claims) (*jwt.Token, error) {
+	currentKeySet := getKeySet()
+	return jwt.ParseWithClaims(signedToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, errors.New("unexpected signing method")
+			}
+			if currentKeySet != nil && currentKeySet.active != nil && !conf.Get().Jwt.AcceptSharedSecret {
+				return nil, errors.New("the shared secret is not accepted with asymmetric keys")
+			}
+			return []byte(conf.Get().JwtKey), nil
+		}
+		if currentKeySet == nil {
+			return nil, errors.New("unknown key " + kid)
+		}
+		key, ok := currentKeySet.keys[kid]
+		if !ok {
+			return nil, errors.New("unknown key " + kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.publicKey, nil
+	})
+}
+
+// GetJWKS returns the public keys that can verify our tokens
+func GetJWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	currentKeySet := getKeySet()
+	if currentKeySet == nil {
+		return jwks
+	}
+	for _, key := range currentKeySet.keys {
+		jwk := JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
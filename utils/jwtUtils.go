@@ -15,10 +15,12 @@ type JWTClaim struct {
 	Username string    `json:"username"`
 	Mail     string    `json:"mail"`
 	Csrf     string    `json:"csrf"`
+	// Must match the user session version, otherwise the token has been revoked
+	SessionVersion int `json:"sessionVersion"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(id string, mail string, username string, csrf string) (tokenString string, err error) {
+func GenerateJWT(id string, mail string, username string, csrf string, sessionVersion int) (tokenString string, err error) {
 	conf := conf.Get()
 	expirationTime := time.Now().Add(24 * time.Hour)
 	fmt.Println(expirationTime)
@@ -29,29 +31,21 @@ func GenerateJWT(id string, mail string, username string, csrf string) (tokenStr
 		issuer = conf.Prod.CookieHost
 	}
 	claims := &JWTClaim{
-		Id:       uuidId,
-		Mail:     mail,
-		Username: username,
-		Csrf:     csrf,
+		Id:             uuidId,
+		Mail:           mail,
+		Username:       username,
+		Csrf:           csrf,
+		SessionVersion: sessionVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    issuer,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err = token.SignedString([]byte(conf.JwtKey))
+	tokenString, err = signToken(claims)
 	return
 }
 func ValidateToken(signedToken string) (tokenClaims JWTClaim, err error) {
-	conf := conf.Get()
-
-	token, err := jwt.ParseWithClaims(
-		signedToken,
-		&JWTClaim{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(conf.JwtKey), nil
-		},
-	)
+	token, err := parseToken(signedToken, &JWTClaim{})
 	if err != nil {
 		return
 	}
@@ -66,3 +60,102 @@ func ValidateToken(signedToken string) (tokenClaims JWTClaim, err error) {
 	}
 	return *claims, nil
 }
+
+// OIDCStateClaim holds the data needed to finish an OIDC authorization code flow. It is stored
+// signed in a short-lived cookie so the callback can be matched with the browser that started it.
+type OIDCStateClaim struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+func GenerateOIDCStateJWT(provider string, state string, nonce string, verifier string) (string, error) {
+	claims := &OIDCStateClaim{
+		Provider: provider,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(10 * time.Minute)),
+		},
+	}
+	return signToken(claims)
+}
+
+func ValidateOIDCStateToken(signedToken string) (*OIDCStateClaim, error) {
+	token, err := parseToken(signedToken, &OIDCStateClaim{})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*OIDCStateClaim)
+	if !ok {
+		return nil, errors.New("couldn't parse claims")
+	}
+	return claims, nil
+}
+
+// MagicLinkClaim is the token sent in a login link. The nonce is the hash of the one stored in the browser
+// that requested the link, so it can't be used from another one.
+type MagicLinkClaim struct {
+	Nonce string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+func GenerateMagicLinkJWT(codeId string, username string, hashedNonce string, ttl time.Duration) (string, error) {
+	claims := &MagicLinkClaim{
+		Nonce: hashedNonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        codeId,
+			Subject:   username,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+	return signToken(claims)
+}
+
+func ValidateMagicLinkToken(signedToken string) (*MagicLinkClaim, error) {
+	token, err := parseToken(signedToken, &MagicLinkClaim{})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*MagicLinkClaim)
+	if !ok {
+		return nil, errors.New("couldn't parse claims")
+	}
+	return claims, nil
+}
+
+const unsubscribeAudience = "unsubscribe"
+
+// UnsubscribeClaim is the token of the one-click unsubscribe links of the emails. List is the kind of emails
+// to stop, e.g. the study reminders
+type UnsubscribeClaim struct {
+	List string `json:"list"`
+	jwt.RegisteredClaims
+}
+
+func GenerateUnsubscribeJWT(userId string, list string, ttl time.Duration) (string, error) {
+	claims := &UnsubscribeClaim{
+		List: list,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userId,
+			Audience:  jwt.ClaimStrings{unsubscribeAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+	return signToken(claims)
+}
+
+func ValidateUnsubscribeToken(signedToken string) (*UnsubscribeClaim, error) {
+	token, err := parseToken(signedToken, &UnsubscribeClaim{})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*UnsubscribeClaim)
+	if !ok || !claims.VerifyAudience(unsubscribeAudience, true) {
+		return nil, errors.New("couldn't parse claims")
+	}
+	return claims, nil
+}
@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"fmt"
 	"net/http"
 )
 
@@ -9,6 +8,8 @@ type ResponseBody struct {
 	Data         interface{} `json:"data"`
 	ErrorMessage *string     `json:"errorMessage"`
 	ErrorCode    *string     `json:"errorCode"`
+	// RequestId is set in the errors, to find their logs
+	RequestId string `json:"requestId,omitempty"`
 }
 
 type HttpResponse struct {
@@ -24,10 +25,7 @@ func ErrorResponse(status int, errorMessage *string, errorCode *string) HttpResp
 	return HttpResponse{Status: status, Body: ResponseBody{ErrorMessage: errorMessage, ErrorCode: errorCode}}
 }
 
-func InternalError(err error) HttpResponse {
-	return ErrorResponse(http.StatusInternalServerError, GetStringPointer(err.Error()), nil)
-}
-
-func NotFound(entity string, entityId string) HttpResponse {
-	return HttpResponse{Status: http.StatusNotFound, Body: ResponseBody{ErrorMessage: GetStringPointer(fmt.Sprintf("%s %s not found", entity, entityId))}}
+// ErrorResponseWithData returns an error with details about it, e.g. the failed validations
+func ErrorResponseWithData(status int, errorMessage *string, errorCode *string, data interface{}) HttpResponse {
+	return HttpResponse{Status: status, Body: ResponseBody{Data: data, ErrorMessage: errorMessage, ErrorCode: errorCode}}
 }
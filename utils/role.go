@@ -0,0 +1,6 @@
+package utils
+
+const (
+	USER_ROLE  = "user"
+	ADMIN_ROLE = "admin"
+)
@@ -3,4 +3,6 @@ package utils
 const (
 	VALIDATION_TYPE = "validate_account"
 	RESET_TYPE      = "reset_password"
+	EMAIL_TYPE      = "change_email"
+	MAGIC_LINK_TYPE = "magic_link"
 )